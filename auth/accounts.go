@@ -0,0 +1,163 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// passwordIterations is the amount of PBKDF2 iterations passwords are hashed with.
+const passwordIterations = 100000
+
+var (
+	// AlreadyRegistered gets returned when registering a player that already has a password.
+	AlreadyRegistered = errors.New("already registered")
+	// NotRegistered gets returned when logging in as a player that has no password.
+	NotRegistered = errors.New("not registered")
+	// WrongPassword gets returned when logging in with a wrong password.
+	WrongPassword = errors.New("wrong password")
+)
+
+// account is a single account, as persisted in the accounts file.
+type account struct {
+	Name string `json:"name"`
+	Salt string `json:"salt"`
+	Hash string `json:"hash"`
+}
+
+// Accounts is a store of the passwords of offline players, persisted in a JSON file.
+// Passwords are stored as salted PBKDF2-SHA256 hashes.
+type Accounts struct {
+	path                  string
+	minimumPasswordLength int
+
+	mutex    sync.RWMutex
+	accounts map[string]account
+}
+
+// NewAccounts returns a new account store persisted in the file at the path.
+// Accounts are loaded from the file if it exists.
+func NewAccounts(path string, minimumPasswordLength int) (*Accounts, error) {
+	var accounts = &Accounts{path: path, minimumPasswordLength: minimumPasswordLength, accounts: make(map[string]account)}
+	var data, err = ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return accounts, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var list []account
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+	for _, entry := range list {
+		accounts.accounts[strings.ToLower(entry.Name)] = entry
+	}
+	return accounts, nil
+}
+
+// IsRegistered checks if a player with the given name has registered a password.
+func (accounts *Accounts) IsRegistered(name string) bool {
+	accounts.mutex.RLock()
+	defer accounts.mutex.RUnlock()
+	var _, ok = accounts.accounts[strings.ToLower(name)]
+	return ok
+}
+
+// Register registers the password for the player with the given name.
+// AlreadyRegistered is returned if the player already has a password.
+func (accounts *Accounts) Register(name string, password string) error {
+	if len(password) < accounts.minimumPasswordLength {
+		return fmt.Errorf("password must be at least %v characters long", accounts.minimumPasswordLength)
+	}
+	var salt = make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+
+	accounts.mutex.Lock()
+	defer accounts.mutex.Unlock()
+	if _, ok := accounts.accounts[strings.ToLower(name)]; ok {
+		return AlreadyRegistered
+	}
+	accounts.accounts[strings.ToLower(name)] = account{
+		Name: name,
+		Salt: base64.StdEncoding.EncodeToString(salt),
+		Hash: base64.StdEncoding.EncodeToString(hashPassword(password, salt, passwordIterations)),
+	}
+	return accounts.save()
+}
+
+// Check checks the password of the player with the given name.
+// NotRegistered is returned if the player has no password, and WrongPassword if the password is wrong.
+func (accounts *Accounts) Check(name string, password string) error {
+	accounts.mutex.RLock()
+	var entry, ok = accounts.accounts[strings.ToLower(name)]
+	accounts.mutex.RUnlock()
+	if !ok {
+		return NotRegistered
+	}
+	salt, err := base64.StdEncoding.DecodeString(entry.Salt)
+	if err != nil {
+		return err
+	}
+	hash, err := base64.StdEncoding.DecodeString(entry.Hash)
+	if err != nil {
+		return err
+	}
+	if subtle.ConstantTimeCompare(hash, hashPassword(password, salt, passwordIterations)) != 1 {
+		return WrongPassword
+	}
+	return nil
+}
+
+// save writes all accounts to the accounts file.
+func (accounts *Accounts) save() error {
+	var list = make([]account, 0, len(accounts.accounts))
+	for _, entry := range accounts.accounts {
+		list = append(list, entry)
+	}
+	var data, err = json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(accounts.path), 0700); err != nil {
+		return err
+	}
+	var temp = accounts.path + ".tmp"
+	if err := ioutil.WriteFile(temp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(temp, accounts.path)
+}
+
+// hashPassword hashes the password with the salt, using PBKDF2 with HMAC-SHA256.
+// A single block is derived, resulting in a 32 byte hash.
+func hashPassword(password string, salt []byte, iterations int) []byte {
+	var mac = hmac.New(sha256.New, []byte(password))
+	mac.Write(salt)
+	mac.Write([]byte{0, 0, 0, 1})
+	var u = mac.Sum(nil)
+	var result = make([]byte, len(u))
+	copy(result, u)
+
+	for i := 1; i < iterations; i++ {
+		mac.Reset()
+		mac.Write(u)
+		u = mac.Sum(u[:0])
+		for j := range result {
+			result[j] ^= u[j]
+		}
+	}
+	return result
+}
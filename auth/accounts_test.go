@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"encoding/hex"
+	"path/filepath"
+	"testing"
+)
+
+func TestHashPassword(t *testing.T) {
+	// Test vectors of PBKDF2-HMAC-SHA256 from RFC 7914.
+	var vectors = map[int]string{
+		1:    "120fb6cffcf8b32c43e7225256c4f837a86548c92ccc35480805987cb70be17b",
+		4096: "c5e478d59288c841aa530db6845c4c8d962893a001ce4e11a4963873aa98134a",
+	}
+	for iterations, expected := range vectors {
+		if hash := hex.EncodeToString(hashPassword("password", []byte("salt"), iterations)); hash != expected {
+			t.Errorf("%v iterations: got %v, expected %v", iterations, hash, expected)
+		}
+	}
+}
+
+func TestAccounts(t *testing.T) {
+	var path = filepath.Join(t.TempDir(), "accounts.json")
+	var accounts, err = NewAccounts(path, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := accounts.Register("Steve", "short"); err == nil {
+		t.Fatal("registered a password shorter than the minimum length")
+	}
+	if err := accounts.Register("Steve", "hunter22"); err != nil {
+		t.Fatal(err)
+	}
+	if err := accounts.Register("steve", "another"); err != AlreadyRegistered {
+		t.Fatalf("registering twice returned %v, expected %v", err, AlreadyRegistered)
+	}
+
+	accounts, err = NewAccounts(path, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !accounts.IsRegistered("STEVE") {
+		t.Fatal("account did not persist")
+	}
+	if err := accounts.Check("steve", "hunter22"); err != nil {
+		t.Fatalf("correct password was rejected: %v", err)
+	}
+	if err := accounts.Check("Steve", "hunter23"); err != WrongPassword {
+		t.Fatalf("wrong password returned %v, expected %v", err, WrongPassword)
+	}
+	if err := accounts.Check("Alex", "hunter22"); err != NotRegistered {
+		t.Fatalf("unknown player returned %v, expected %v", err, NotRegistered)
+	}
+}
+
+func TestOfflineIdentity(t *testing.T) {
+	if identity := OfflineIdentity("Notch"); identity != "42653081-a90e-3475-b3d6-3550cdb43f8e" {
+		t.Fatalf("got identity %v", identity)
+	}
+	if OfflineIdentity("notch") != OfflineIdentity("Notch") {
+		t.Fatal("identity depends on the case of the name")
+	}
+}
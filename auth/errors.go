@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// EmptyChain gets returned when a login contains no chains.
+	EmptyChain = errors.New("login chain is empty")
+	// MalformedToken gets returned when a JWT in the chain is not a valid JWT.
+	MalformedToken = errors.New("malformed token")
+	// MissingX5u gets returned when the header of a JWT has no x5u public key.
+	MissingX5u = errors.New("token has no x5u public key")
+	// UnsupportedAlgorithm gets returned when a JWT is not signed with ES384.
+	UnsupportedAlgorithm = errors.New("token is not signed with ES384")
+	// InvalidPublicKey gets returned when a public key is not a valid P-384 ECDSA public key.
+	InvalidPublicKey = errors.New("invalid public key")
+	// InvalidSignature gets returned when the signature of a JWT does not match its public key.
+	InvalidSignature = errors.New("invalid signature")
+	// BrokenChain gets returned when a JWT is not signed by the identity public key of the JWT before it.
+	BrokenChain = errors.New("token is not signed by the previous token in the chain")
+	// Expired gets returned when a JWT has expired.
+	Expired = errors.New("token has expired")
+	// NotYetValid gets returned when a JWT is not valid yet.
+	NotYetValid = errors.New("token is not valid yet")
+	// MissingIdentity gets returned when the last JWT in the chain has no identity data.
+	MissingIdentity = errors.New("chain has no identity data")
+	// IdentityMismatch gets returned when the identity of the chain does not match the identity claimed by the login.
+	IdentityMismatch = errors.New("identity does not match the login")
+)
+
+// ChainError is an error of a single JWT in the login chain.
+// Index is the index of the JWT in the chain, or -1 for the client data JWT.
+type ChainError struct {
+	Index int
+	Err   error
+}
+
+// Error returns the error message of the error, prefixed with the index of the failing JWT.
+func (err *ChainError) Error() string {
+	if err.Index == -1 {
+		return fmt.Sprintf("client data: %v", err.Err)
+	}
+	return fmt.Sprintf("chain %v: %v", err.Index, err.Err)
+}
+
+// Unwrap returns the underlying error of the failing JWT.
+func (err *ChainError) Unwrap() error {
+	return err.Err
+}
+
+// Cause returns the underlying error of err if it is a chain error, or err itself otherwise.
+func Cause(err error) error {
+	if chainError, ok := err.(*ChainError); ok {
+		return chainError.Err
+	}
+	return err
+}
@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"crypto/md5"
+	"fmt"
+	"strings"
+)
+
+// OfflineProvider authenticates players without XBOX Live, using passwords stored locally.
+// Players get a stable identity derived from their name, and have to log in with their password after joining.
+type OfflineProvider struct {
+	Verifier *Verifier
+	Accounts *Accounts
+}
+
+// NewOfflineProvider returns a new offline provider, with the accounts stored in the given file.
+func NewOfflineProvider(path string, minimumPasswordLength int) (*OfflineProvider, error) {
+	var accounts, err = NewAccounts(path, minimumPasswordLength)
+	if err != nil {
+		return nil, err
+	}
+	return &OfflineProvider{NewVerifier(), accounts}, nil
+}
+
+// Authenticate verifies the login chain of the player,
+// and replaces the identity claimed by the client with an identity derived from the name of the player.
+func (provider *OfflineProvider) Authenticate(login Login) (Identity, error) {
+	var identity, err = verify(provider.Verifier, login)
+	if err != nil {
+		return identity, err
+	}
+	identity.XUID = ""
+	identity.Identity = OfflineIdentity(identity.DisplayName)
+	identity.Authenticated = false
+	return identity, nil
+}
+
+// IsRegistered checks if a player with the given name has registered a password.
+func (provider *OfflineProvider) IsRegistered(name string) bool {
+	return provider.Accounts.IsRegistered(name)
+}
+
+// Register registers the password for the player with the given name.
+func (provider *OfflineProvider) Register(name string, password string) error {
+	return provider.Accounts.Register(name, password)
+}
+
+// Login checks the password of the player with the given name.
+func (provider *OfflineProvider) Login(name string, password string) error {
+	return provider.Accounts.Check(name, password)
+}
+
+// OfflineIdentity returns the identity UUID of an offline player with the given name.
+// The identity is a version 3 UUID of the lower case name, so that it is the same every time the player joins.
+func OfflineIdentity(name string) string {
+	var hash = md5.Sum([]byte("OfflinePlayer:" + strings.ToLower(name)))
+	hash[6] = hash[6]&0x0f | 0x30
+	hash[8] = hash[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", hash[0:4], hash[4:6], hash[6:8], hash[8:10], hash[10:16])
+}
@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"errors"
+	"net"
+	"time"
+)
+
+var (
+	// XboxLiveRequired gets returned when a player that is not logged into XBOX Live joins a server requiring XBOX Live.
+	XboxLiveRequired = errors.New("XBOX Live account required")
+	// UntrustedProxy gets returned when a login was not forwarded by the trusted proxy.
+	UntrustedProxy = errors.New("login was not forwarded by the trusted proxy")
+)
+
+// Login is the login of a player, as received by the server.
+// Claimed is the identity the player claims to have, which is verified by the provider.
+type Login struct {
+	Chain      []string
+	ClientData string
+	Claimed    ExtraData
+	Address    net.IP
+}
+
+// Provider authenticates players logging into the server.
+type Provider interface {
+	// Authenticate verifies the login of a player and returns its identity.
+	// An error is returned if the player may not join.
+	Authenticate(login Login) (Identity, error)
+}
+
+// PasswordProvider is a provider of which players have to log in with a password after joining.
+// Players are not allowed to play until they logged in or registered.
+type PasswordProvider interface {
+	Provider
+	// IsRegistered checks if a player with the given name has registered a password.
+	IsRegistered(name string) bool
+	// Register registers the password for the player with the given name.
+	Register(name string, password string) error
+	// Login checks the password of the player with the given name.
+	Login(name string, password string) error
+}
+
+// verify verifies the chain and client data of the login with the verifier.
+func verify(verifier *Verifier, login Login) (Identity, error) {
+	var identity, err = verifier.Verify(login.Chain, login.Claimed)
+	if err != nil {
+		return identity, err
+	}
+	return identity, verifier.VerifyClientData(login.ClientData, identity)
+}
+
+// XboxLiveProvider authenticates players using their XBOX Live login chain.
+// Players that are not logged into XBOX Live may only join if XBOX Live is not required.
+type XboxLiveProvider struct {
+	Verifier *Verifier
+	Required bool
+}
+
+// NewXboxLiveProvider returns a new XBOX Live provider trusting the XBOX Live root key.
+func NewXboxLiveProvider(required bool) *XboxLiveProvider {
+	return &XboxLiveProvider{NewVerifier(), required}
+}
+
+// Authenticate verifies the login chain of the player,
+// and returns XboxLiveRequired if XBOX Live is required and the player is not logged in.
+func (provider *XboxLiveProvider) Authenticate(login Login) (Identity, error) {
+	var identity, err = verify(provider.Verifier, login)
+	if err != nil {
+		return identity, err
+	}
+	if provider.Required && !identity.Authenticated {
+		return identity, XboxLiveRequired
+	}
+	return identity, nil
+}
+
+// TrustedProxyProvider authenticates players forwarded by a proxy.
+// The proxy signs the login chain of every player with its key, vouching for their identity.
+type TrustedProxyProvider struct {
+	Verifier *Verifier
+}
+
+// NewTrustedProxyProvider returns a new trusted proxy provider trusting the base64 encoded public key of the proxy.
+func NewTrustedProxyProvider(key string) (*TrustedProxyProvider, error) {
+	if _, err := ParsePublicKey(key); err != nil {
+		return nil, err
+	}
+	return &TrustedProxyProvider{&Verifier{RootKey: key, Now: time.Now}}, nil
+}
+
+// Authenticate verifies the login chain of the player,
+// and returns UntrustedProxy if the chain was not signed by the proxy.
+func (provider *TrustedProxyProvider) Authenticate(login Login) (Identity, error) {
+	var identity, err = verify(provider.Verifier, login)
+	if err != nil {
+		return identity, err
+	}
+	if !identity.Authenticated {
+		return identity, UntrustedProxy
+	}
+	return identity, nil
+}
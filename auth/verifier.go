@@ -0,0 +1,217 @@
+// Package auth implements the verification of the JWT chains sent by clients when logging in.
+// A chain signed by the XBOX Live root key authenticates a player, while self-signed chains
+// are accepted without authentication.
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/irmine/gomine/net/packets/data"
+)
+
+// Header is the header of a JWT in the login chain.
+type Header struct {
+	Algorithm string `json:"alg"`
+	X5u       string `json:"x5u"`
+}
+
+// ExtraData is the identity of the player carried by the last JWT in the login chain.
+type ExtraData struct {
+	XUID        string `json:"XUID"`
+	DisplayName string `json:"displayName"`
+	Identity    string `json:"identity"`
+}
+
+// Payload is the payload of a JWT in the login chain.
+type Payload struct {
+	CertificateAuthority bool       `json:"certificateAuthority"`
+	IdentityPublicKey    string     `json:"identityPublicKey"`
+	ExtraData            *ExtraData `json:"extraData"`
+	ExpirationTime       int64      `json:"exp"`
+	NotBefore            int64      `json:"nbf"`
+	IssuedAt             int64      `json:"iat"`
+}
+
+// Identity is the verified identity of a player logging in.
+// PublicKey is the identity public key of the client, used for encryption and to verify the client data.
+// Authenticated is true if the chain was signed by the root key.
+type Identity struct {
+	ExtraData
+	PublicKey     *ecdsa.PublicKey
+	Authenticated bool
+}
+
+// Verifier verifies login chains.
+// RootKey is the base64 encoded public key trusted to authenticate players, by default the XBOX Live root key.
+// Now returns the time tokens are checked against, by default the current time.
+type Verifier struct {
+	RootKey string
+	Now     func() time.Time
+}
+
+// NewVerifier returns a new verifier trusting the XBOX Live root key.
+func NewVerifier() *Verifier {
+	return &Verifier{RootKey: data.MojangPublicKey, Now: time.Now}
+}
+
+// Verify verifies the login chain and returns the identity of the player.
+// Every JWT in the chain must be signed by the identity public key of the JWT before it,
+// except for the first, which may be signed by any key.
+// The identity of the last JWT in the chain must match the claimed identity of the login.
+// The XUID of the returned identity is only set if the chain was signed by the root key.
+// A *ChainError is returned if any JWT in the chain is invalid.
+func (verifier *Verifier) Verify(chain []string, claimed ExtraData) (Identity, error) {
+	var identity Identity
+	if len(chain) == 0 {
+		return identity, EmptyChain
+	}
+
+	var now = verifier.now()
+	var previousKey string
+	var payload Payload
+	for index, token := range chain {
+		var header Header
+		payload = Payload{}
+		if err := verifier.verifyToken(token, &header, &payload, now); err != nil {
+			return identity, &ChainError{index, err}
+		}
+		if index != 0 && header.X5u != previousKey {
+			return identity, &ChainError{index, BrokenChain}
+		}
+		if header.X5u == verifier.RootKey {
+			identity.Authenticated = true
+		}
+		previousKey = payload.IdentityPublicKey
+	}
+
+	var last = len(chain) - 1
+	if payload.ExtraData == nil || payload.ExtraData.DisplayName == "" {
+		return identity, &ChainError{last, MissingIdentity}
+	}
+	var key, err = ParsePublicKey(payload.IdentityPublicKey)
+	if err != nil {
+		return identity, &ChainError{last, err}
+	}
+	var extraData = *payload.ExtraData
+	if extraData.DisplayName != claimed.DisplayName || extraData.XUID != claimed.XUID || !strings.EqualFold(extraData.Identity, claimed.Identity) {
+		return identity, &ChainError{last, IdentityMismatch}
+	}
+	if !identity.Authenticated {
+		// XUIDs can only be trusted if they were issued by XBOX Live.
+		extraData.XUID = ""
+	}
+	identity.ExtraData = extraData
+	identity.PublicKey = key
+	return identity, nil
+}
+
+// VerifyClientData verifies the client data JWT of a login is signed by the identity public key of the player.
+func (verifier *Verifier) VerifyClientData(token string, identity Identity) error {
+	var header Header
+	var payload map[string]interface{}
+	if err := verifier.verifyToken(token, &header, &payload, verifier.now()); err != nil {
+		return &ChainError{-1, err}
+	}
+	var key, err = ParsePublicKey(header.X5u)
+	if err != nil || key.X.Cmp(identity.PublicKey.X) != 0 || key.Y.Cmp(identity.PublicKey.Y) != 0 {
+		return &ChainError{-1, BrokenChain}
+	}
+	return nil
+}
+
+// now returns the time tokens are checked against.
+func (verifier *Verifier) now() time.Time {
+	if verifier.Now == nil {
+		return time.Now()
+	}
+	return verifier.Now()
+}
+
+// verifyToken verifies the signature of the JWT with the key in its header,
+// decodes its header and payload, and checks if the token is valid at the given time.
+func (verifier *Verifier) verifyToken(token string, header *Header, payload interface{}, now time.Time) error {
+	var parts = strings.Split(token, ".")
+	if len(parts) != 3 {
+		return MalformedToken
+	}
+	if err := decodePart(parts[0], header); err != nil {
+		return err
+	}
+	if header.X5u == "" {
+		return MissingX5u
+	}
+	if header.Algorithm != "ES384" {
+		return UnsupportedAlgorithm
+	}
+	var key, err = ParsePublicKey(header.X5u)
+	if err != nil {
+		return err
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || len(signature) != 96 {
+		return InvalidSignature
+	}
+	var hash = sha512.New384()
+	hash.Write([]byte(parts[0] + "." + parts[1]))
+	var r = new(big.Int).SetBytes(signature[:48])
+	var s = new(big.Int).SetBytes(signature[48:])
+	if !ecdsa.Verify(key, hash.Sum(nil), r, s) {
+		return InvalidSignature
+	}
+
+	if err := decodePart(parts[1], payload); err != nil {
+		return err
+	}
+	if p, ok := payload.(*Payload); ok {
+		if p.ExpirationTime != 0 && now.Unix() >= p.ExpirationTime {
+			return Expired
+		}
+		if p.NotBefore != 0 && now.Unix() < p.NotBefore {
+			return NotYetValid
+		}
+	}
+	return nil
+}
+
+// ParsePublicKey parses a base64 encoded DER public key, as found in the x5u of JWTs.
+// InvalidPublicKey is returned if the key is not a P-384 ECDSA public key.
+func ParsePublicKey(key string) (*ecdsa.PublicKey, error) {
+	var der, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(key, "="))
+	if err != nil {
+		return nil, InvalidPublicKey
+	}
+	parsed, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, InvalidPublicKey
+	}
+	var publicKey, ok = parsed.(*ecdsa.PublicKey)
+	if !ok || publicKey.Curve != elliptic.P384() {
+		return nil, InvalidPublicKey
+	}
+	return publicKey, nil
+}
+
+// decodePart decodes a base64 encoded JSON part of a JWT into v.
+func decodePart(part string, v interface{}) error {
+	var b, err = base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return MalformedToken
+	}
+	// Fields of a different type than expected are left empty,
+	// as clients are not consistent in the types of some fields.
+	if err := json.Unmarshal(b, v); err != nil {
+		if _, ok := err.(*json.UnmarshalTypeError); !ok {
+			return MalformedToken
+		}
+	}
+	return nil
+}
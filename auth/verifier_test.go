@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+var now = time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func newKey(t *testing.T) *ecdsa.PrivateKey {
+	var key, err = ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func encodeKey(key *ecdsa.PrivateKey) string {
+	var der, _ = x509.MarshalPKIXPublicKey(&key.PublicKey)
+	return base64.RawStdEncoding.EncodeToString(der)
+}
+
+// sign returns an ES384 JWT with the payload, signed by the key.
+func sign(key *ecdsa.PrivateKey, header Header, payload interface{}) string {
+	var headerData, _ = json.Marshal(header)
+	var payloadData, _ = json.Marshal(payload)
+	var content = base64.RawURLEncoding.EncodeToString(headerData) + "." + base64.RawURLEncoding.EncodeToString(payloadData)
+
+	var hash = sha512.Sum384([]byte(content))
+	var r, s, _ = ecdsa.Sign(rand.Reader, key, hash[:])
+	var signature = make([]byte, 96)
+	r.FillBytes(signature[:48])
+	s.FillBytes(signature[48:])
+	return content + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// link returns a JWT signed by the signer, authorising the next key.
+func link(signer *ecdsa.PrivateKey, next *ecdsa.PrivateKey, extraData *ExtraData) string {
+	return sign(signer, Header{"ES384", encodeKey(signer)}, Payload{
+		IdentityPublicKey: encodeKey(next),
+		ExtraData:         extraData,
+		NotBefore:         now.Add(-time.Hour).Unix(),
+		ExpirationTime:    now.Add(time.Hour).Unix(),
+		IssuedAt:          now.Add(-time.Hour).Unix(),
+	})
+}
+
+var steve = ExtraData{XUID: "2535400000000000", DisplayName: "Steve", Identity: "5e3f6a5c-6a5b-3a4e-9c0e-6a1e4f6b1c2d"}
+
+func TestVerify(t *testing.T) {
+	var root, intermediate, client = newKey(t), newKey(t), newKey(t)
+	var verifier = &Verifier{RootKey: encodeKey(root), Now: func() time.Time { return now }}
+
+	var authenticated = []string{link(client, root, nil), link(root, intermediate, nil), link(intermediate, client, &steve)}
+	var identity, err = verifier.Verify(authenticated, steve)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !identity.Authenticated || identity.ExtraData != steve || identity.PublicKey.X.Cmp(client.X) != 0 {
+		t.Fatalf("authenticated chain returned unexpected identity %+v", identity)
+	}
+
+	identity, err = verifier.Verify([]string{link(client, client, &steve)}, steve)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if identity.Authenticated || identity.XUID != "" || identity.DisplayName != "Steve" {
+		t.Fatalf("self-signed chain returned unexpected identity %+v", identity)
+	}
+	if err := verifier.VerifyClientData(link(client, client, nil), identity); err != nil {
+		t.Fatalf("client data signed by the client was rejected: %v", err)
+	}
+	if err := verifier.VerifyClientData(link(root, root, nil), identity); Cause(err) != BrokenChain {
+		t.Fatalf("client data signed by another key returned %v, expected %v", err, BrokenChain)
+	}
+}
+
+func TestVerifyFailures(t *testing.T) {
+	var root, intermediate, client, attacker = newKey(t), newKey(t), newKey(t), newKey(t)
+	var verifier = &Verifier{RootKey: encodeKey(root), Now: func() time.Time { return now }}
+
+	var tampered = link(client, client, &steve)
+	var parts = strings.Split(tampered, ".")
+	var alex = steve
+	alex.DisplayName = "Alex"
+	var alexPayload, _ = json.Marshal(Payload{IdentityPublicKey: encodeKey(client), ExtraData: &alex})
+	tampered = parts[0] + "." + base64.RawURLEncoding.EncodeToString(alexPayload) + "." + parts[2]
+
+	var tests = []struct {
+		name    string
+		chain   []string
+		claimed ExtraData
+		index   int
+		err     error
+	}{
+		{"empty", nil, steve, -1, EmptyChain},
+		{"malformed", []string{"not.a-jwt"}, steve, 0, MalformedToken},
+		{"missing x5u", []string{sign(client, Header{Algorithm: "ES384"}, Payload{ExtraData: &steve})}, steve, 0, MissingX5u},
+		{"wrong algorithm", []string{sign(client, Header{"ES256", encodeKey(client)}, Payload{ExtraData: &steve})}, steve, 0, UnsupportedAlgorithm},
+		{"invalid key", []string{sign(client, Header{"ES384", "AAAA"}, Payload{ExtraData: &steve})}, steve, 0, InvalidPublicKey},
+		{"tampered payload", []string{tampered}, alex, 0, InvalidSignature},
+		{"broken chain", []string{link(client, root, nil), link(attacker, intermediate, nil), link(intermediate, client, &steve)}, steve, 1, BrokenChain},
+		{"expired", []string{sign(client, Header{"ES384", encodeKey(client)}, Payload{IdentityPublicKey: encodeKey(client), ExtraData: &steve, ExpirationTime: now.Add(-time.Second).Unix()})}, steve, 0, Expired},
+		{"not yet valid", []string{sign(client, Header{"ES384", encodeKey(client)}, Payload{IdentityPublicKey: encodeKey(client), ExtraData: &steve, NotBefore: now.Add(time.Minute).Unix()})}, steve, 0, NotYetValid},
+		{"missing identity", []string{link(client, client, nil)}, steve, 0, MissingIdentity},
+		{"identity mismatch", []string{link(client, client, &steve)}, alex, 0, IdentityMismatch},
+	}
+	for _, test := range tests {
+		var _, err = verifier.Verify(test.chain, test.claimed)
+		if Cause(err) != test.err {
+			t.Errorf("%v: got error %v, expected %v", test.name, err, test.err)
+			continue
+		}
+		if chainError, ok := err.(*ChainError); ok && chainError.Index != test.index {
+			t.Errorf("%v: error at chain %v, expected chain %v", test.name, chainError.Index, test.index)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package gomine
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/irmine/gomine/auth"
+	"github.com/irmine/gomine/net"
+	"github.com/irmine/gomine/net/packets/data"
+	"github.com/irmine/gomine/text"
+)
+
+const (
+	// AuthXboxLive authenticates players with XBOX Live.
+	AuthXboxLive = "xbox-live"
+	// AuthOffline authenticates players with passwords stored on the server.
+	AuthOffline = "offline"
+	// AuthTrustedProxy authenticates players forwarded by a proxy holding the configured key.
+	AuthTrustedProxy = "trusted-proxy"
+)
+
+const (
+	// DefaultLoginTimeout is the default time in seconds players have to log in with their password after spawning.
+	DefaultLoginTimeout = 60
+	// DefaultMaximumLoginAttempts is the default amount of wrong passwords players may enter before being disconnected.
+	DefaultMaximumLoginAttempts = 3
+)
+
+// newAuthProvider returns the authentication provider configured in the authentication configuration of the server.
+func newAuthProvider(server *Server) (auth.Provider, error) {
+	var config = server.Config.Authentication
+	switch config.Provider {
+	case AuthXboxLive, "":
+		return auth.NewXboxLiveProvider(server.Config.XBOXLiveAuth), nil
+	case AuthOffline:
+		return auth.NewOfflineProvider(server.ServerPath+"accounts.json", config.MinimumPasswordLength)
+	case AuthTrustedProxy:
+		return auth.NewTrustedProxyProvider(config.ProxyKey)
+	}
+	return nil, fmt.Errorf("unknown authentication provider %q", config.Provider)
+}
+
+// passwordLogin is a session that has to log in with its password before it may play.
+// The login timeout starts once the player spawned.
+type passwordLogin struct {
+	session  *net.MinecraftSession
+	since    time.Time
+	attempts int
+}
+
+// passwordLogins keeps track of all sessions that have not yet logged in with their password.
+type passwordLogins struct {
+	mutex  sync.Mutex
+	logins map[net.Connection]*passwordLogin
+}
+
+// getPasswordProvider returns the authentication provider of the server if players log in with a password.
+// A bool is returned indicating if players log in with a password.
+func (server *Server) getPasswordProvider() (auth.PasswordProvider, bool) {
+	var provider, ok = server.AuthProvider.(auth.PasswordProvider)
+	return provider, ok
+}
+
+// requirePasswordLogin freezes the session until it logged in with its password,
+// if the authentication provider of the server requires passwords.
+func (server *Server) requirePasswordLogin(session *net.MinecraftSession) {
+	if _, ok := server.getPasswordProvider(); !ok {
+		return
+	}
+	server.passwordLogins.mutex.Lock()
+	server.passwordLogins.logins[session.GetConnection()] = &passwordLogin{session: session}
+	server.passwordLogins.mutex.Unlock()
+}
+
+// IsFrozen checks if the session still has to log in with its password.
+// Frozen sessions may not move, chat, or run any other command than /login and /register.
+func (server *Server) IsFrozen(session *net.MinecraftSession) bool {
+	server.passwordLogins.mutex.Lock()
+	defer server.passwordLogins.mutex.Unlock()
+	var _, ok = server.passwordLogins.logins[session.GetConnection()]
+	return ok
+}
+
+// unfreeze marks the session of the connection as logged in.
+func (server *Server) unfreeze(connection net.Connection) {
+	server.passwordLogins.mutex.Lock()
+	delete(server.passwordLogins.logins, connection)
+	server.passwordLogins.mutex.Unlock()
+}
+
+// promptPasswordLogin tells the frozen session to log in or register, and starts its login timeout.
+func (server *Server) promptPasswordLogin(session *net.MinecraftSession) {
+	var provider, ok = server.getPasswordProvider()
+	if !ok {
+		return
+	}
+	server.passwordLogins.mutex.Lock()
+	var login, frozen = server.passwordLogins.logins[session.GetConnection()]
+	if frozen && login.since.IsZero() {
+		login.since = time.Now()
+	}
+	server.passwordLogins.mutex.Unlock()
+	if !frozen {
+		return
+	}
+	if provider.IsRegistered(session.GetName()) {
+		session.SendMessage(text.Yellow + "Please log in using /login <password>.")
+	} else {
+		session.SendMessage(text.Yellow + "Please register using /register <password> <password>.")
+	}
+}
+
+// failPasswordLogin registers a wrong password entered by the session,
+// and disconnects the session once it entered too many wrong passwords.
+func (server *Server) failPasswordLogin(session *net.MinecraftSession) {
+	var maximum = server.Config.Authentication.MaximumLoginAttempts
+	if maximum <= 0 {
+		maximum = DefaultMaximumLoginAttempts
+	}
+	server.passwordLogins.mutex.Lock()
+	var login, ok = server.passwordLogins.logins[session.GetConnection()]
+	if ok {
+		login.attempts++
+	}
+	server.passwordLogins.mutex.Unlock()
+
+	if ok && login.attempts >= maximum {
+		server.Logger.Notice(session.GetName(), "entered too many wrong passwords.")
+		session.Kick("Too many failed login attempts.", false, false)
+	}
+}
+
+// tickPasswordLogins disconnects frozen sessions that did not log in within the login timeout.
+func (server *Server) tickPasswordLogins() {
+	var timeout = time.Duration(server.Config.Authentication.LoginTimeout) * time.Second
+	if timeout <= 0 {
+		timeout = DefaultLoginTimeout * time.Second
+	}
+	var expired []*net.MinecraftSession
+	server.passwordLogins.mutex.Lock()
+	for connection, login := range server.passwordLogins.logins {
+		if !login.since.IsZero() && time.Since(login.since) > timeout {
+			expired = append(expired, login.session)
+			delete(server.passwordLogins.logins, connection)
+		}
+	}
+	server.passwordLogins.mutex.Unlock()
+
+	for _, session := range expired {
+		session.Kick("Login timed out.", false, false)
+	}
+}
+
+// resetFrozenMovement moves the frozen session back to where it was, discarding its movement.
+func resetFrozenMovement(session *net.MinecraftSession) {
+	var player = session.GetPlayer()
+	session.SendMovePlayer(player.GetRuntimeId(), player.Position, player.Rotation, data.MoveReset, player.OnGround, player.GetRidingId())
+}
@@ -0,0 +1,163 @@
+package gomine
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/irmine/gomine/commands"
+	"github.com/irmine/gomine/commands/arguments"
+	"github.com/irmine/gomine/net"
+	"github.com/irmine/gomine/net/packets/data"
+	"github.com/irmine/gomine/net/packets/types"
+)
+
+// availableCommands keeps track of the commands last sent to every session,
+// so that they only get sent again once the commands a session may use changed.
+type availableCommands struct {
+	mutex sync.Mutex
+	sent  map[*net.MinecraftSession]string
+}
+
+// SendAvailableCommands sends all commands the session may use to the session,
+// so that they show up in the command autocompletion of the client.
+// Commands are sent again automatically once the permissions of the session change,
+// or commands get registered or deregistered.
+func (server *Server) SendAvailableCommands(session *net.MinecraftSession) {
+	var fingerprint = getCommandFingerprint(server.CommandManager, session)
+	server.availableCommands.mutex.Lock()
+	server.availableCommands.sent[session] = fingerprint
+	server.availableCommands.mutex.Unlock()
+
+	session.SendAvailableCommands(getCommandData(server.CommandManager, session))
+}
+
+// tickAvailableCommands sends the available commands again to every spawned session,
+// of which the commands it may use changed since they were last sent.
+func (server *Server) tickAvailableCommands() {
+	for _, session := range server.SessionManager.GetSessions() {
+		if !session.HasSpawned() {
+			continue
+		}
+		server.availableCommands.mutex.Lock()
+		var sent, ok = server.availableCommands.sent[session]
+		server.availableCommands.mutex.Unlock()
+
+		if !ok || sent != getCommandFingerprint(server.CommandManager, session) {
+			server.SendAvailableCommands(session)
+		}
+	}
+}
+
+// forgetAvailableCommands removes the commands last sent to the session.
+func (server *Server) forgetAvailableCommands(session *net.MinecraftSession) {
+	server.availableCommands.mutex.Lock()
+	delete(server.availableCommands.sent, session)
+	server.availableCommands.mutex.Unlock()
+}
+
+// getCommandFingerprint returns a string identifying the commands and subcommands the sender may use.
+// The fingerprint changes if the sender may use other commands, or commands got registered or deregistered.
+func getCommandFingerprint(manager *commands.Manager, sender commands.Sender) string {
+	var paths []string
+	for _, command := range manager.GetCommands() {
+		paths = appendExecutablePaths(paths, command, sender)
+	}
+	sort.Strings(paths)
+	return strconv.Itoa(manager.GetRevision()) + ":" + strings.Join(paths, ",")
+}
+
+// appendExecutablePaths appends the paths of the command and all its subcommands the sender may use.
+func appendExecutablePaths(paths []string, command *commands.Command, sender commands.Sender) []string {
+	if !command.CanExecute(sender) {
+		return paths
+	}
+	paths = append(paths, command.GetPath())
+	for _, subcommand := range command.GetSubcommands() {
+		paths = appendExecutablePaths(paths, subcommand, sender)
+	}
+	return paths
+}
+
+// getCommandData returns the data of all commands the sender may use, sorted by name.
+func getCommandData(manager *commands.Manager, sender commands.Sender) []types.CommandData {
+	var commandData = make([]types.CommandData, 0, len(manager.GetCommands()))
+	for _, command := range manager.GetCommands() {
+		if !command.CanExecute(sender) {
+			continue
+		}
+		var overloads = getCommandOverloads(command, sender, nil)
+		if len(overloads) == 0 {
+			continue
+		}
+		var d = types.CommandData{Name: command.GetName(), Description: command.GetDescription(), Overloads: overloads}
+		if len(command.GetAliases()) != 0 {
+			d.Aliases = &types.CommandEnum{Name: command.GetName() + "Aliases", Values: append([]string{command.GetName()}, command.GetAliases()...)}
+		}
+		commandData = append(commandData, d)
+	}
+	sort.Slice(commandData, func(i, j int) bool {
+		return commandData[i].Name < commandData[j].Name
+	})
+	return commandData
+}
+
+// getCommandOverloads returns the overloads of the command and of all its subcommands the sender may use.
+// The overloads of subcommands start with a parameter for the name of every subcommand in their path.
+func getCommandOverloads(command *commands.Command, sender commands.Sender, prefix []types.CommandParameter) []types.CommandOverload {
+	var overloads []types.CommandOverload
+	for _, overload := range command.GetOverloads() {
+		var parameters = append([]types.CommandParameter{}, prefix...)
+		for _, argument := range overload.GetArguments() {
+			parameters = append(parameters, getCommandParameter(argument))
+		}
+		overloads = append(overloads, types.CommandOverload{Parameters: parameters})
+	}
+	for _, subcommand := range command.GetSubcommands() {
+		if !subcommand.CanExecute(sender) {
+			continue
+		}
+		var parameter = types.CommandParameter{Name: subcommand.GetName(), Enum: &types.CommandEnum{
+			Name:   subcommand.GetPath(),
+			Values: append([]string{subcommand.GetName()}, subcommand.GetAliases()...),
+		}}
+		var parameters = append(append([]types.CommandParameter{}, prefix...), parameter)
+		overloads = append(overloads, getCommandOverloads(subcommand, sender, parameters)...)
+	}
+	return overloads
+}
+
+// getCommandParameter returns the parameter shown to clients for the argument.
+// Arguments with options are shown as enum, other arguments by the type of their value.
+func getCommandParameter(argument *arguments.Argument) types.CommandParameter {
+	var parameter = types.CommandParameter{Name: argument.GetName(), Optional: argument.IsOptional()}
+	if options := argument.GetOptions(); len(options) != 0 {
+		var name = argument.GetTypeName()
+		if name == "string" {
+			name = argument.GetName()
+		}
+		parameter.Enum = &types.CommandEnum{Name: name, Values: options}
+		return parameter
+	}
+	switch argument.GetTypeName() {
+	case "int", "int64":
+		parameter.Type = data.CommandArgInt
+	case "float64":
+		parameter.Type = data.CommandArgFloat
+	case "target", "player":
+		parameter.Type = data.CommandArgTarget
+	case "x y z":
+		parameter.Type = data.CommandArgPosition
+	case "json":
+		parameter.Type = data.CommandArgJson
+	case "rawtext":
+		parameter.Type = data.CommandArgRawText
+	default:
+		parameter.Type = data.CommandArgString
+		if argument.GetInputAmount() > 1 && argument.ShouldMerge() {
+			parameter.Type = data.CommandArgMessage
+		}
+	}
+	return parameter
+}
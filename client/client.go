@@ -0,0 +1,432 @@
+// Package client implements a headless Bedrock client, which logs in to a server the way a game client would.
+// Clients are used for integration tests, load testing bots and testing plugins without a game client.
+package client
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/golang/geo/r3"
+	"github.com/google/uuid"
+	"github.com/irmine/gomine/net"
+	"github.com/irmine/gomine/net/info"
+	"github.com/irmine/gomine/net/packets"
+	"github.com/irmine/gomine/net/packets/bedrock"
+	"github.com/irmine/gomine/net/packets/data"
+	"github.com/irmine/gomine/utils"
+	data2 "github.com/irmine/worlds/entities/data"
+)
+
+// Connection is a connection to a server that encoded batches are exchanged over.
+// Connections of the loopback transport implement Connection.
+type Connection interface {
+	// WriteBatch writes an encoded batch to the server.
+	WriteBatch(packet []byte) error
+	// ReadBatch reads an encoded batch sent by the server.
+	// ReadBatch returns io.EOF once the connection got closed.
+	ReadBatch() ([]byte, error)
+	// Close closes the connection.
+	Close() error
+}
+
+// Timeout gets returned by Join if the client did not spawn in time.
+var Timeout = errors.New("client did not spawn in time")
+
+// LoginFailed gets returned by Join if the server sent a failed login status.
+var LoginFailed = errors.New("login failed")
+
+// Disconnected is returned by Join if the client got disconnected before spawning.
+type Disconnected struct {
+	Reason string
+}
+
+// Error returns the reason of the disconnection.
+func (err Disconnected) Error() string {
+	return "disconnected: " + err.Reason
+}
+
+// Client is a headless Minecraft client.
+// Clients log in with a self-signed chain, complete the resource pack sequence,
+// request chunks and spawn, after which they can chat, run commands and move.
+type Client struct {
+	connection Connection
+	name       string
+	uuid       uuid.UUID
+	xuid       string
+	clientId   int
+	privateKey *ecdsa.PrivateKey
+
+	protocolNumber int32
+	viewDistance   int32
+	packets        map[int]func() packets.IPacket
+
+	encryptionHandler *utils.EncryptionHandler
+	usesEncryption    bool
+	sendMutex         sync.Mutex
+
+	mutex     sync.RWMutex
+	runtimeId uint64
+	position  r3.Vector
+	rotation  data2.Rotation
+	err       error
+
+	spawned   chan struct{}
+	spawnOnce sync.Once
+	closed    chan struct{}
+	closeOnce sync.Once
+
+	// PacketFunction gets called for every packet received, before the packet gets handled by the client.
+	PacketFunction func(packet packets.IPacket)
+	// TextFunction gets called for every text packet received.
+	TextFunction func(packet *bedrock.TextPacket)
+	// ChunkFunction gets called for every chunk received.
+	ChunkFunction func(packet *bedrock.FullChunkDataPacket)
+	// MoveFunction gets called for every movement received, including movement of the client itself.
+	MoveFunction func(packet *bedrock.MovePlayerPacket)
+	// DisconnectFunction gets called once the client got disconnected by the server.
+	DisconnectFunction func(reason string)
+}
+
+// New returns a new client with the given name, which communicates over the given connection.
+// A new key pair is generated for the self-signed login chain of the client.
+func New(name string, connection Connection) (*Client, error) {
+	var key, err = ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	clientId, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	if err != nil {
+		return nil, err
+	}
+
+	var ids = info.PacketIds
+	return &Client{
+		connection:     connection,
+		name:           name,
+		uuid:           uuid.New(),
+		clientId:       int(clientId.Int64()),
+		privateKey:     key,
+		protocolNumber: info.LatestProtocol,
+		viewDistance:   4,
+		packets: map[int]func() packets.IPacket{
+			ids[info.PlayStatusPacket]:         func() packets.IPacket { return bedrock.NewPlayStatusPacket() },
+			ids[info.ServerHandshakePacket]:    func() packets.IPacket { return bedrock.NewServerHandshakePacket() },
+			ids[info.DisconnectPacket]:         func() packets.IPacket { return bedrock.NewDisconnectPacket() },
+			ids[info.ResourcePackInfoPacket]:   func() packets.IPacket { return bedrock.NewResourcePackInfoPacket() },
+			ids[info.ResourcePackStackPacket]:  func() packets.IPacket { return bedrock.NewResourcePackStackPacket() },
+			ids[info.StartGamePacket]:          func() packets.IPacket { return bedrock.NewStartGamePacket() },
+			ids[info.ChunkRadiusUpdatedPacket]: func() packets.IPacket { return bedrock.NewChunkRadiusUpdatedPacket() },
+			ids[info.FullChunkDataPacket]:      func() packets.IPacket { return bedrock.NewFullChunkDataPacket() },
+			ids[info.TextPacket]:               func() packets.IPacket { return bedrock.NewTextPacket() },
+			ids[info.MovePlayerPacket]:         func() packets.IPacket { return bedrock.NewMovePlayerPacket() },
+			ids[info.TransferPacket]:           func() packets.IPacket { return bedrock.NewTransferPacket() },
+		},
+		encryptionHandler: utils.NewEncryptionHandler(),
+		spawned:           make(chan struct{}),
+		closed:            make(chan struct{}),
+	}, nil
+}
+
+// GetName returns the name the client logs in with.
+func (client *Client) GetName() string {
+	return client.name
+}
+
+// GetUUID returns the UUID the client logs in with.
+func (client *Client) GetUUID() uuid.UUID {
+	return client.uuid
+}
+
+// SetXUID sets the XUID the client logs in with.
+// This must be set before joining.
+func (client *Client) SetXUID(xuid string) {
+	client.xuid = xuid
+}
+
+// SetViewDistance sets the chunk radius the client requests after the game started.
+// This must be set before joining.
+func (client *Client) SetViewDistance(distance int32) {
+	client.viewDistance = distance
+}
+
+// GetRuntimeId returns the runtime ID of the player of the client, as sent in the start game packet.
+func (client *Client) GetRuntimeId() uint64 {
+	client.mutex.RLock()
+	defer client.mutex.RUnlock()
+	return client.runtimeId
+}
+
+// GetPosition returns the last known position of the client.
+func (client *Client) GetPosition() r3.Vector {
+	client.mutex.RLock()
+	defer client.mutex.RUnlock()
+	return client.position
+}
+
+// RegisterPacket registers a packet the client should decode, in addition to the default packets.
+func (client *Client) RegisterPacket(packetId int, packetFunc func() packets.IPacket) {
+	client.packets[packetId] = packetFunc
+}
+
+// IsPacketRegistered checks if the client decodes packets with the given ID.
+func (client *Client) IsPacketRegistered(packetId int) bool {
+	var _, ok = client.packets[packetId]
+	return ok
+}
+
+// GetPacket returns a new packet with the given ID.
+func (client *Client) GetPacket(packetId int) packets.IPacket {
+	return client.packets[packetId]()
+}
+
+// UsesEncryption checks if the batches of the client are encrypted.
+func (client *Client) UsesEncryption() bool {
+	return client.usesEncryption
+}
+
+// GetEncryptionHandler returns the encryption handler of the client.
+func (client *Client) GetEncryptionHandler() *utils.EncryptionHandler {
+	return client.encryptionHandler
+}
+
+// Join logs in to the server and waits until the client spawned, or the timeout passed.
+// Packets keep getting processed in the background after joining, until the client gets closed.
+func (client *Client) Join(timeout time.Duration) error {
+	go client.process()
+
+	var login = bedrock.NewLoginPacket()
+	login.Protocol = client.protocolNumber
+	login.RawChains = NewSelfSignedChain(client.privateKey, client.name, client.uuid, client.xuid)
+	login.RawClientData = NewClientData(client.privateKey, "", client.clientId)
+	if err := client.SendPacket(login); err != nil {
+		return err
+	}
+
+	select {
+	case <-client.spawned:
+		return nil
+	case <-client.closed:
+		return client.getError()
+	case <-time.After(timeout):
+		return Timeout
+	}
+}
+
+// Done returns a channel that gets closed once the client is closed.
+func (client *Client) Done() <-chan struct{} {
+	return client.closed
+}
+
+// Close closes the connection of the client.
+func (client *Client) Close() error {
+	client.close(nil)
+	return nil
+}
+
+// SendPacket sends a packet to the server in its own batch.
+func (client *Client) SendPacket(packet packets.IPacket) error {
+	client.sendMutex.Lock()
+	defer client.sendMutex.Unlock()
+
+	var batch = net.NewEndpointBatch(client)
+	batch.AddPacket(packet)
+	batch.Encode()
+
+	return client.connection.WriteBatch(batch.Buffer)
+}
+
+// SendChat sends a chat message to the server.
+func (client *Client) SendChat(message string) error {
+	var pk = bedrock.NewTextPacket()
+	pk.TextType = data.TextChat
+	pk.SourceName = client.name
+	pk.Message = message
+	pk.XUID = client.xuid
+
+	return client.SendPacket(pk)
+}
+
+// SendCommand sends a command to the server. The command may be prefixed with a slash.
+func (client *Client) SendCommand(command string) error {
+	var pk = bedrock.NewCommandRequestPacket()
+	pk.CommandText = command
+	pk.UUID = client.uuid
+
+	return client.SendPacket(pk)
+}
+
+// Move moves the client to the given position with the given rotation.
+func (client *Client) Move(position r3.Vector, rotation data2.Rotation, onGround bool) error {
+	client.mutex.Lock()
+	client.position = position
+	client.rotation = rotation
+	client.mutex.Unlock()
+
+	var pk = bedrock.NewMovePlayerPacket()
+	pk.RuntimeId = client.GetRuntimeId()
+	pk.Position = position
+	pk.Rotation = rotation
+	pk.Mode = data.MoveNormal
+	pk.OnGround = onGround
+
+	return client.SendPacket(pk)
+}
+
+// process reads and handles batches until the connection gets closed.
+func (client *Client) process() {
+	for {
+		var buffer, err = client.connection.ReadBatch()
+		if err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+			client.close(err)
+			return
+		}
+
+		var batch = net.NewEndpointBatch(client)
+		batch.Buffer = buffer
+		if err := batch.Decode(); err != nil {
+			client.close(err)
+			return
+		}
+		for _, packet := range batch.GetPackets() {
+			if err := packet.DecodeHeader(); err != nil {
+				client.close(fmt.Errorf("decoding packet 0x%02x: %v", packet.GetId(), err))
+				return
+			}
+			if err := packet.Decode(); err != nil {
+				client.close(fmt.Errorf("decoding packet 0x%02x: %v", packet.GetId(), err))
+				return
+			}
+
+			client.handlePacket(packet)
+		}
+	}
+}
+
+// handlePacket handles a single packet received from the server.
+func (client *Client) handlePacket(packet packets.IPacket) {
+	if client.PacketFunction != nil {
+		client.PacketFunction(packet)
+	}
+
+	switch pk := packet.(type) {
+	case *bedrock.ServerHandshakePacket:
+		if err := client.enableEncryption(pk.Jwt); err != nil {
+			client.close(err)
+			return
+		}
+		client.SendPacket(bedrock.NewClientHandshakePacket())
+
+	case *bedrock.PlayStatusPacket:
+		switch pk.Status {
+		case data.StatusLoginSuccess:
+		case data.StatusSpawn:
+			client.spawnOnce.Do(func() {
+				close(client.spawned)
+			})
+		default:
+			client.close(LoginFailed)
+		}
+
+	case *bedrock.ResourcePackInfoPacket:
+		var response = bedrock.NewResourcePackClientResponsePacket()
+		response.Status = data.StatusHaveAllPacks
+		client.SendPacket(response)
+
+	case *bedrock.ResourcePackStackPacket:
+		var response = bedrock.NewResourcePackClientResponsePacket()
+		response.Status = data.StatusCompleted
+		client.SendPacket(response)
+
+	case *bedrock.StartGamePacket:
+		client.mutex.Lock()
+		client.runtimeId = pk.EntityRuntimeId
+		client.position = pk.PlayerPosition
+		client.rotation = data2.Rotation{Pitch: float64(pk.Pitch), Yaw: float64(pk.Yaw), HeadYaw: float64(pk.Yaw)}
+		client.mutex.Unlock()
+
+		var request = bedrock.NewRequestChunkRadiusPacket()
+		request.Radius = client.viewDistance
+		client.SendPacket(request)
+
+	case *bedrock.FullChunkDataPacket:
+		if client.ChunkFunction != nil {
+			client.ChunkFunction(pk)
+		}
+
+	case *bedrock.TextPacket:
+		if client.TextFunction != nil {
+			client.TextFunction(pk)
+		}
+
+	case *bedrock.MovePlayerPacket:
+		if pk.RuntimeId == client.GetRuntimeId() {
+			client.mutex.Lock()
+			client.position = pk.Position
+			client.rotation = pk.Rotation
+			client.mutex.Unlock()
+		}
+		if client.MoveFunction != nil {
+			client.MoveFunction(pk)
+		}
+
+	case *bedrock.DisconnectPacket:
+		client.close(Disconnected{pk.Message})
+		if client.DisconnectFunction != nil {
+			client.DisconnectFunction(pk.Message)
+		}
+	}
+}
+
+// enableEncryption enables encryption with the key and salt of the server handshake.
+// The shared secret is computed the same way the server computes it,
+// with the public key of the server in place of the public key of the client.
+func (client *Client) enableEncryption(jwt string) error {
+	var serverKey, token, err = parseHandshakeJwt(jwt)
+	if err != nil {
+		return err
+	}
+	client.sendMutex.Lock()
+	defer client.sendMutex.Unlock()
+
+	client.encryptionHandler.Data = &utils.EncryptionData{
+		ClientPublicKey:  serverKey,
+		ServerPrivateKey: client.privateKey,
+		ServerToken:      token,
+	}
+	client.encryptionHandler.Data.ComputeSharedSecret()
+	client.encryptionHandler.Data.ComputeSecretKeyBytes()
+	client.usesEncryption = true
+	return nil
+}
+
+// getError returns the error the client got closed with.
+func (client *Client) getError() error {
+	client.mutex.RLock()
+	defer client.mutex.RUnlock()
+	if client.err == nil {
+		return Disconnected{"connection closed"}
+	}
+	return client.err
+}
+
+// close closes the client with the given error, if it was not closed yet.
+func (client *Client) close(err error) {
+	client.closeOnce.Do(func() {
+		client.mutex.Lock()
+		client.err = err
+		client.mutex.Unlock()
+
+		close(client.closed)
+		client.connection.Close()
+	})
+}
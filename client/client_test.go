@@ -0,0 +1,61 @@
+package client_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/irmine/gomine/client"
+	"github.com/irmine/gomine/internal/gominetest"
+	"github.com/irmine/gomine/net/packets/bedrock"
+)
+
+func TestClient(t *testing.T) {
+	var server, transport = gominetest.StartServer(t, nil)
+	var connection, err = transport.Connect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := client.New("Steve", connection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	var messages = make(chan string, 16)
+	c.TextFunction = func(packet *bedrock.TextPacket) {
+		messages <- packet.Message
+	}
+
+	if err := c.Join(time.Second * 10); err != nil {
+		t.Fatalf("joining: %v", err)
+	}
+	if _, ok := server.SessionManager.GetSessionByUUID(c.GetUUID()); !ok {
+		t.Fatal("client spawned without a session on the server")
+	}
+
+	if err := c.SendChat("Hello"); err != nil {
+		t.Fatal(err)
+	}
+	expectMessage(t, messages, "<Steve> Hello")
+
+	if err := c.SendCommand("/list"); err != nil {
+		t.Fatal(err)
+	}
+	expectMessage(t, messages, "Player List (1 Player)")
+}
+
+// expectMessage waits until a message containing the expected text is received.
+func expectMessage(t *testing.T, messages chan string, expected string) {
+	t.Helper()
+	var timeout = time.After(time.Second * 5)
+	for {
+		select {
+		case message := <-messages:
+			if strings.Contains(message, expected) {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("no message containing %q was received", expected)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package client
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/irmine/gomine/net/info"
+	"github.com/irmine/gomine/net/packets/types"
+	"github.com/irmine/gomine/utils"
+)
+
+// InvalidJwt gets returned if a JWT sent by the server could not be parsed or verified.
+var InvalidJwt = errors.New("invalid server handshake JWT")
+
+// chainPayload is the payload of the self-signed identity chain of the client.
+type chainPayload struct {
+	ExtraData         map[string]interface{} `json:"extraData"`
+	IdentityPublicKey string                 `json:"identityPublicKey"`
+	NotBefore         int64                  `json:"nbf"`
+	ExpirationTime    int64                  `json:"exp"`
+	IssuedAt          int64                  `json:"iat"`
+}
+
+// NewSelfSignedChain returns a login chain containing a single JWT, signed by the given key.
+// Servers accept self-signed chains if XBOX Live authentication is not required.
+func NewSelfSignedChain(key *ecdsa.PrivateKey, name string, identity uuid.UUID, xuid string) []string {
+	var publicKey, _ = x509.MarshalPKIXPublicKey(&key.PublicKey)
+	var now = time.Now()
+
+	var payload = chainPayload{
+		ExtraData: map[string]interface{}{
+			"displayName": name,
+			"identity":    identity.String(),
+			"XUID":        xuid,
+		},
+		IdentityPublicKey: base64.RawStdEncoding.EncodeToString(publicKey),
+		NotBefore:         now.Add(-time.Minute).Unix(),
+		ExpirationTime:    now.Add(24 * time.Hour).Unix(),
+		IssuedAt:          now.Unix(),
+	}
+	return []string{utils.ConstructJwt(key, payload)}
+}
+
+// NewClientData returns the client data JWT of a login, signed by the given key.
+// The skin of the client is a blank 64x64 skin.
+func NewClientData(key *ecdsa.PrivateKey, serverAddress string, clientId int) string {
+	var data = types.ClientDataKeys{
+		ClientRandomId: clientId,
+		ServerAddress:  serverAddress,
+		LanguageCode:   "en_US",
+		SkinId:         "Standard_Custom",
+		SkinData:       base64.RawStdEncoding.EncodeToString(make([]byte, 64*64*4)),
+		GeometryId:     "geometry.humanoid.custom",
+		DeviceModel:    "GoMine Client",
+		DeviceOS:       7,
+		GameVersion:    info.LatestGameVersionNetwork,
+	}
+	return utils.ConstructJwt(key, data)
+}
+
+// parseHandshakeJwt verifies the JWT of a server handshake,
+// and returns the public key of the server and the token used as salt.
+func parseHandshakeJwt(jwt string) (*ecdsa.PublicKey, []byte, error) {
+	var parts = strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		return nil, nil, InvalidJwt
+	}
+
+	var header = utils.EncryptionHeader{}
+	var payload = utils.EncryptionPayload{}
+	if !decodeJwtPart(parts[0], &header) || !decodeJwtPart(parts[1], &payload) {
+		return nil, nil, InvalidJwt
+	}
+
+	var keyData, err = base64.RawStdEncoding.DecodeString(header.X5u)
+	if err != nil {
+		return nil, nil, InvalidJwt
+	}
+	key, err := x509.ParsePKIXPublicKey(keyData)
+	if err != nil {
+		return nil, nil, InvalidJwt
+	}
+	var publicKey, ok = key.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, nil, InvalidJwt
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || len(signature) == 0 || len(signature)%2 != 0 {
+		return nil, nil, InvalidJwt
+	}
+	var hash = sha512.New384()
+	hash.Write([]byte(parts[0] + "." + parts[1]))
+
+	var r = new(big.Int).SetBytes(signature[:len(signature)/2])
+	var s = new(big.Int).SetBytes(signature[len(signature)/2:])
+	if !ecdsa.Verify(publicKey, hash.Sum(nil), r, s) {
+		return nil, nil, InvalidJwt
+	}
+
+	token, err := base64.RawStdEncoding.DecodeString(payload.Token)
+	if err != nil {
+		return nil, nil, InvalidJwt
+	}
+	return publicKey, token, nil
+}
+
+// decodeJwtPart decodes a base64 encoded part of a JWT into v.
+func decodeJwtPart(part string, v interface{}) bool {
+	var data, err = base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, v) == nil
+}
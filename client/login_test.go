@@ -0,0 +1,75 @@
+package client
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/irmine/gomine/net/packets/bedrock"
+	"github.com/irmine/gomine/utils"
+)
+
+func TestLoginRoundTrip(t *testing.T) {
+	var key, _ = ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	var identity = uuid.New()
+
+	var login = bedrock.NewLoginPacket()
+	login.Protocol = 332
+	login.RawChains = NewSelfSignedChain(key, "Steve", identity, "")
+	login.RawClientData = NewClientData(key, "127.0.0.1:19132", 1)
+	login.Encode()
+
+	var decoded = bedrock.NewLoginPacket()
+	decoded.SetBuffer(login.GetBuffer())
+	if err := decoded.Decode(); err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.Protocol != 332 {
+		t.Errorf("protocol %v does not equal 332", decoded.Protocol)
+	}
+	if decoded.Username != "Steve" {
+		t.Errorf("username %q does not equal Steve", decoded.Username)
+	}
+	if decoded.ClientUUID != identity {
+		t.Errorf("UUID %v does not equal %v", decoded.ClientUUID, identity)
+	}
+	if len(decoded.Chains) != 1 || decoded.Chains[0].Header.X5u != decoded.IdentityPublicKey {
+		t.Error("chain is not signed by the identity public key")
+	}
+	if decoded.ServerAddress != "127.0.0.1:19132" {
+		t.Errorf("server address %q does not equal 127.0.0.1:19132", decoded.ServerAddress)
+	}
+}
+
+func TestHandshakeKeyAgreement(t *testing.T) {
+	var serverKey, _ = ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	var clientKey, _ = ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	var token = []byte("server token")
+
+	publicKey, salt, err := parseHandshakeJwt(utils.ConstructEncryptionJwt(serverKey, token))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if publicKey.X.Cmp(serverKey.X) != 0 || !bytes.Equal(salt, token) {
+		t.Fatal("handshake JWT does not contain the server key and token")
+	}
+
+	var server = &utils.EncryptionData{ClientPublicKey: &clientKey.PublicKey, ServerPrivateKey: serverKey, ServerToken: token}
+	var client = &utils.EncryptionData{ClientPublicKey: publicKey, ServerPrivateKey: clientKey, ServerToken: salt}
+	for _, data := range []*utils.EncryptionData{server, client} {
+		data.ComputeSharedSecret()
+		data.ComputeSecretKeyBytes()
+	}
+	if server.EncryptSecretKeyBytes != client.DecryptSecretKeyBytes {
+		t.Fatal("client and server computed different keys")
+	}
+
+	var jwt = utils.ConstructEncryptionJwt(serverKey, token)
+	if _, _, err := parseHandshakeJwt(jwt[:len(jwt)-4] + "AAAA"); err != InvalidJwt {
+		t.Fatalf("expected InvalidJwt for a tampered signature, got %v", err)
+	}
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/irmine/gomine"
+	"github.com/irmine/gomine/net"
+	"github.com/irmine/gomine/net/capture"
+	"github.com/irmine/gomine/net/info"
+	"github.com/irmine/gomine/net/packets"
+	"github.com/irmine/gomine/net/packets/bedrock"
+	"github.com/irmine/gomine/resources"
+	"github.com/irmine/gomine/text"
+)
+
+// maximumDumpLength is the maximum length of byte slices printed when inspecting packets.
+// Longer byte slices only have their length printed.
+const maximumDumpLength = 64
+
+// runInspect runs the inspect tool, which prints every packet in a capture file decoded.
+// Usage: gomine inspect [-hex] <capture file>
+func runInspect(args []string) error {
+	var flags = flag.NewFlagSet("inspect", flag.ExitOnError)
+	var printHex = flags.Bool("hex", false, "print the raw packet buffer of every packet")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: gomine inspect [-hex] <capture file>")
+	}
+
+	records, err := readCapture(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	if len(records) == 0 {
+		return nil
+	}
+	var start = records[0].Time
+	for _, record := range records {
+		fmt.Printf("%10.3fs %v %v\n", record.Time.Sub(start).Seconds(), record.Direction, inspectPacket(record.Data))
+		if *printHex {
+			fmt.Println(hex.Dump(record.Data))
+		}
+	}
+	return nil
+}
+
+// runReplay runs the replay tool, which starts a fresh server on a loopback transport,
+// and replays all packets the client sent in a capture file against it.
+// The replay server runs in a temporary directory, which gets removed once the replay finished.
+// Encryption and XBOX Live authentication are disabled on the replay server,
+// as the captured login can not be re-encrypted with a new key.
+// Usage: gomine replay [-fast] <capture file>
+func runReplay(args []string) error {
+	var flags = flag.NewFlagSet("replay", flag.ExitOnError)
+	var fast = flags.Bool("fast", false, "replay packets without the delays between them")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: gomine replay [-fast] <capture file>")
+	}
+
+	records, err := readCapture(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	path, err := ioutil.TempDir("", "gomine-replay")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(path)
+	path += "/"
+	gomine.SetUpDirectories(path)
+	var config = resources.NewGoMineConfig(path)
+	config.UseEncryption = false
+	config.XBOXLiveAuth = false
+	config.CapturePackets = false
+
+	var transport = net.NewLoopbackTransport(0)
+	var server = gomine.New(path, gomine.WithConfig(config), gomine.WithTransport(transport))
+	if err := server.Start(); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var stopped = make(chan error, 1)
+	go func() {
+		stopped <- server.Run(ctx)
+	}()
+
+	connection, err := transport.Connect()
+	if err != nil {
+		return err
+	}
+	go func() {
+		for {
+			if _, err := connection.ReadBatch(); err != nil {
+				return
+			}
+		}
+	}()
+
+	var last time.Time
+	for _, record := range records {
+		if record.Direction != capture.Inbound || len(record.Data) == 0 {
+			continue
+		}
+		if record.Data[0] == byte(info.PacketIds[info.ClientHandshakePacket]) {
+			continue
+		}
+		if !*fast && !last.IsZero() {
+			time.Sleep(record.Time.Sub(last))
+		}
+		last = record.Time
+
+		server.Logger.Info("Replaying", inspectPacket(record.Data))
+		var packet = &rawPacket{packets.NewPacket(int(record.Data[0]))}
+		packet.SetBuffer(record.Data)
+
+		var batch = net.NewMinecraftPacketBatch(nil)
+		batch.AddPacket(packet)
+		batch.Encode()
+		if err := connection.WriteBatch(batch.Buffer); err != nil {
+			return err
+		}
+	}
+
+	time.Sleep(time.Second)
+	connection.Close()
+	cancel()
+	return <-stopped
+}
+
+// rawPacket is a packet of which the buffer is already encoded.
+type rawPacket struct {
+	*packets.Packet
+}
+
+// EncodeHeader does nothing, as the buffer already holds the header.
+func (pk *rawPacket) EncodeHeader() {}
+
+// Encode does nothing, as the buffer already holds the encoded packet.
+func (pk *rawPacket) Encode() {}
+
+// readCapture reads all records of the capture file at the given path.
+func readCapture(path string) ([]capture.Record, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader, err := capture.NewReader(file)
+	if err != nil {
+		return nil, err
+	}
+	records, err := reader.ReadAll()
+	if err == io.ErrUnexpectedEOF {
+		text.DefaultLogger.Notice("Capture file is truncated, reading records up to the truncated record.")
+		err = nil
+	}
+	return records, err
+}
+
+// inspectPacket decodes the packet in the buffer,
+// and returns the name of the packet with all of its fields.
+func inspectPacket(buffer []byte) string {
+	if len(buffer) == 0 {
+		return "empty packet"
+	}
+	var name, ok = info.PacketIds.GetPacketName(int(buffer[0]))
+	if !ok {
+		return fmt.Sprintf("unknown packet 0x%02x (%v bytes)", buffer[0], len(buffer))
+	}
+	packetFunc, ok := bedrock.Packets[name]
+	if !ok {
+		return fmt.Sprintf("%v (%v bytes, no decoder)", name, len(buffer))
+	}
+
+	var packet = packetFunc()
+	packet.SetBuffer(buffer)
+	if err := packet.DecodeHeader(); err != nil {
+		return fmt.Sprintf("%v (%v bytes, malformed: %v)", name, len(buffer), err)
+	}
+	if err := packet.Decode(); err != nil {
+		return fmt.Sprintf("%v (%v bytes, malformed: %v)", name, len(buffer), err)
+	}
+
+	return fmt.Sprintf("%v %v", name, formatFields(reflect.ValueOf(packet).Elem()))
+}
+
+// formatFields formats all exported fields of the struct value, except for embedded fields.
+func formatFields(value reflect.Value) string {
+	var fields []string
+	for i := 0; i < value.NumField(); i++ {
+		var field = value.Type().Field(i)
+		if field.Anonymous || field.PkgPath != "" {
+			continue
+		}
+		var fieldValue = value.Field(i).Interface()
+		if bytes, ok := fieldValue.([]byte); ok && len(bytes) > maximumDumpLength {
+			fieldValue = fmt.Sprintf("[%v bytes]", len(bytes))
+		}
+		fields = append(fields, fmt.Sprintf("%v=%v", field.Name, fieldValue))
+	}
+	return "{" + strings.Join(fields, " ") + "}"
+}
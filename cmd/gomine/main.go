@@ -1,37 +1,48 @@
 package main
 
 import (
+	"context"
 	"github.com/irmine/gomine"
 	"github.com/irmine/gomine/resources"
-	"github.com/irmine/gomine/text"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
 	"time"
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "inspect":
+			must(runInspect(os.Args[2:]))
+			return
+		case "replay":
+			must(runReplay(os.Args[2:]))
+			return
+		}
+	}
+
 	startTime := time.Now()
 	path, err := GetServerPath()
 	must(err)
-	SetUpDirectories(path)
+	gomine.SetUpDirectories(path)
 
 	config := resources.NewGoMineConfig(path)
-	server := gomine.NewServer(path, config)
+	server := gomine.New(path, gomine.WithConfig(config), gomine.WithConsole(os.Stdin))
 
 	must(server.Start())
-	text.DefaultLogger.Info("Server startup done! Took:", time.Now().Sub(startTime))
-
-	var ticker = time.NewTicker(time.Millisecond * 50)
-	for {
-		select{
-		case <- ticker.C:
-			if !server.IsRunning() {
-				break
-			}
-			server.Tick()
-		}
-	}
+	server.Logger.Info("Server startup done! Took:", time.Now().Sub(startTime))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var signals = make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		server.Logger.Info("Received signal:", <-signals)
+		cancel()
+	}()
+	must(server.Run(ctx))
 }
 
 func must(err error) {
@@ -45,11 +56,3 @@ func GetServerPath() (string, error) {
 	executable, err := os.Executable()
 	return strings.Replace(filepath.Dir(executable)+"/", `\`, "/", -1), err
 }
-
-// SetUpDirectories sets up all directories needed for GoMine.
-func SetUpDirectories(path string) {
-	os.Mkdir(path+"extensions", 0700)
-	os.Mkdir(path+"extensions/plugins", 0700)
-	os.Mkdir(path+"extensions/behavior_packs", 0700)
-	os.Mkdir(path+"extensions/resource_packs", 0700)
-}
@@ -1,42 +1,46 @@
 package main
 
 import (
+	"context"
 	"github.com/irmine/gomine"
+	"github.com/irmine/gomine/internal/gominetest"
 	"github.com/irmine/gomine/resources"
-	"github.com/irmine/gomine/text"
 	"testing"
 	"time"
 )
 
 func TestSharedServer(t *testing.T) {
 	ports := []uint16{19132, 19133, 19134, 19135, 19136}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	var errs = make(chan error, len(ports))
 	for _, port := range ports {
-		go StartServer(port)
+		server := NewServer(t, port)
+		go func(port uint16) {
+			errs <- RunServer(ctx, t, server, port)
+		}(port)
+	}
+	for range ports {
+		if err := <-errs; err != nil {
+			t.Error(err)
+		}
 	}
-	time.Sleep(time.Minute * 10)
 }
 
-func StartServer(port uint16) {
-	text.DefaultLogger.Info("Starting server with port:", port)
-	startTime := time.Now()
-	path, err := GetServerPath()
-	if err != nil {
-		panic(err)
-	}
-	SetUpDirectories(path)
-	config := resources.NewGoMineConfig(path)
-	config.ServerPort = port
-	server := gomine.NewServer(path, config)
+func NewServer(t *testing.T, port uint16) *gomine.Server {
+	server, _ := gominetest.NewServer(t, func(config *resources.GoMineConfig) {
+		config.ServerPort = port
+	})
+	return server
+}
 
-	if err := server.Start(); err != nil {
-		panic(err)
+func RunServer(ctx context.Context, t *testing.T, server *gomine.Server, port uint16) error {
+	if err := server.Run(ctx); err != nil {
+		return err
 	}
-	text.DefaultLogger.Info("Server startup done! Took:", time.Now().Sub(startTime))
-
-	for range time.NewTicker(time.Second / 20).C {
-		if !server.IsRunning() {
-			break
-		}
-		server.Tick()
+	if server.IsRunning() {
+		t.Errorf("server on port %v is still running after its context was cancelled", port)
 	}
+	return nil
 }
@@ -1,6 +1,27 @@
 package arguments
 
-import "strconv"
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// InvalidValue gets returned when parsing a value the validation function of an argument rejects.
+var InvalidValue = errors.New("invalid value")
+
+// TokenError is returned when parsing the tokens of an argument fails.
+type TokenError struct {
+	// Index is the index of the token that could not be parsed.
+	Index int
+	// Err is the reason the token could not be parsed.
+	Err error
+}
+
+// Error returns the error of the token.
+func (err *TokenError) Error() string {
+	return err.Err.Error()
+}
 
 type Argument struct {
 	name               string
@@ -10,6 +31,10 @@ type Argument struct {
 	validationFunction func(argument string) bool
 	conversionFunction func(argument string) interface{}
 	shouldMerge        bool
+	variadic           bool
+	typeName           string
+	parseFunction      func(sender interface{}, argument string) (interface{}, error)
+	optionsFunction    func() []string
 }
 
 // GetName returns the name of the argument.
@@ -42,16 +67,82 @@ func (argument *Argument) SetInputAmount(amount int) {
 	argument.inputArgs = amount
 }
 
-// SetOutput sets the output value of this argument.
+// IsVariadic checks if the argument takes any amount of values up to its input amount.
+func (argument *Argument) IsVariadic() bool {
+	return argument.variadic
+}
+
+// SetVariadic sets if the argument takes any amount of values up to its input amount,
+// rather than requiring all of them. Variadic arguments that are not optional require at least one value.
+func (argument *Argument) SetVariadic(value bool) {
+	argument.variadic = value
+}
+
+// GetRequiredInputAmount returns the amount of values that must be given for the argument if it is not optional.
+func (argument *Argument) GetRequiredInputAmount() int {
+	if argument.variadic && argument.inputArgs > 0 {
+		return 1
+	}
+	return argument.inputArgs
+}
+
+// SetOutput sets the default output value of this argument.
+// Its type is the type shown in the usage of the argument.
+// Parsed values are not stored in the argument, but in the context of every command execution.
 func (argument *Argument) SetOutput(value interface{}) {
 	argument.output = value
 }
 
-// GetOutput returns the output value of this argument.
+// GetOutput returns the default output value of this argument.
 func (argument *Argument) GetOutput() interface{} {
 	return argument.output
 }
 
+// GetOptions returns the values the argument accepts, or nil if it accepts any value of its type.
+// Options are shown to clients for autocompletion.
+func (argument *Argument) GetOptions() []string {
+	if argument.optionsFunction == nil {
+		return nil
+	}
+	return argument.optionsFunction()
+}
+
+// SetOptionsFunction sets the function returning the values the argument accepts.
+// The function is called every time the options are needed, so that they may change over time.
+func (argument *Argument) SetOptionsFunction(function func() []string) {
+	argument.optionsFunction = function
+}
+
+// GetTypeName returns the name of the type of this argument, as shown in its usage.
+// The name of the type of the default output is used if no type name was set.
+func (argument *Argument) GetTypeName() string {
+	if argument.typeName != "" {
+		return argument.typeName
+	}
+	if argument.output == nil {
+		return "value"
+	}
+	return reflect.TypeOf(argument.output).Name()
+}
+
+// SetTypeName sets the name of the type of this argument, as shown in its usage.
+func (argument *Argument) SetTypeName(name string) {
+	argument.typeName = name
+}
+
+// GetUsage returns the usage of this argument, for example "<amount: int>" or "[reason: string]".
+// The amount of input is shown for arguments taking multiple values of a type without a type name set.
+func (argument *Argument) GetUsage() string {
+	var usage = argument.GetName() + ": " + argument.GetTypeName()
+	if argument.GetInputAmount() > 1 && argument.typeName == "" && argument.GetTypeName() != "string" {
+		usage += "(" + strconv.Itoa(argument.GetInputAmount()) + ")"
+	}
+	if argument.IsOptional() {
+		return "[" + usage + "]"
+	}
+	return "<" + usage + ">"
+}
+
 // ShouldMerge returns whether this argument should merge all its values or not.
 func (argument *Argument) ShouldMerge() bool {
 	return argument.shouldMerge
@@ -67,6 +158,54 @@ func (argument *Argument) ConvertValue(value string) interface{} {
 	return argument.conversionFunction(value)
 }
 
+// SetParseFunction sets the function parsing values of the argument for the sender executing a command.
+// It is used over the validation and conversion functions, for arguments of which the value depends on the sender,
+// and returns an error describing why a value is invalid.
+// Arguments taking multiple values get all values passed at once, joined by spaces.
+func (argument *Argument) SetParseFunction(function func(sender interface{}, value string) (interface{}, error)) {
+	argument.parseFunction = function
+}
+
+// Parse validates and converts the value for the sender executing a command.
+// InvalidValue is returned if the value is rejected by the validation function.
+func (argument *Argument) Parse(sender interface{}, value string) (interface{}, error) {
+	if argument.parseFunction != nil {
+		return argument.parseFunction(sender, value)
+	}
+	if !argument.IsValidValue(value) {
+		return nil, InvalidValue
+	}
+	return argument.ConvertValue(value), nil
+}
+
+// ParseTokens parses the tokens of input of the argument for the sender executing a command.
+// Arguments with a parse function get passed all tokens at once,
+// while the tokens of other arguments are validated and converted one by one.
+// A *TokenError pointing at the token that could not be parsed is returned if parsing fails.
+func (argument *Argument) ParseTokens(sender interface{}, tokens []string) (interface{}, error) {
+	if argument.parseFunction != nil {
+		var value, err = argument.parseFunction(sender, strings.Join(tokens, " "))
+		if err != nil {
+			return nil, &TokenError{Index: 0, Err: err}
+		}
+		return value, nil
+	}
+	var values = make([]interface{}, len(tokens))
+	for i, token := range tokens {
+		if !argument.IsValidValue(token) {
+			return nil, &TokenError{Index: i, Err: InvalidValue}
+		}
+		values[i] = argument.ConvertValue(token)
+	}
+	if len(values) == 1 {
+		return values[0], nil
+	}
+	if argument.ShouldMerge() {
+		return strings.Join(tokens, " "), nil
+	}
+	return values, nil
+}
+
 // IsInt checks if the input string is able to be parsed as an integer.
 func IsInt(value string) bool {
 	var _, err = strconv.Atoi(value)
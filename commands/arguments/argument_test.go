@@ -0,0 +1,152 @@
+package arguments
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/irmine/gomine/commands/selectors"
+	"github.com/irmine/gomine/items"
+)
+
+type testSource struct{}
+
+func (source testSource) GetOrigin(sender interface{}) selectors.Origin {
+	return selectors.Origin{Position: selectors.Vector{X: 10, Y: 64, Z: -5}}
+}
+
+func (source testSource) GetPlayers() []*selectors.Target {
+	return []*selectors.Target{{Name: "Steve", Player: true}, {Name: "Stella", Player: true}, {Name: "Alex", Player: true}}
+}
+
+func (source testSource) GetEntities(dimension interface{}) []*selectors.Target {
+	return nil
+}
+
+func TestPosition(t *testing.T) {
+	var position = NewPosition("position", false, testSource{})
+	var tests = map[string]selectors.Vector{
+		"1 2 3":      {X: 1, Y: 2, Z: 3},
+		"~ ~1 ~":     {X: 10, Y: 65, Z: -5},
+		"~-1.5 70 ~": {X: 8.5, Y: 70, Z: -5},
+		"^ ^ ^2":     {X: 10, Y: 64, Z: -3},
+		"^1 ^ ^":     {X: 11, Y: 64, Z: -5},
+	}
+	for input, expected := range tests {
+		var value, err = position.ParseTokens(nil, split(input))
+		if err != nil {
+			t.Errorf("expected %v to be parsed, got %v", input, err)
+			continue
+		}
+		var vector = value.(selectors.Vector)
+		if math.Abs(vector.X-expected.X) > 1e-9 || math.Abs(vector.Y-expected.Y) > 1e-9 || math.Abs(vector.Z-expected.Z) > 1e-9 {
+			t.Errorf("expected %v to be %v, got %v", input, expected, vector)
+		}
+	}
+	for input, expected := range map[string]error{"^ ~ ^": MixedCoordinates, "1 two 3": InvalidCoordinates} {
+		if _, err := position.ParseTokens(nil, split(input)); err == nil || err.(*TokenError).Err != expected {
+			t.Errorf("expected %v to fail with %v, got %v", input, expected, err)
+		}
+	}
+	if position.GetUsage() != "<position: x y z>" {
+		t.Errorf("unexpected usage %v", position.GetUsage())
+	}
+}
+
+func TestPlayer(t *testing.T) {
+	var player = NewPlayer("player", false, testSource{})
+	for input, expected := range map[string]string{"steve": "Steve", "al": "Alex", "Stel": "Stella"} {
+		var value, err = player.Parse(nil, input)
+		if err != nil || value.(*selectors.Target).Name != expected {
+			t.Errorf("expected %v to match %v, got %v %v", input, expected, value, err)
+		}
+	}
+	if _, err := player.Parse(nil, "St"); err == nil || err.Error() != "multiple players match: Stella, Steve" {
+		t.Errorf("expected an ambiguous name to fail, got %v", err)
+	}
+	if _, err := player.Parse(nil, "Notch"); err != PlayerNotOnline {
+		t.Errorf("expected an offline player to fail, got %v", err)
+	}
+}
+
+func TestItemAndBlock(t *testing.T) {
+	var item, block = NewItem("item", false), NewBlock("block", false)
+	if value, err := item.Parse(nil, "stone"); err != nil || value.(items.Type).GetId() != "minecraft:stone" {
+		t.Errorf("expected stone to be an item, got %v %v", value, err)
+	}
+	if _, err := item.Parse(nil, "minecraft:diamond_pickaxe_of_doom"); err != UnknownItem {
+		t.Errorf("expected an unknown item to fail, got %v", err)
+	}
+	if value, err := block.Parse(nil, "minecraft:stone"); err != nil || value.(items.Type).GetId() != "minecraft:stone" {
+		t.Errorf("expected stone to be a block, got %v %v", value, err)
+	}
+	if _, err := block.Parse(nil, "dirt_block_of_doom"); err != UnknownBlock {
+		t.Errorf("expected an unknown block to fail, got %v", err)
+	}
+	if options := item.GetOptions(); len(options) != 2 || options[0] != "air" || options[1] != "stone" {
+		t.Errorf("expected the options of items to be all registered items, got %v", options)
+	}
+}
+
+func TestBoolAndDuration(t *testing.T) {
+	var b = NewBool("value", false)
+	for input, expected := range map[string]bool{"true": true, "ON": true, "no": false} {
+		if value, err := b.Parse(nil, input); err != nil || value != expected {
+			t.Errorf("expected %v to be %v, got %v %v", input, expected, value, err)
+		}
+	}
+	if _, err := b.Parse(nil, "maybe"); err != InvalidBool {
+		t.Errorf("expected maybe to fail, got %v", err)
+	}
+	if options := b.GetOptions(); len(options) != 2 {
+		t.Errorf("expected bools to have true and false as options, got %v", options)
+	}
+	if options := NewInt("amount", false).GetOptions(); options != nil {
+		t.Errorf("expected ints to have no options, got %v", options)
+	}
+
+	var duration = NewDuration("duration", false)
+	for input, expected := range map[string]time.Duration{"30s": time.Second * 30, "1h30m": time.Minute * 90, "1w1d": time.Hour * 24 * 8} {
+		if value, err := duration.Parse(nil, input); err != nil || value != expected {
+			t.Errorf("expected %v to be %v, got %v %v", input, expected, value, err)
+		}
+	}
+	for _, input := range []string{"", "30", "h", "1x", "0s", "1h30", "99999999w", "15000w15000w"} {
+		if _, err := duration.Parse(nil, input); err != InvalidDuration {
+			t.Errorf("expected %q to fail, got %v", input, err)
+		}
+	}
+}
+
+func TestJsonAndRawText(t *testing.T) {
+	var j = NewJson("data", false)
+	if value, err := j.ParseTokens(nil, split(`{"a": [1, 2]}`)); err != nil || string(value.(json.RawMessage)) != `{"a": [1, 2]}` {
+		t.Errorf("expected valid JSON to be parsed, got %v %v", value, err)
+	}
+	if _, err := j.ParseTokens(nil, split(`{"a":`)); err == nil {
+		t.Error("expected invalid JSON to fail")
+	}
+
+	var raw = NewRawText("message", false)
+	if value, err := raw.ParseTokens(nil, split(`{"rawtext": [{"text": "Hello "}, {"translate": "item.apple.name"}]}`)); err != nil || value != "Hello %item.apple.name" {
+		t.Errorf("expected raw text to be parsed, got %v %v", value, err)
+	}
+	if _, err := raw.ParseTokens(nil, split(`{"text": "Hello"}`)); err == nil || err.(*TokenError).Err != InvalidRawText {
+		t.Errorf("expected JSON that is not raw text to fail, got %v", err)
+	}
+}
+
+func split(value string) []string {
+	var tokens []string
+	var token = ""
+	for _, c := range value {
+		if c == ' ' {
+			tokens = append(tokens, token)
+			token = ""
+			continue
+		}
+		token += string(c)
+	}
+	return append(tokens, token)
+}
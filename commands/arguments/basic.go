@@ -1,51 +1,142 @@
 package arguments
 
 import (
+	"errors"
+	"math"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // NewFloat returns a new Float argument with the given name and optional value.
 func NewFloat(name string, optional bool) *Argument {
-	return &Argument{name, optional, 1, float64(0), func(value string) bool {
+	return &Argument{name: name, optional: optional, inputArgs: 1, output: float64(0), validationFunction: func(value string) bool {
 		return IsFloat(value)
-	}, func(value string) interface{} {
+	}, conversionFunction: func(value string) interface{} {
 		var float, _ = strconv.ParseFloat(value, 64)
 		return float
-	}, false}
+	}, shouldMerge: false}
 }
 
 // NewInt returns a new Int argument with the given name and optional value.
 func NewInt(name string, optional bool) *Argument {
-	return &Argument{name, optional, 1, 0, func(value string) bool {
+	return &Argument{name: name, optional: optional, inputArgs: 1, output: 0, validationFunction: func(value string) bool {
 		return IsInt(value)
-	}, func(value string) interface{} {
+	}, conversionFunction: func(value string) interface{} {
 		var i, _ = strconv.ParseInt(value, 10, 64)
 		return i
-	}, false}
+	}, shouldMerge: false}
 }
 
 // NewString returns a new String argument with the given name and optional value.
 func NewString(name string, optional bool) *Argument {
-	var arg = &Argument{name, optional, 1, "", func(value string) bool {
+	var arg = &Argument{name: name, optional: optional, inputArgs: 1, output: "", validationFunction: func(value string) bool {
 		return true
-	}, func(value string) interface{} {
+	}, conversionFunction: func(value string) interface{} {
 		return value
-	}, true}
+	}, shouldMerge: true}
 	return arg
 }
 
 // NewStringEnum returns a new String Enum argument with the given name and optional value.
 func NewStringEnum(name string, optional bool, options []string) *Argument {
-	var arg = &Argument{name, optional, 1, "", func(value string) bool {
+	var arg = &Argument{name: name, optional: optional, inputArgs: 1, output: "", validationFunction: func(value string) bool {
 		for _, option := range options {
 			if strings.ToLower(option) == strings.ToLower(value) {
 				return true
 			}
 		}
 		return false
-	}, func(value string) interface{} {
+	}, conversionFunction: func(value string) interface{} {
 		return strings.ToLower(value)
-	}, true}
+	}, shouldMerge: true, optionsFunction: func() []string {
+		return options
+	}}
 	return arg
 }
+
+// InvalidBool gets returned when parsing a bool that is not true, false, on, off, yes or no.
+var InvalidBool = errors.New("expected true or false")
+
+// InvalidDuration gets returned when parsing a duration that is not made up of amounts of units, such as 1h30m.
+var InvalidDuration = errors.New("expected a duration such as 30s, 1h30m or 7d, in units of w, d, h, m and s")
+
+// boolValues are the values accepted by bool arguments.
+var boolValues = map[string]bool{
+	"true": true, "on": true, "yes": true, "1": true,
+	"false": false, "off": false, "no": false, "0": false,
+}
+
+// durationUnits are the units of the values accepted by duration arguments.
+var durationUnits = map[byte]time.Duration{
+	'w': time.Hour * 24 * 7,
+	'd': time.Hour * 24,
+	'h': time.Hour,
+	'm': time.Minute,
+	's': time.Second,
+}
+
+// NewBool returns a new Bool argument with the given name and optional value.
+// The argument takes true, on or yes, or false, off or no.
+func NewBool(name string, optional bool) *Argument {
+	return &Argument{name: name, optional: optional, inputArgs: 1, output: false, validationFunction: func(value string) bool {
+		var _, ok = boolValues[strings.ToLower(value)]
+		return ok
+	}, conversionFunction: func(value string) interface{} {
+		return boolValues[strings.ToLower(value)]
+	}, parseFunction: func(sender interface{}, value string) (interface{}, error) {
+		var b, ok = boolValues[strings.ToLower(value)]
+		if !ok {
+			return nil, InvalidBool
+		}
+		return b, nil
+	}, optionsFunction: func() []string {
+		return []string{"true", "false"}
+	}}
+}
+
+// NewDuration returns a new Duration argument with the given name and optional value.
+// The argument takes amounts of weeks, days, hours, minutes and seconds, such as 1h30m or 7d.
+// Its value is a time.Duration.
+func NewDuration(name string, optional bool) *Argument {
+	return &Argument{name: name, optional: optional, inputArgs: 1, output: time.Duration(0), validationFunction: func(value string) bool {
+		var _, err = ParseDuration(value)
+		return err == nil
+	}, conversionFunction: func(value string) interface{} {
+		var duration, _ = ParseDuration(value)
+		return duration
+	}, typeName: "duration", parseFunction: func(sender interface{}, value string) (interface{}, error) {
+		return ParseDuration(value)
+	}}
+}
+
+// ParseDuration parses a duration made up of amounts of units, such as 1h30m or 2w3d.
+// The units are w, d, h, m and s. InvalidDuration is returned if the duration could not be parsed.
+func ParseDuration(value string) (time.Duration, error) {
+	var duration time.Duration
+	var amount = ""
+	for i := 0; i < len(value); i++ {
+		if value[i] >= '0' && value[i] <= '9' {
+			amount += string(value[i])
+			continue
+		}
+		var unit, ok = durationUnits[value[i]]
+		if !ok || amount == "" {
+			return 0, InvalidDuration
+		}
+		var n, err = strconv.ParseInt(amount, 10, 32)
+		if err != nil || n > math.MaxInt64/int64(unit) {
+			return 0, InvalidDuration
+		}
+		// Durations that do not fit in a time.Duration would otherwise wrap around.
+		if time.Duration(n)*unit > math.MaxInt64-duration {
+			return 0, InvalidDuration
+		}
+		duration += time.Duration(n) * unit
+		amount = ""
+	}
+	if amount != "" || duration <= 0 {
+		return 0, InvalidDuration
+	}
+	return duration, nil
+}
@@ -0,0 +1,106 @@
+package arguments
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/irmine/gomine/items"
+)
+
+// UnknownItem gets returned when parsing the ID of an item that is not registered.
+var UnknownItem = errors.New("unknown item")
+
+// UnknownBlock gets returned when parsing the name of a block that is not registered.
+var UnknownBlock = errors.New("unknown block")
+
+// maximumBlockId is the highest numeric ID of blocks. Items with a higher ID can not be placed.
+const maximumBlockId = 255
+
+// NewItem returns a new Item argument with the given name and optional value.
+// The argument takes the string ID of an item registered in the default item manager,
+// with or without the minecraft: prefix, such as minecraft:stone or stone.
+// Its value is the items.Type of the item.
+func NewItem(name string, optional bool) *Argument {
+	return &Argument{name: name, optional: optional, inputArgs: 1, output: items.Type{}, validationFunction: func(value string) bool {
+		var _, ok = getItemType(value)
+		return ok
+	}, conversionFunction: func(value string) interface{} {
+		var t, _ = getItemType(value)
+		return t
+	}, typeName: "item", parseFunction: func(sender interface{}, value string) (interface{}, error) {
+		var t, ok = getItemType(value)
+		if !ok {
+			return nil, UnknownItem
+		}
+		return t, nil
+	}, optionsFunction: func() []string {
+		return getItemNames(false)
+	}}
+}
+
+// NewBlock returns a new Block argument with the given name and optional value.
+// The argument takes the name of a block, such as minecraft:stone or stone,
+// which must be registered as item in the default item manager with the ID of a block.
+// Its value is the items.Type of the block.
+func NewBlock(name string, optional bool) *Argument {
+	return &Argument{name: name, optional: optional, inputArgs: 1, output: items.Type{}, validationFunction: func(value string) bool {
+		var _, ok = getBlockType(value)
+		return ok
+	}, conversionFunction: func(value string) interface{} {
+		var t, _ = getBlockType(value)
+		return t
+	}, typeName: "block", parseFunction: func(sender interface{}, value string) (interface{}, error) {
+		var t, ok = getBlockType(value)
+		if !ok {
+			return nil, UnknownBlock
+		}
+		return t, nil
+	}, optionsFunction: func() []string {
+		return getItemNames(true)
+	}}
+}
+
+// getItemType returns the item type with the given string ID, adding the minecraft: prefix if it has none.
+func getItemType(value string) (items.Type, bool) {
+	var id = strings.ToLower(value)
+	if !strings.Contains(id, ":") {
+		id = "minecraft:" + id
+	}
+	if !items.DefaultManager.IsRegistered(id) {
+		return items.Type{}, false
+	}
+	return items.DefaultManager.GetTypes()[id], true
+}
+
+// getBlockType returns the item type of the block with the given name.
+// Items that do not have the numeric ID of a block are not blocks.
+func getBlockType(value string) (items.Type, bool) {
+	var t, ok = getItemType(value)
+	if !ok {
+		return t, false
+	}
+	var key, hasId = items.TypeToId[fmt.Sprint(t)]
+	if !hasId {
+		return t, false
+	}
+	var id, _ = items.FromKey(key)
+	return t, id >= 0 && id <= maximumBlockId
+}
+
+// getItemNames returns the sorted string IDs of all registered items, or only of those that are blocks,
+// without the minecraft: prefix.
+func getItemNames(blocksOnly bool) []string {
+	var names []string
+	for id := range items.DefaultManager.GetTypes() {
+		if blocksOnly {
+			if _, ok := getBlockType(id); !ok {
+				continue
+			}
+		}
+		names = append(names, strings.TrimPrefix(id, "minecraft:"))
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,76 @@
+package arguments
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// maximumJsonWords is the maximum amount of words in the input of JSON arguments.
+const maximumJsonWords = 256
+
+// InvalidRawText gets returned when parsing raw text that is not an object with a rawtext list of text components.
+var InvalidRawText = errors.New(`expected raw text such as {"rawtext":[{"text":"Hello"}]}`)
+
+// NewJson returns a new JSON argument with the given name and optional value.
+// The argument takes all remaining input, which must be valid JSON.
+// Its value is the json.RawMessage of the input.
+func NewJson(name string, optional bool) *Argument {
+	return &Argument{name: name, optional: optional, inputArgs: maximumJsonWords, output: json.RawMessage{}, validationFunction: func(value string) bool {
+		return true
+	}, conversionFunction: func(value string) interface{} {
+		return value
+	}, variadic: true, typeName: "json", parseFunction: func(sender interface{}, value string) (interface{}, error) {
+		var message json.RawMessage
+		if err := json.Unmarshal([]byte(value), &message); err != nil {
+			return nil, errors.New("invalid JSON: " + err.Error())
+		}
+		return message, nil
+	}}
+}
+
+// rawText is raw text as used in the tellraw and titleraw commands.
+type rawText struct {
+	RawText []rawTextComponent `json:"rawtext"`
+}
+
+// rawTextComponent is a component of raw text.
+// Translated components are kept as their translation key, to be translated by the client.
+type rawTextComponent struct {
+	Text      *string `json:"text"`
+	Translate *string `json:"translate"`
+}
+
+// NewRawText returns a new Raw Text argument with the given name and optional value.
+// The argument takes all remaining input, which must be raw text JSON, such as {"rawtext":[{"text":"Hello"}]}.
+// Its value is the string of all text components joined together.
+func NewRawText(name string, optional bool) *Argument {
+	return &Argument{name: name, optional: optional, inputArgs: maximumJsonWords, output: "", validationFunction: func(value string) bool {
+		return true
+	}, conversionFunction: func(value string) interface{} {
+		return value
+	}, variadic: true, typeName: "rawtext", parseFunction: func(sender interface{}, value string) (interface{}, error) {
+		return ParseRawText(value)
+	}}
+}
+
+// ParseRawText parses raw text JSON into the string of all text components joined together.
+// InvalidRawText is returned if the JSON is not raw text, or has components without text or translation.
+func ParseRawText(value string) (string, error) {
+	var raw rawText
+	if err := json.Unmarshal([]byte(value), &raw); err != nil || raw.RawText == nil {
+		return "", InvalidRawText
+	}
+	var text strings.Builder
+	for _, component := range raw.RawText {
+		switch {
+		case component.Text != nil:
+			text.WriteString(*component.Text)
+		case component.Translate != nil:
+			text.WriteString("%" + *component.Translate)
+		default:
+			return "", InvalidRawText
+		}
+	}
+	return text.String(), nil
+}
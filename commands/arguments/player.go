@@ -0,0 +1,52 @@
+package arguments
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/irmine/gomine/commands/selectors"
+)
+
+// NewPlayer returns a new Player argument with the given name and optional value.
+// The argument takes the name of an online player, or the start of it if only one player's name starts with it.
+// Its value is the *selectors.Target of the player.
+func NewPlayer(name string, optional bool, source selectors.Source) *Argument {
+	var arg = &Argument{name: name, optional: optional, inputArgs: 1, output: &selectors.Target{}, validationFunction: func(value string) bool {
+		return value != "" && !selectors.IsSelector(value)
+	}, conversionFunction: func(value string) interface{} {
+		return value
+	}, typeName: "player"}
+	arg.parseFunction = func(sender interface{}, value string) (interface{}, error) {
+		return findPlayer(source, value)
+	}
+	return arg
+}
+
+// findPlayer returns the player with the given name.
+// If no player has the name, the player of which the name starts with it is returned,
+// unless the names of multiple players start with it.
+func findPlayer(source selectors.Source, name string) (*selectors.Target, error) {
+	var matches []*selectors.Target
+	var lowerName = strings.ToLower(name)
+	for _, player := range source.GetPlayers() {
+		if strings.EqualFold(player.Name, name) {
+			return player, nil
+		}
+		if strings.HasPrefix(strings.ToLower(player.Name), lowerName) {
+			matches = append(matches, player)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, PlayerNotOnline
+	case 1:
+		return matches[0], nil
+	}
+	var names = make([]string, len(matches))
+	for i, match := range matches {
+		names[i] = match.Name
+	}
+	sort.Strings(names)
+	return nil, errors.New("multiple players match: " + strings.Join(names, ", "))
+}
@@ -0,0 +1,101 @@
+package arguments
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/irmine/gomine/commands/selectors"
+)
+
+// MixedCoordinates gets returned when parsing a position mixing local coordinates with world coordinates.
+var MixedCoordinates = errors.New("local coordinates (^) can not be mixed with world coordinates")
+
+// InvalidCoordinates gets returned when parsing a position of which a coordinate is not a number.
+var InvalidCoordinates = errors.New("coordinates must be numbers, optionally prefixed with ~ or ^")
+
+// NewPosition returns a new Position argument with the given name and optional value.
+// The argument takes three coordinates, which may each be absolute, such as 10,
+// or relative to the position of the sender, such as ~ or ~-2.
+// Local coordinates, such as ^ ^ ^2, are relative to the rotation of the sender:
+// left, up and forward respectively. They can not be mixed with other coordinates.
+// Its value is a selectors.Vector.
+func NewPosition(name string, optional bool, source selectors.Source) *Argument {
+	var arg = &Argument{name: name, optional: optional, inputArgs: 3, output: selectors.Vector{}, validationFunction: func(value string) bool {
+		var _, _, err = parseCoordinate(value)
+		return err == nil
+	}, conversionFunction: func(value string) interface{} {
+		return value
+	}, typeName: "x y z"}
+	arg.parseFunction = func(sender interface{}, value string) (interface{}, error) {
+		return parsePosition(source.GetOrigin(sender), strings.Fields(value))
+	}
+	return arg
+}
+
+// parsePosition parses the three coordinates relative to the origin.
+func parsePosition(origin selectors.Origin, coordinates []string) (selectors.Vector, error) {
+	if len(coordinates) != 3 {
+		return selectors.Vector{}, errors.New("expected 3 coordinates, got " + strconv.Itoa(len(coordinates)))
+	}
+	var values [3]float64
+	var local = 0
+	var base = [3]float64{origin.Position.X, origin.Position.Y, origin.Position.Z}
+	for i, coordinate := range coordinates {
+		var value, prefix, err = parseCoordinate(coordinate)
+		if err != nil {
+			return selectors.Vector{}, err
+		}
+		switch prefix {
+		case '^':
+			local++
+		case '~':
+			value += base[i]
+		}
+		values[i] = value
+	}
+	switch local {
+	case 0:
+		return selectors.Vector{X: values[0], Y: values[1], Z: values[2]}, nil
+	case 3:
+		return getLocalPosition(origin, values[0], values[1], values[2]), nil
+	}
+	return selectors.Vector{}, MixedCoordinates
+}
+
+// parseCoordinate parses a single coordinate, returning its value and its prefix, or 0 if it has none.
+// Prefixed coordinates without value, such as ~, have a value of 0.
+func parseCoordinate(coordinate string) (float64, byte, error) {
+	var prefix byte
+	if strings.HasPrefix(coordinate, "~") || strings.HasPrefix(coordinate, "^") {
+		prefix = coordinate[0]
+		if coordinate = coordinate[1:]; coordinate == "" {
+			return 0, prefix, nil
+		}
+	}
+	var value, err = strconv.ParseFloat(coordinate, 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, prefix, InvalidCoordinates
+	}
+	return value, prefix, nil
+}
+
+// getLocalPosition returns the position the given distance to the left, up and forward of the origin,
+// relative to its rotation.
+func getLocalPosition(origin selectors.Origin, left, up, forward float64) selectors.Vector {
+	var yaw = (origin.Yaw + 90) * math.Pi / 180
+	var pitch = -origin.Pitch * math.Pi / 180
+	var upPitch = (-origin.Pitch + 90) * math.Pi / 180
+
+	var forwardX, forwardY, forwardZ = math.Cos(yaw) * math.Cos(pitch), math.Sin(pitch), math.Sin(yaw) * math.Cos(pitch)
+	var upX, upY, upZ = math.Cos(yaw) * math.Cos(upPitch), math.Sin(upPitch), math.Sin(yaw) * math.Cos(upPitch)
+	// The left vector is the cross product of the up and forward vectors.
+	var leftX, leftY, leftZ = upY*forwardZ - upZ*forwardY, upZ*forwardX - upX*forwardZ, upX*forwardY - upY*forwardX
+
+	return selectors.Vector{
+		X: origin.Position.X + forwardX*forward + upX*up + leftX*left,
+		Y: origin.Position.Y + forwardY*forward + upY*up + leftY*left,
+		Z: origin.Position.Z + forwardZ*forward + upZ*up + leftZ*left,
+	}
+}
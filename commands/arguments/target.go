@@ -0,0 +1,78 @@
+package arguments
+
+import (
+	"errors"
+
+	"github.com/irmine/gomine/commands/selectors"
+)
+
+// NoTargets gets returned when parsing a target selector that matched no targets.
+var NoTargets = errors.New("no targets matched the selector")
+
+// PlayerNotOnline gets returned when parsing the name of a player that is not online.
+var PlayerNotOnline = errors.New("no player with that name is online")
+
+// NewTarget returns a new Target argument with the given name and optional value.
+// The argument accepts a target selector, such as @e[r=10], or the name of an online player, as accepted by Player arguments.
+// Its value is a []*selectors.Target with the players and entities selected, resolved relative to the sender.
+func NewTarget(name string, optional bool, source selectors.Source) *Argument {
+	return newTargetArgument(name, optional, source, false)
+}
+
+// NewPlayerTarget returns a new Player Target argument with the given name and optional value.
+// It is like a Target argument, but only selects players, so that the value of every target is a player.
+func NewPlayerTarget(name string, optional bool, source selectors.Source) *Argument {
+	return newTargetArgument(name, optional, source, true)
+}
+
+// newTargetArgument returns a new argument resolving targets against the source.
+func newTargetArgument(name string, optional bool, source selectors.Source, playersOnly bool) *Argument {
+	var arg = &Argument{name: name, optional: optional, inputArgs: 1, output: []*selectors.Target{}, validationFunction: func(value string) bool {
+		if selectors.IsSelector(value) {
+			var selector, err = selectors.Parse(value)
+			return err == nil && selector.CheckArguments(source) == nil
+		}
+		return value != ""
+	}, conversionFunction: func(value string) interface{} {
+		return value
+	}, typeName: "target"}
+	if playersOnly {
+		arg.typeName = "player"
+	}
+	arg.parseFunction = func(sender interface{}, value string) (interface{}, error) {
+		return resolveTargets(source, sender, value, playersOnly)
+	}
+	return arg
+}
+
+// resolveTargets resolves the selector or player name relative to the sender.
+func resolveTargets(source selectors.Source, sender interface{}, value string, playersOnly bool) ([]*selectors.Target, error) {
+	if !selectors.IsSelector(value) {
+		var player, err = findPlayer(source, value)
+		if err != nil {
+			return nil, err
+		}
+		return []*selectors.Target{player}, nil
+	}
+	var selector, err = selectors.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+	if err := selector.CheckArguments(source); err != nil {
+		return nil, err
+	}
+	var targets = selector.Resolve(source, sender)
+	if playersOnly {
+		var players = targets[:0]
+		for _, target := range targets {
+			if target.Player {
+				players = append(players, target)
+			}
+		}
+		targets = players
+	}
+	if len(targets) == 0 {
+		return nil, NoTargets
+	}
+	return targets, nil
+}
@@ -1,40 +1,53 @@
 package commands
 
 import (
-	"reflect"
-	"strconv"
+	"errors"
 	"strings"
 
 	"github.com/irmine/gomine/commands/arguments"
 	"github.com/irmine/gomine/text"
 )
 
+// PermissionDenied gets returned when executing a command without having its permission.
+var PermissionDenied = errors.New("you do not have permission to execute this command")
+
 type Command struct {
-	name              string
-	description       string
-	permission        string
-	aliases           []string
-	arguments         []*arguments.Argument
-	argumentTypes     []string
-	usage             string
-	permissionExempt  bool
-	executionFunction interface{}
+	name             string
+	description      string
+	permission       string
+	aliases          []string
+	overloads        []*Overload
+	subcommands      []*Command
+	parent           *Command
+	permissionExempt bool
 }
 
 // NewCommand returns a new command with the given command function.
 // The permission used in the command should be registered in order to get correct output.
+// The function may be nil for commands that only have subcommands.
 func NewCommand(name string, description string, permission string, aliases []string, function interface{}) *Command {
-	if reflect.TypeOf(function).Kind() != reflect.Func {
-		function = func() {}
+	var command = &Command{name: name, permission: permission, aliases: aliases, description: description}
+	if function != nil {
+		command.AddOverload(function)
 	}
-	return &Command{name: name, permission: permission, aliases: aliases, description: description, executionFunction: function}
+	return command
 }
 
-// GetUsage returns the usage of this command.
-// The usage will get parsed if it had not yet been.
+// GetUsage returns the usage of this command, with a line for every overload and subcommand.
 func (command *Command) GetUsage() string {
-	command.parseUsage()
-	return command.usage
+	return text.Yellow + "Usage: " + strings.Join(command.getUsageLines(), "\n")
+}
+
+// getUsageLines returns the usage of every overload of this command and its subcommands.
+func (command *Command) getUsageLines() []string {
+	var lines []string
+	for _, overload := range command.overloads {
+		lines = append(lines, strings.TrimSpace("/"+command.GetPath()+" "+overload.GetUsage()))
+	}
+	for _, subcommand := range command.subcommands {
+		lines = append(lines, subcommand.getUsageLines()...)
+	}
+	return lines
 }
 
 // ExemptFromPermissionCheck sets the command exempted from permission checking, allowing anybody to use it.
@@ -47,11 +60,31 @@ func (command *Command) IsPermissionChecked() bool {
 	return !command.permissionExempt
 }
 
+// CanExecute checks if the sender has the permission to execute this command.
+// Subcommands without a permission only require the permission of their parent.
+func (command *Command) CanExecute(sender Sender) bool {
+	if command.parent != nil && !command.parent.CanExecute(sender) {
+		return false
+	}
+	if !command.IsPermissionChecked() || (command.parent != nil && command.permission == "") {
+		return true
+	}
+	return sender.HasPermission(command.permission)
+}
+
 // GetName returns the command name.
 func (command *Command) GetName() string {
 	return command.name
 }
 
+// GetPath returns the name of the command prefixed by the names of its parents, for example "perm group add".
+func (command *Command) GetPath() string {
+	if command.parent == nil {
+		return command.name
+	}
+	return command.parent.GetPath() + " " + command.name
+}
+
 // GetDescription returns the command description.
 func (command *Command) GetDescription() string {
 	return command.description
@@ -77,131 +110,136 @@ func (command *Command) GetAliases() []string {
 	return command.aliases
 }
 
-// GetArguments returns a slice with all arguments.
-func (command *Command) GetArguments() []*arguments.Argument {
-	return command.arguments
+// GetParent returns the command this command is a subcommand of, or nil if it is not a subcommand.
+func (command *Command) GetParent() *Command {
+	return command.parent
 }
 
-// SetArguments sets the command arguments.
-func (command *Command) SetArguments(arguments []*arguments.Argument) {
-	command.arguments = arguments
+// AddSubcommand adds a subcommand to this command, for example "add" to "perm group".
+// Subcommands are matched by their name and aliases before the overloads of this command.
+func (command *Command) AddSubcommand(subcommand *Command) {
+	subcommand.parent = command
+	command.subcommands = append(command.subcommands, subcommand)
 }
 
-// AppendArgument adds one argument to the command.
-func (command *Command) AppendArgument(argument *arguments.Argument) {
-	command.argumentTypes = append(command.argumentTypes, reflect.TypeOf(argument.GetOutput()).Name())
-
-	command.arguments = append(command.arguments, argument)
+// GetSubcommands returns all subcommands of this command.
+func (command *Command) GetSubcommands() []*Command {
+	return command.subcommands
 }
 
-// parseUsage parses the usage into a readable and clear one.
-func (command *Command) parseUsage() {
-	if command.usage == "" {
-		var usage = text.Yellow + "Usage: /" + command.GetName() + " "
-		for index, argument := range command.GetArguments() {
-			if argument.IsOptional() {
-				usage += "["
-			} else {
-				usage += "<"
-			}
-
-			usage += argument.GetName() + ": " + command.argumentTypes[index]
-			if argument.GetInputAmount() > 1 && command.argumentTypes[index] != "string" {
-				usage += "(" + strconv.Itoa(argument.GetInputAmount()) + ")"
-			}
-
-			if argument.IsOptional() {
-				usage += "]"
-			} else {
-				usage += ">"
+// GetSubcommand returns the subcommand with the given name or alias, and a bool indicating success.
+func (command *Command) GetSubcommand(name string) (*Command, bool) {
+	for _, subcommand := range command.subcommands {
+		if strings.EqualFold(subcommand.name, name) {
+			return subcommand, true
+		}
+		for _, alias := range subcommand.aliases {
+			if strings.EqualFold(alias, name) {
+				return subcommand, true
 			}
-			usage += " "
 		}
-		command.usage = usage
 	}
+	return nil, false
 }
 
-// Execute executes the command with the given sender and command arguments.
-func (command *Command) Execute(sender Sender, commandArgs []string) {
-	if _, ok := command.parse(sender, commandArgs); !ok {
-		return
-	}
-	command.parseArgsAndExecute(sender)
+// AddOverload adds an overload with the given command function and arguments to this command.
+// Overloads are tried in the order they were added, and the first overload matching the arguments gets executed.
+func (command *Command) AddOverload(function interface{}, args ...*arguments.Argument) *Overload {
+	var overload = NewOverload(function, args...)
+	command.overloads = append(command.overloads, overload)
+	return overload
+}
+
+// GetOverloads returns all overloads of this command.
+func (command *Command) GetOverloads() []*Overload {
+	return command.overloads
 }
 
-// Parse checks and parses the values of a command.
-func (command *Command) parse(sender Sender, commandArgs []string) ([]*arguments.Argument, bool) {
-	if command.IsPermissionChecked() && !sender.HasPermission(command.GetPermission()) {
-		sender.SendMessage("You do not have permission to execute this command.")
-		return []*arguments.Argument{}, false
+// getDefaultOverload returns the first overload of the command, adding one if the command has none.
+func (command *Command) getDefaultOverload() *Overload {
+	if len(command.overloads) == 0 {
+		command.AddOverload(func() {})
 	}
+	return command.overloads[0]
+}
 
-	var stringIndex = 0
-	if len(commandArgs) == 0 {
-		if len(command.GetArguments()) == 0 {
-			return command.GetArguments(), true
-		}
-		sender.SendMessage(command.GetUsage())
-		return nil, false
+// GetArguments returns a slice with all arguments of the first overload.
+func (command *Command) GetArguments() []*arguments.Argument {
+	if len(command.overloads) == 0 {
+		return nil
 	}
-	for _, argument := range command.arguments {
-		var i = 0
-		var output []string
-
-		for i < argument.GetInputAmount() {
-			if len(commandArgs) < stringIndex+i+1 {
-				if !argument.IsOptional() {
-					sender.SendMessage(command.GetUsage())
-					return nil, false
-				}
-			} else {
-				commandArgs[stringIndex+i] = strings.TrimSpace(commandArgs[stringIndex+i])
-
-				if !argument.IsValidValue(commandArgs[stringIndex+i]) {
-					sender.SendMessage(command.GetUsage())
-					return nil, false
-				}
-				output = append(output, commandArgs[stringIndex+i])
-			}
-			i++
-		}
-		stringIndex += i
-		var processedOutput []interface{}
+	return command.overloads[0].GetArguments()
+}
 
-		for _, value := range output {
-			processedOutput = append(processedOutput, argument.ConvertValue(value))
-		}
+// SetArguments sets the arguments of the first overload.
+func (command *Command) SetArguments(arguments []*arguments.Argument) {
+	command.getDefaultOverload().SetArguments(arguments)
+}
+
+// AppendArgument adds one argument to the first overload.
+func (command *Command) AppendArgument(argument *arguments.Argument) {
+	command.getDefaultOverload().AppendArgument(argument)
+}
 
-		if argument.ShouldMerge() {
-			argument.SetOutput(strings.Join(output, " "))
+// Execute executes the command with the given sender and command arguments.
+// If the arguments could not be parsed, the sender gets sent the error and the usage of the command,
+// and the error is returned.
+func (command *Command) Execute(sender Sender, commandArgs []string) error {
+	var ctx, err = command.Parse(sender, commandArgs)
+	if err != nil {
+		if parseErr, ok := err.(*ParseError); ok {
+			sender.SendMessage(text.Red + parseErr.Error() + "\n" + parseErr.GetCommand().GetUsage())
 		} else {
-			if len(processedOutput) == 1 {
-				argument.SetOutput(processedOutput[0])
-			} else {
-				argument.SetOutput(processedOutput)
-			}
+			sender.SendMessage("You do not have permission to execute this command.")
+		}
+		return err
+	}
+	ctx.execute()
+	return nil
+}
+
+// Parse parses the command arguments into a new context, which may be executed by the sender.
+// The arguments are matched against the subcommands and overloads of the command.
+// PermissionDenied is returned if the sender may not execute the command,
+// and a *ParseError if the arguments match no overload.
+func (command *Command) Parse(sender Sender, commandArgs []string) (*Context, error) {
+	var input = []string{command.name}
+	for _, arg := range commandArgs {
+		if arg = strings.TrimSpace(arg); arg != "" {
+			input = append(input, arg)
 		}
 	}
-	return command.GetArguments(), true
+	return command.parse(sender, input, 1)
 }
 
-// ParseArgsAndExecute parses the arguments into an output able to be typed against.
-// After parsing, the command gets called.
-func (command *Command) parseArgsAndExecute(sender Sender) {
-	var method = reflect.ValueOf(command.executionFunction)
-	var input = make([]reflect.Value, method.Type().NumIn())
-
-	var argOffset = 0
-	for i := 0; i < method.Type().NumIn(); i++ {
-
-		if method.Type().In(i).String() == "commands.Sender" {
-			input[i] = reflect.ValueOf(sender)
-			continue
+// parse parses the input from the given index onwards.
+// The error of the overload that parsed the most input is returned if none of the overloads match.
+func (command *Command) parse(sender Sender, input []string, index int) (*Context, error) {
+	if !command.CanExecute(sender) {
+		return nil, PermissionDenied
+	}
+	if index < len(input) {
+		if subcommand, ok := command.GetSubcommand(input[index]); ok {
+			return subcommand.parse(sender, input, index+1)
 		}
-
-		input[i] = reflect.ValueOf(command.arguments[argOffset].GetOutput())
-		argOffset++
+	}
+	if len(command.overloads) == 0 {
+		if index < len(input) {
+			return nil, newParseError(command, input, index, "Unknown subcommand \""+input[index]+"\"")
+		}
+		return nil, newParseError(command, input, index, "Missing subcommand")
 	}
 
-	method.Call(input)
+	var furthest *ParseError
+	for _, overload := range command.overloads {
+		var values, err = overload.parse(sender, input, index)
+		if err == nil {
+			return &Context{sender: sender, command: command, overload: overload, input: input, values: values}, nil
+		}
+		if furthest == nil || err.Index > furthest.Index {
+			furthest = err
+		}
+	}
+	furthest.command = command
+	return nil, furthest
 }
@@ -0,0 +1,194 @@
+package commands
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/irmine/gomine/commands/arguments"
+	"github.com/irmine/gomine/commands/selectors"
+	"github.com/irmine/gomine/text"
+)
+
+type testSender struct {
+	permissions map[string]bool
+	messages    []string
+}
+
+func (sender *testSender) HasPermission(permission string) bool {
+	return sender.permissions[permission]
+}
+
+func (sender *testSender) SendMessage(message ...interface{}) {
+	for _, m := range message {
+		sender.messages = append(sender.messages, m.(string))
+	}
+}
+
+func newTestSender(permissions ...string) *testSender {
+	var sender = &testSender{permissions: make(map[string]bool)}
+	for _, permission := range permissions {
+		sender.permissions[permission] = true
+	}
+	return sender
+}
+
+func TestOverloads(t *testing.T) {
+	var calls []string
+	var command = NewCommand("give", "", "give", nil, func(sender Sender, amount int) {
+		calls = append(calls, "self:"+strconv.Itoa(amount))
+	})
+	command.AppendArgument(arguments.NewInt("amount", false))
+	command.AddOverload(func(ctx *Context, player string, amount int) {
+		if ctx.IsSet("amount") {
+			calls = append(calls, player+":"+strconv.Itoa(amount))
+		} else {
+			calls = append(calls, player)
+		}
+	}, arguments.NewString("player", false), arguments.NewInt("amount", true))
+
+	var sender = newTestSender("give")
+	for _, args := range [][]string{{"3"}, {"Steve", "3"}, {"Steve"}} {
+		if err := command.Execute(sender, args); err != nil {
+			t.Errorf("expected %v to execute, got %v", args, err)
+		}
+	}
+	var expected = []string{"self:3", "Steve:3", "Steve"}
+	if strings.Join(calls, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected calls %v, got %v", expected, calls)
+	}
+	if err := command.Execute(newTestSender(), []string{"3"}); err != PermissionDenied {
+		t.Errorf("expected the permission to be checked, got %v", err)
+	}
+}
+
+func TestSubcommands(t *testing.T) {
+	var added []string
+	var perm = NewCommand("perm", "", "perm", nil, nil)
+	var group = NewCommand("group", "", "", []string{"g"}, nil)
+	var add = NewCommand("add", "", "perm.group.add", nil, func(ctx *Context, group string, player string) {
+		added = append(added, group+":"+player)
+	})
+	add.AppendArgument(arguments.NewString("group", false))
+	add.AppendArgument(arguments.NewString("player", false))
+	perm.AddSubcommand(group)
+	group.AddSubcommand(add)
+
+	if err := perm.Execute(newTestSender("perm", "perm.group.add"), []string{"g", "add", "admin", "Steve"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(added) != 1 || added[0] != "admin:Steve" {
+		t.Errorf("expected the subcommand to be executed, got %v", added)
+	}
+	if add.GetPath() != "perm group add" {
+		t.Errorf("expected path perm group add, got %v", add.GetPath())
+	}
+	if err := perm.Execute(newTestSender("perm"), []string{"group", "add", "admin", "Steve"}); err != PermissionDenied {
+		t.Errorf("expected the permission of the subcommand to be checked, got %v", err)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	var perm = NewCommand("perm", "", "", nil, nil)
+	var add = NewCommand("add", "", "", nil, func(player string, level int) {})
+	add.AppendArgument(arguments.NewString("player", false))
+	add.AppendArgument(arguments.NewInt("level", false))
+	perm.AddSubcommand(add)
+	perm.ExemptFromPermissionCheck(true)
+
+	var tests = []struct {
+		args    []string
+		index   int
+		token   string
+		message string
+	}{
+		{[]string{"remove"}, 1, "remove", "Unknown subcommand \"remove\" at /perm >>remove<<"},
+		{[]string{}, 1, "", "Missing subcommand at /perm >><<"},
+		{[]string{"add", "Steve", "high"}, 3, "high", "Invalid value \"high\" for <level: int> at /perm add Steve >>high<<"},
+		{[]string{"add", "Steve"}, 3, "", "Missing argument <level: int> at /perm add Steve >><<"},
+		{[]string{"add", "Steve", "1", "extra"}, 4, "extra", "Unexpected argument \"extra\" at /perm add Steve 1 >>extra<<"},
+	}
+	for _, test := range tests {
+		var _, err = perm.Parse(newTestSender(), test.args)
+		var parseErr, ok = err.(*ParseError)
+		if !ok {
+			t.Errorf("expected a parse error for %v, got %v", test.args, err)
+			continue
+		}
+		if parseErr.Index != test.index || parseErr.GetToken() != test.token || parseErr.Error() != test.message {
+			t.Errorf("expected error %q at %v, got %q at %v", test.message, test.index, parseErr.Error(), parseErr.Index)
+		}
+	}
+}
+
+func TestContextsAreIndependent(t *testing.T) {
+	var command = NewCommand("tell", "", "", nil, func() {})
+	command.AppendArgument(arguments.NewString("player", false))
+	command.ExemptFromPermissionCheck(true)
+
+	var first, _ = command.Parse(newTestSender(), []string{"Steve"})
+	var second, _ = command.Parse(newTestSender(), []string{"Alex"})
+	if value, _ := first.Get("player"); value != "Steve" {
+		t.Errorf("expected the first context to keep its own value, got %v", value)
+	}
+	if value, _ := second.Get("player"); value != "Alex" {
+		t.Errorf("expected the second context to keep its own value, got %v", value)
+	}
+}
+
+func TestUsage(t *testing.T) {
+	var command = NewCommand("whitelist", "", "", nil, nil)
+	var add = NewCommand("add", "", "", nil, func() {})
+	add.AppendArgument(arguments.NewString("player", false))
+	command.AddSubcommand(NewCommand("list", "", "", nil, func() {}))
+	command.AddSubcommand(add)
+
+	var sender = newTestSender()
+	command.ExemptFromPermissionCheck(true)
+	command.Execute(sender, []string{"add"})
+	if len(sender.messages) != 1 || !strings.Contains(sender.messages[0], "Usage: /whitelist add <player: string>") {
+		t.Errorf("expected the usage of the subcommand to be sent, got %v", sender.messages)
+	}
+	if !strings.Contains(command.GetUsage(), "/whitelist list\n/whitelist add <player: string>") {
+		t.Errorf("expected the usage of all subcommands, got %v", command.GetUsage())
+	}
+}
+
+type testSource struct {
+	players []*selectors.Target
+}
+
+func (source testSource) GetOrigin(sender interface{}) selectors.Origin {
+	return selectors.Origin{}
+}
+
+func (source testSource) GetPlayers() []*selectors.Target {
+	return source.players
+}
+
+func (source testSource) GetEntities(dimension interface{}) []*selectors.Target {
+	return []*selectors.Target{{Type: "zombie"}}
+}
+
+func TestTargetArgument(t *testing.T) {
+	var source = testSource{players: []*selectors.Target{{Name: "Steve", Player: true}, {Name: "Alex", Player: true}}}
+	var selected []string
+	var command = NewCommand("kill", "", "", nil, func(targets []*selectors.Target) {
+		for _, target := range targets {
+			selected = append(selected, target.Name)
+		}
+	})
+	command.AppendArgument(arguments.NewPlayerTarget("player", false, source))
+	command.ExemptFromPermissionCheck(true)
+
+	var sender = newTestSender()
+	for _, args := range [][]string{{"steve"}, {"@a"}, {"@e[type=zombie]"}, {"Notch"}} {
+		command.Execute(sender, args)
+	}
+	if strings.Join(selected, ",") != "Steve,Steve,Alex" {
+		t.Errorf("expected only players to be selected, got %v", selected)
+	}
+	if len(sender.messages) != 2 || !strings.HasPrefix(sender.messages[1], text.Red+"Invalid value \"Notch\" for <player: player>: "+arguments.PlayerNotOnline.Error()) {
+		t.Errorf("expected errors for the selector without players and the offline player, got %v", sender.messages)
+	}
+}
@@ -0,0 +1,100 @@
+package commands
+
+import (
+	"reflect"
+	"strings"
+)
+
+var (
+	senderType  = reflect.TypeOf((*Sender)(nil)).Elem()
+	contextType = reflect.TypeOf((*Context)(nil))
+)
+
+// Context holds the state of a single execution of a command.
+// A new context is created every time a command gets parsed,
+// so that senders executing the same command at once never share parsed values.
+type Context struct {
+	sender   Sender
+	command  *Command
+	overload *Overload
+	input    []string
+	values   []interface{}
+}
+
+// GetSender returns the sender executing the command.
+func (ctx *Context) GetSender() Sender {
+	return ctx.sender
+}
+
+// GetCommand returns the command being executed.
+// This is the subcommand if a subcommand was matched.
+func (ctx *Context) GetCommand() *Command {
+	return ctx.command
+}
+
+// GetOverload returns the overload of the command the input matched.
+func (ctx *Context) GetOverload() *Overload {
+	return ctx.overload
+}
+
+// GetInput returns the command line that was parsed, without leading slash.
+func (ctx *Context) GetInput() string {
+	return strings.Join(ctx.input, " ")
+}
+
+// Get returns the value of the argument with the given name, and a bool indicating if it was set.
+// Optional arguments without input are not set.
+func (ctx *Context) Get(name string) (interface{}, bool) {
+	for i, argument := range ctx.overload.GetArguments() {
+		if argument.GetName() == name {
+			return ctx.values[i], ctx.values[i] != nil
+		}
+	}
+	return nil, false
+}
+
+// IsSet checks if the argument with the given name was set.
+func (ctx *Context) IsSet(name string) bool {
+	var _, ok = ctx.Get(name)
+	return ok
+}
+
+// execute calls the function of the overload with the parsed values.
+// Parameters of optional arguments that were not set get their zero value.
+func (ctx *Context) execute() {
+	var method = reflect.ValueOf(ctx.overload.executionFunction)
+	var methodType = method.Type()
+	var input = make([]reflect.Value, methodType.NumIn())
+
+	var argOffset = 0
+	for i := range input {
+		var parameter = methodType.In(i)
+		switch parameter {
+		case senderType:
+			input[i] = reflect.ValueOf(&ctx.sender).Elem()
+		case contextType:
+			input[i] = reflect.ValueOf(ctx)
+		default:
+			input[i] = reflect.Zero(parameter)
+			if argOffset < len(ctx.values) && ctx.values[argOffset] != nil {
+				input[i] = convertValue(reflect.ValueOf(ctx.values[argOffset]), parameter)
+			}
+			argOffset++
+		}
+	}
+	method.Call(input)
+}
+
+// convertValue converts numeric values to the numeric type of the parameter, for example int64 to int.
+// Other values are passed as is.
+func convertValue(value reflect.Value, parameter reflect.Type) reflect.Value {
+	if !value.Type().AssignableTo(parameter) && isNumeric(value.Kind()) && isNumeric(parameter.Kind()) {
+		return value.Convert(parameter)
+	}
+	return value
+}
+
+// isNumeric checks if the kind is an integer or floating point kind.
+func isNumeric(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Float64
+}
@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"strings"
+)
+
+// ParseError is returned when the input of a command could not be parsed.
+// It points at the token of the input that could not be parsed.
+type ParseError struct {
+	// Message describes why the token could not be parsed.
+	Message string
+	// Input is the command line split into tokens, starting with the name of the command.
+	Input []string
+	// Index is the index of the token in the input that could not be parsed.
+	// It equals the length of the input if input was missing.
+	Index int
+
+	command *Command
+}
+
+// newParseError returns a new parse error pointing at the token with the given index.
+func newParseError(command *Command, input []string, index int, message string) *ParseError {
+	return &ParseError{Message: message, Input: input, Index: index, command: command}
+}
+
+// GetCommand returns the command or subcommand the input was being parsed for.
+func (err *ParseError) GetCommand() *Command {
+	return err.command
+}
+
+// GetToken returns the token that could not be parsed, or an empty string if input was missing.
+func (err *ParseError) GetToken() string {
+	if err.Index >= len(err.Input) {
+		return ""
+	}
+	return err.Input[err.Index]
+}
+
+// Error returns the message of the error, followed by the input with the failing token marked.
+// For example: Invalid value "abc" for <amount: int> at /give Steve >>abc<<
+func (err *ParseError) Error() string {
+	var before = strings.Join(err.Input[:err.Index], " ")
+	var after = ""
+	if err.Index+1 < len(err.Input) {
+		after = " " + strings.Join(err.Input[err.Index+1:], " ")
+	}
+	return err.Message + " at /" + before + " >>" + err.GetToken() + "<<" + after
+}
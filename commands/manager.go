@@ -7,11 +7,23 @@ import (
 type Manager struct {
 	commands map[string]*Command
 	aliases  map[string]*Command
+	revision int
 }
 
 // NewManager returns a new Manager struct.
 func NewManager() *Manager {
-	return &Manager{make(map[string]*Command), make(map[string]*Command)}
+	return &Manager{make(map[string]*Command), make(map[string]*Command), 0}
+}
+
+// GetCommands returns a name => command map of all registered commands.
+func (holder *Manager) GetCommands() map[string]*Command {
+	return holder.commands
+}
+
+// GetRevision returns the revision of the registered commands.
+// The revision changes every time a command gets registered or deregistered.
+func (holder *Manager) GetRevision() int {
+	return holder.revision
 }
 
 // IsCommandRegistered checks if the command has been registered.
@@ -33,6 +45,7 @@ func (holder *Manager) DeregisterCommand(commandName string) bool {
 		holder.deregisterAlias(alias)
 	}
 	delete(holder.commands, commandName)
+	holder.revision++
 	return true
 }
 
@@ -68,6 +81,7 @@ func (holder *Manager) RegisterCommand(command *Command) {
 	for _, alias := range command.GetAliases() {
 		holder.registerAlias(alias, command)
 	}
+	holder.revision++
 }
 
 // AliasExists checks if the given alias exists or not.
@@ -0,0 +1,95 @@
+package commands
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/irmine/gomine/commands/arguments"
+)
+
+// Overload is a list of arguments a command accepts, with the function executed if the arguments match.
+// Arguments only describe the input they accept, so that a single overload can be parsed by many senders at once.
+type Overload struct {
+	arguments         []*arguments.Argument
+	executionFunction interface{}
+}
+
+// NewOverload returns a new overload with the given command function and arguments.
+// The function gets passed the sender if it takes a commands.Sender, the context if it takes a *commands.Context,
+// and the values of the arguments in order for all other parameters.
+func NewOverload(function interface{}, args ...*arguments.Argument) *Overload {
+	if function == nil || reflect.TypeOf(function).Kind() != reflect.Func {
+		function = func() {}
+	}
+	return &Overload{arguments: args, executionFunction: function}
+}
+
+// GetArguments returns a slice with all arguments of the overload.
+func (overload *Overload) GetArguments() []*arguments.Argument {
+	return overload.arguments
+}
+
+// SetArguments sets the arguments of the overload.
+func (overload *Overload) SetArguments(arguments []*arguments.Argument) {
+	overload.arguments = arguments
+}
+
+// AppendArgument adds one argument to the overload.
+func (overload *Overload) AppendArgument(argument *arguments.Argument) {
+	overload.arguments = append(overload.arguments, argument)
+}
+
+// GetUsage returns the usage of the arguments of the overload, for example "<player: string> [reason: string]".
+func (overload *Overload) GetUsage() string {
+	var usages = make([]string, len(overload.arguments))
+	for i, argument := range overload.arguments {
+		usages[i] = argument.GetUsage()
+	}
+	return strings.Join(usages, " ")
+}
+
+// parse parses the input from the given index onwards into the values of the arguments of the overload, for the sender.
+// Values of optional arguments without input are nil.
+// A parse error is returned if the input is invalid, or if input remains after all arguments.
+func (overload *Overload) parse(sender Sender, input []string, index int) ([]interface{}, *ParseError) {
+	var values = make([]interface{}, len(overload.arguments))
+	for i, argument := range overload.arguments {
+		var end = index + argument.GetInputAmount()
+		if end > len(input) {
+			end = len(input)
+		}
+		var tokens = input[index:end]
+		if len(tokens) < argument.GetRequiredInputAmount() && !argument.IsOptional() {
+			return nil, newParseError(nil, input, end, "Missing argument "+argument.GetUsage())
+		}
+		if len(tokens) == 0 {
+			continue
+		}
+		var value, err = argument.ParseTokens(sender, tokens)
+		if err != nil {
+			var tokenErr = err.(*arguments.TokenError)
+			var token = tokens[tokenErr.Index]
+			if argument.GetInputAmount() > 1 && tokenErr.Err != arguments.InvalidValue {
+				token = strings.Join(tokens, " ")
+			}
+			return nil, newParseError(nil, input, index+tokenErr.Index, getInvalidValueMessage(argument, token, tokenErr.Err))
+		}
+		values[i] = value
+		index = end
+	}
+	if index < len(input) {
+		return nil, newParseError(nil, input, index, "Unexpected argument "+strconv.Quote(input[index]))
+	}
+	return values, nil
+}
+
+// getInvalidValueMessage returns the message of the parse error of an invalid value of the argument.
+// The error of the argument is included, unless it only states the value is invalid.
+func getInvalidValueMessage(argument *arguments.Argument, value string, err error) string {
+	var message = "Invalid value " + strconv.Quote(value) + " for " + argument.GetUsage()
+	if err != arguments.InvalidValue {
+		message += ": " + err.Error()
+	}
+	return message
+}
@@ -1,5 +1,16 @@
+// Package selectors implements target selectors, such as @a[r=10,name=Steve], used to select players and entities in commands.
+// Selectors are parsed once, and may be resolved many times against the players and entities provided by a source.
 package selectors
 
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+)
+
 const (
 	NearestPlayer = "@p"
 	RandomPlayer  = "@r"
@@ -8,11 +19,396 @@ const (
 	Self          = "@s"
 )
 
+// UnknownVariable gets returned when parsing a selector that does not start with a known variable.
+var UnknownVariable = errors.New("unknown selector variable, expected @p, @r, @a, @e or @s")
+
+// MalformedArguments gets returned when parsing a selector of which the arguments are not closed,
+// or are not in the key=value format.
+var MalformedArguments = errors.New("selector arguments must be key=value pairs between brackets")
+
+// Vector is a position in a dimension.
+type Vector struct {
+	X, Y, Z float64
+}
+
+// Target is a player or entity a selector may select.
+type Target struct {
+	// Name is the name of the player, or empty for entities.
+	Name string
+	// Type is the type of the entity, which is "player" for players.
+	Type string
+	// Player is true if the target is a player.
+	Player bool
+	// GameMode is the game mode of the player, or -1 for entities.
+	GameMode int
+	// Tags are the tags of the target.
+	Tags []string
+	// Position is the position of the target in its dimension.
+	Position Vector
+	// Dimension is the dimension of the target. It is only compared against the dimension of the origin.
+	Dimension interface{}
+	// Value is the player or entity the target is, for example the session of a player.
+	Value interface{}
+}
+
+// Origin is the point a selector is resolved relative to.
+type Origin struct {
+	// Position is the position distances are measured from.
+	Position Vector
+	// Dimension is the dimension of the sender.
+	// Selectors with positional arguments only select targets in this dimension.
+	Dimension interface{}
+	// Yaw and Pitch are the rotation of the sender in degrees, which local coordinates are relative to.
+	Yaw, Pitch float64
+	// Self is the target of the sender itself, or nil if the sender is not a player or entity, such as the console.
+	Self *Target
+}
+
+// Source provides the targets a selector is resolved against.
+type Source interface {
+	// GetOrigin returns the origin of the sender of a command.
+	GetOrigin(sender interface{}) Origin
+	// GetPlayers returns the targets of all players online.
+	GetPlayers() []*Target
+	// GetEntities returns the targets of all entities in the dimension, excluding players.
+	GetEntities(dimension interface{}) []*Target
+}
+
+// ArgumentSource is a source that does not support every selector argument,
+// for example a source of which the targets have no tags.
+type ArgumentSource interface {
+	Source
+	// SupportsArgument checks if selectors with the argument, such as tag, can be resolved against the source.
+	SupportsArgument(key string) bool
+}
+
 type TargetSelector struct {
 	variable  string
-	arguments map[string]string
+	arguments map[string][]string
+	criteria  criteria
+}
+
+// criteria are the parsed arguments of a selector.
+type criteria struct {
+	position     [3]*float64
+	relative     [3]bool
+	volume       [3]*float64
+	radius       *float64
+	minRadius    *float64
+	count        int
+	gameModes    []filter
+	names        []filter
+	tags         []filter
+	types        []filter
+	isPositional bool
+}
+
+// filter is a value a target is matched against, which may be inverted with an exclamation mark.
+type filter struct {
+	value    string
+	inverted bool
 }
 
 func NewTargetSelector(variable string) *TargetSelector {
-	return &TargetSelector{variable, make(map[string]string)}
+	return &TargetSelector{variable, make(map[string][]string), criteria{}}
+}
+
+// IsSelector checks if the value looks like a selector, which means it starts with an @.
+func IsSelector(value string) bool {
+	return strings.HasPrefix(value, "@")
+}
+
+// Parse parses a selector, such as @a or @e[type=player,r=10,c=3].
+// The following arguments are supported:
+// x, y and z set the origin, which may be relative to the sender with ~.
+// r and rm select targets within a maximum and minimum radius.
+// dx, dy and dz select targets within the volume from the origin to the origin plus the offsets.
+// c limits the amount of targets, selecting the furthest targets first if negative.
+// m, name, tag and type select targets by game mode, name, tag and type,
+// and select all other targets if prefixed with an exclamation mark.
+func Parse(value string) (*TargetSelector, error) {
+	if len(value) < 2 {
+		return nil, UnknownVariable
+	}
+	var selector = NewTargetSelector(value[:2])
+	switch selector.variable {
+	case NearestPlayer, RandomPlayer, AllPlayers, AllEntities, Self:
+	default:
+		return nil, UnknownVariable
+	}
+	var rest = value[2:]
+	if rest == "" {
+		return selector, selector.parseCriteria()
+	}
+	if !strings.HasPrefix(rest, "[") || !strings.HasSuffix(rest, "]") {
+		return nil, MalformedArguments
+	}
+	rest = strings.TrimSpace(rest[1 : len(rest)-1])
+	if rest == "" {
+		return selector, selector.parseCriteria()
+	}
+	for _, pair := range strings.Split(rest, ",") {
+		var keyValue = strings.SplitN(pair, "=", 2)
+		if len(keyValue) != 2 || strings.TrimSpace(keyValue[0]) == "" {
+			return nil, MalformedArguments
+		}
+		var key = strings.ToLower(strings.TrimSpace(keyValue[0]))
+		selector.arguments[key] = append(selector.arguments[key], strings.Trim(strings.TrimSpace(keyValue[1]), "\""))
+	}
+	return selector, selector.parseCriteria()
+}
+
+// GetVariable returns the variable of the selector, for example @a.
+func (selector *TargetSelector) GetVariable() string {
+	return selector.variable
+}
+
+// CheckArguments checks if the source supports all arguments of the selector.
+// An error is returned if the source implements ArgumentSource and does not support one of the arguments.
+func (selector *TargetSelector) CheckArguments(source Source) error {
+	var argumentSource, ok = source.(ArgumentSource)
+	if !ok {
+		return nil
+	}
+	for key := range selector.arguments {
+		if !argumentSource.SupportsArgument(key) {
+			return errors.New("selector argument " + strconv.Quote(key) + " is not supported")
+		}
+	}
+	return nil
+}
+
+// GetArgument returns the value of the argument with the given key, and a bool indicating if it was set.
+// The last value is returned if the argument was set multiple times.
+func (selector *TargetSelector) GetArgument(key string) (string, bool) {
+	var values = selector.arguments[key]
+	if len(values) == 0 {
+		return "", false
+	}
+	return values[len(values)-1], true
+}
+
+// parseCriteria parses the arguments of the selector into criteria targets are matched against.
+func (selector *TargetSelector) parseCriteria() error {
+	var c = &selector.criteria
+	for key, values := range selector.arguments {
+		var value = values[len(values)-1]
+		var err error
+		switch key {
+		case "x", "y", "z":
+			var axis = strings.Index("xyz", key)
+			c.relative[axis] = strings.HasPrefix(value, "~")
+			if c.relative[axis] {
+				value = strings.TrimPrefix(value, "~")
+				if value == "" {
+					value = "0"
+				}
+			}
+			c.position[axis], err = parseFloat(key, value)
+		case "dx", "dy", "dz":
+			c.volume[strings.Index("xyz", key[1:])], err = parseFloat(key, value)
+		case "r":
+			c.radius, err = parseFloat(key, value)
+		case "rm":
+			c.minRadius, err = parseFloat(key, value)
+		case "c":
+			if c.count, err = strconv.Atoi(value); err != nil || c.count == 0 {
+				err = errors.New("selector argument c must be a non-zero integer")
+			}
+		case "m":
+			c.gameModes, err = parseFilters(values, parseGameMode)
+		case "name":
+			c.names, err = parseFilters(values, nil)
+		case "tag":
+			c.tags, err = parseFilters(values, nil)
+		case "type":
+			c.types, err = parseFilters(values, strings.ToLower)
+		default:
+			err = errors.New("unknown selector argument " + strconv.Quote(key))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	c.isPositional = c.radius != nil || c.minRadius != nil || c.volume != [3]*float64{} || c.position != [3]*float64{}
+	return nil
+}
+
+// Resolve returns the targets of the source selected by the selector, relative to the sender.
+// An empty slice is returned if no targets matched.
+func (selector *TargetSelector) Resolve(source Source, sender interface{}) []*Target {
+	var c = &selector.criteria
+	var origin = source.GetOrigin(sender)
+	var position = []*float64{&origin.Position.X, &origin.Position.Y, &origin.Position.Z}
+	for axis, value := range c.position {
+		if value == nil {
+			continue
+		}
+		if c.relative[axis] {
+			*position[axis] += *value
+		} else {
+			*position[axis] = *value
+		}
+	}
+
+	var candidates []*Target
+	switch selector.variable {
+	case Self:
+		if origin.Self != nil {
+			candidates = append(candidates, origin.Self)
+		}
+	case AllEntities:
+		candidates = append(source.GetPlayers(), source.GetEntities(origin.Dimension)...)
+	default:
+		candidates = source.GetPlayers()
+	}
+
+	var targets = make([]*Target, 0, len(candidates))
+	for _, target := range candidates {
+		if selector.matches(target, origin) {
+			targets = append(targets, target)
+		}
+	}
+
+	var count = c.count
+	switch selector.variable {
+	case NearestPlayer:
+		if count == 0 {
+			count = 1
+		}
+		sortByDistance(targets, origin.Position, count < 0)
+	case RandomPlayer:
+		if count == 0 {
+			count = 1
+		}
+		rand.Shuffle(len(targets), func(i, j int) {
+			targets[i], targets[j] = targets[j], targets[i]
+		})
+	default:
+		if count != 0 {
+			sortByDistance(targets, origin.Position, count < 0)
+		}
+	}
+	if count < 0 {
+		count = -count
+	}
+	if count != 0 && count < len(targets) {
+		targets = targets[:count]
+	}
+	return targets
+}
+
+// matches checks if the target matches all criteria of the selector.
+func (selector *TargetSelector) matches(target *Target, origin Origin) bool {
+	var c = &selector.criteria
+	if c.isPositional && origin.Dimension != nil && target.Dimension != origin.Dimension {
+		return false
+	}
+	var distance = getDistance(target.Position, origin.Position)
+	if c.radius != nil && distance > *c.radius {
+		return false
+	}
+	if c.minRadius != nil && distance < *c.minRadius {
+		return false
+	}
+	var from = []float64{origin.Position.X, origin.Position.Y, origin.Position.Z}
+	var at = []float64{target.Position.X, target.Position.Y, target.Position.Z}
+	for axis, offset := range c.volume {
+		if offset == nil {
+			continue
+		}
+		var min, max = math.Min(from[axis], from[axis]+*offset), math.Max(from[axis], from[axis]+*offset)
+		if at[axis] < min || at[axis] > max+1 {
+			return false
+		}
+	}
+	if !matchesFilters(c.gameModes, func(value string) bool { return value == strconv.Itoa(target.GameMode) }) {
+		return false
+	}
+	if !matchesFilters(c.names, func(value string) bool { return strings.EqualFold(value, target.Name) }) {
+		return false
+	}
+	if !matchesFilters(c.types, func(value string) bool { return value == target.Type || value == "minecraft:"+target.Type }) {
+		return false
+	}
+	return matchesFilters(c.tags, func(value string) bool {
+		if value == "" {
+			return len(target.Tags) == 0
+		}
+		for _, tag := range target.Tags {
+			if tag == value {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// matchesFilters checks if all filters match according to the match function.
+// Inverted filters match if the match function returns false.
+func matchesFilters(filters []filter, match func(value string) bool) bool {
+	for _, f := range filters {
+		if match(f.value) == f.inverted {
+			return false
+		}
+	}
+	return true
+}
+
+// parseFilters parses the values of an argument into filters.
+// The values are converted with the convert function if it is not nil.
+func parseFilters(values []string, convert func(value string) string) ([]filter, error) {
+	var filters = make([]filter, len(values))
+	for i, value := range values {
+		filters[i].inverted = strings.HasPrefix(value, "!")
+		filters[i].value = strings.TrimPrefix(value, "!")
+		if convert != nil {
+			if filters[i].value = convert(filters[i].value); filters[i].value == "" {
+				return nil, errors.New("invalid selector value " + strconv.Quote(value))
+			}
+		}
+	}
+	return filters, nil
+}
+
+// parseGameMode returns the number of the game mode with the given name or number,
+// or an empty string if the game mode is unknown.
+func parseGameMode(value string) string {
+	switch strings.ToLower(value) {
+	case "0", "s", "survival":
+		return "0"
+	case "1", "c", "creative":
+		return "1"
+	case "2", "a", "adventure":
+		return "2"
+	case "3", "sp", "spectator":
+		return "3"
+	}
+	return ""
+}
+
+// parseFloat parses the value of the argument with the given key as a float.
+func parseFloat(key string, value string) (*float64, error) {
+	var f, err = strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil, errors.New("selector argument " + key + " must be a number")
+	}
+	return &f, nil
+}
+
+// sortByDistance sorts the targets by their distance to the position, nearest first unless reversed.
+func sortByDistance(targets []*Target, position Vector, reverse bool) {
+	sort.SliceStable(targets, func(i, j int) bool {
+		var a, b = getDistance(targets[i].Position, position), getDistance(targets[j].Position, position)
+		if reverse {
+			return a > b
+		}
+		return a < b
+	})
+}
+
+// getDistance returns the distance between two positions.
+func getDistance(a Vector, b Vector) float64 {
+	var x, y, z = a.X - b.X, a.Y - b.Y, a.Z - b.Z
+	return math.Sqrt(x*x + y*y + z*z)
 }
@@ -0,0 +1,161 @@
+package selectors
+
+import (
+	"testing"
+)
+
+type testSource struct {
+	players  []*Target
+	entities []*Target
+}
+
+func (source testSource) GetOrigin(sender interface{}) Origin {
+	if target, ok := sender.(*Target); ok {
+		return Origin{Position: target.Position, Dimension: target.Dimension, Self: target}
+	}
+	return Origin{Dimension: "overworld"}
+}
+
+func (source testSource) GetPlayers() []*Target {
+	return append([]*Target{}, source.players...)
+}
+
+func (source testSource) GetEntities(dimension interface{}) []*Target {
+	var targets []*Target
+	for _, entity := range source.entities {
+		if entity.Dimension == dimension {
+			targets = append(targets, entity)
+		}
+	}
+	return targets
+}
+
+func newTestSource() testSource {
+	return testSource{
+		players: []*Target{
+			{Name: "Steve", Type: "player", Player: true, GameMode: 1, Position: Vector{X: 10}, Dimension: "overworld", Tags: []string{"red"}},
+			{Name: "Alex", Type: "player", Player: true, GameMode: 0, Position: Vector{X: 2}, Dimension: "overworld"},
+			{Name: "Notch", Type: "player", Player: true, GameMode: 1, Position: Vector{X: 1}, Dimension: "nether"},
+		},
+		entities: []*Target{
+			{Type: "zombie", GameMode: -1, Position: Vector{X: 5}, Dimension: "overworld"},
+		},
+	}
+}
+
+func TestParse(t *testing.T) {
+	for _, value := range []string{"@a", "@p[]", "@e[r=10,type=!player,c=-2]", "@a[x=~1,y=5,z=~,m=c,name=!Steve,tag=red,tag=!blue]"} {
+		if _, err := Parse(value); err != nil {
+			t.Errorf("expected %v to be parsed, got %v", value, err)
+		}
+	}
+	for _, value := range []string{"", "@", "@x", "Steve", "@a[", "@a[r]", "@a[r=far]", "@a[c=0]", "@a[m=hardcore]", "@a[foo=bar]"} {
+		if _, err := Parse(value); err == nil {
+			t.Errorf("expected %v not to be parsed", value)
+		}
+	}
+	var selector, _ = Parse("@a[name=Steve,r=5]")
+	if value, ok := selector.GetArgument("name"); selector.GetVariable() != AllPlayers || !ok || value != "Steve" {
+		t.Errorf("expected the variable and arguments to be kept, got %v %v", selector.GetVariable(), value)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	var source = newTestSource()
+	var sender = source.players[1]
+	var tests = []struct {
+		selector string
+		expected []string
+	}{
+		{"@a", []string{"Steve", "Alex", "Notch"}},
+		{"@p", []string{"Alex"}},
+		{"@s", []string{"Alex"}},
+		{"@a[r=5]", []string{"Alex"}},
+		{"@a[rm=1]", []string{"Steve"}},
+		{"@a[c=2]", []string{"Alex", "Notch"}},
+		{"@a[c=-1]", []string{"Steve"}},
+		{"@a[m=creative]", []string{"Steve", "Notch"}},
+		{"@a[m=!1]", []string{"Alex"}},
+		{"@a[name=steve]", []string{"Steve"}},
+		{"@a[name=!Steve,name=!Alex]", []string{"Notch"}},
+		{"@a[tag=red]", []string{"Steve"}},
+		{"@a[tag=]", []string{"Alex", "Notch"}},
+		{"@a[x=9,dx=2]", []string{"Steve"}},
+		{"@a[x=~7,r=1]", []string{"Steve"}},
+		{"@e[type=zombie]", []string{"zombie"}},
+		{"@e[r=4]", []string{"zombie", "Alex"}},
+		{"@e[type=!player,type=!zombie]", nil},
+	}
+	for _, test := range tests {
+		var selector, err = Parse(test.selector)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, target := range selector.Resolve(source, sender) {
+			var name = target.Name
+			if !target.Player {
+				name = target.Type
+			}
+			names = append(names, name)
+		}
+		if !equalNames(names, test.expected) {
+			t.Errorf("expected %v to select %v, got %v", test.selector, test.expected, names)
+		}
+	}
+}
+
+func TestResolveFromConsole(t *testing.T) {
+	var source = newTestSource()
+	var selector, _ = Parse("@s")
+	if targets := selector.Resolve(source, nil); len(targets) != 0 {
+		t.Errorf("expected @s to select nothing for the console, got %v targets", len(targets))
+	}
+	selector, _ = Parse("@r[c=2]")
+	if targets := selector.Resolve(source, nil); len(targets) != 2 {
+		t.Errorf("expected @r[c=2] to select 2 players, got %v", len(targets))
+	}
+}
+
+// taglessSource is a test source of which the targets have no tags.
+type taglessSource struct {
+	testSource
+}
+
+func (source taglessSource) SupportsArgument(key string) bool {
+	return key != "tag"
+}
+
+func TestCheckArguments(t *testing.T) {
+	var selector, _ = Parse("@a[tag=red,r=5]")
+	if err := selector.CheckArguments(newTestSource()); err != nil {
+		t.Errorf("expected all arguments to be supported by a source without restrictions, got %v", err)
+	}
+	if err := selector.CheckArguments(taglessSource{newTestSource()}); err == nil || err.Error() != `selector argument "tag" is not supported` {
+		t.Errorf("expected tag not to be supported, got %v", err)
+	}
+	selector, _ = Parse("@e[type=zombie,r=5]")
+	if err := selector.CheckArguments(taglessSource{newTestSource()}); err != nil {
+		t.Errorf("expected type and r to be supported, got %v", err)
+	}
+}
+
+// equalNames checks if the names are equal, ignoring order for selectors that do not sort.
+func equalNames(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	var counts = make(map[string]int)
+	for _, name := range a {
+		counts[name]++
+	}
+	for _, name := range b {
+		counts[name]--
+	}
+	for _, count := range counts {
+		if count != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -1,16 +1,27 @@
 package gomine
 
 import (
+	"fmt"
+	"github.com/irmine/gomine/auth"
 	"github.com/irmine/gomine/commands"
+	"github.com/irmine/gomine/commands/arguments"
+	"github.com/irmine/gomine/moderation"
 	"github.com/irmine/gomine/net"
 	"github.com/irmine/gomine/text"
+	"github.com/irmine/gomine/timings"
+	net2 "net"
 	"strconv"
+	"strings"
+	"time"
 )
 
-func NewTest(_ *Server) *commands.Command {
+// maximumReasonWords is the maximum amount of words in the reason of a ban.
+const maximumReasonWords = 64
+
+func NewTest(server *Server) *commands.Command {
 	cmd := commands.NewCommand("chunk", "Lists the current chunk", "none", []string{}, func(sender commands.Sender) {
 		if session, ok := sender.(*net.MinecraftSession); ok {
-			text.DefaultLogger.Debug(session.GetPlayer().GetChunk().X, session.GetPlayer().GetChunk().Z)
+			server.Logger.Debug(session.GetPlayer().GetChunk().X, session.GetPlayer().GetChunk().Z)
 			session.SendMessage(session.GetPlayer().GetChunk().X, session.GetPlayer().GetChunk().Z)
 		}
 	})
@@ -47,12 +58,372 @@ func NewPing() *commands.Command {
 	return ping
 }
 
+func NewTps(server *Server) *commands.Command {
+	var tps = commands.NewCommand("tps", "Shows the tick rate of the server", "gomine.tps", []string{}, func(sender commands.Sender) {
+		var windows = []time.Duration{time.Second * 5, time.Minute, time.Minute * 15}
+		var message = text.Yellow + "TPS from last 5s, 1m, 15m: "
+		for i, window := range windows {
+			if i != 0 {
+				message += text.Yellow + ", "
+			}
+			message += formatTps(server.TickLoop.GetTPS(window))
+		}
+		var stats = server.TickLoop.GetStats(time.Second * 5)
+		message += "\n" + text.Yellow + "MSPT from last 5s (avg/min/max): " + text.White + fmt.Sprintf("%.2f/%.2f/%.2f", stats.AverageMSPT, stats.MinimumMSPT, stats.MaximumMSPT)
+		sender.SendMessage(message)
+	})
+	tps.ExemptFromPermissionCheck(true)
+	return tps
+}
+
+// formatTps formats the amount of ticks per second, colored by how close it is to the maximum.
+func formatTps(tps float64) string {
+	var color = text.BrightGreen
+	if tps < 15 {
+		color = text.Red
+	} else if tps < 18 {
+		color = text.Yellow
+	}
+	return color + fmt.Sprintf("%.1f", tps)
+}
+
+func NewTimings(server *Server) *commands.Command {
+	var cmd = commands.NewCommand("timings", "Measures where the time of server ticks is spent", "gomine.timings", []string{}, func(sender commands.Sender, action string) {
+		switch action {
+		case "on":
+			server.Timings.Enable()
+			sender.SendMessage(text.Yellow + "Timings are now on. Previous timings were discarded.")
+		case "off":
+			server.Timings.Disable()
+			sender.SendMessage(text.Yellow + "Timings are now off. Run /timings report to see the timings measured.")
+		case "report":
+			var report = server.Timings.GetReport()
+			if len(report.Records) == 0 {
+				sender.SendMessage(text.Red + "No timings were measured. Run /timings on to start measuring.")
+				return
+			}
+			if path, err := report.Save(server.ServerPath + "timings/"); err != nil {
+				sender.SendMessage(text.Red+"Could not save the timings report:", err.Error())
+			} else {
+				sender.SendMessage(text.Yellow + "Saved the timings report to " + path + ".")
+			}
+			sender.SendMessage(getTimingsSummary(report))
+		}
+	})
+	cmd.AppendArgument(arguments.NewStringEnum("action", false, []string{"on", "off", "report"}))
+	return cmd
+}
+
+// maximumTimingsSummaryRecords is the maximum amount of records shown in the summary of a timings report.
+const maximumTimingsSummaryRecords = 5
+
+// getTimingsSummary returns a summary of the records in the report that took the most time.
+func getTimingsSummary(report timings.Report) string {
+	var elapsed = report.End.Sub(report.Start)
+	var summary = text.BrightGreen + "-----" + text.White + " Timings (" + elapsed.Round(time.Second).String() + ") " + text.BrightGreen + "-----"
+	for _, record := range report.GetTop(maximumTimingsSummaryRecords) {
+		summary += "\n" + text.Yellow + string(record.Category) + ": " + record.Name + text.White + fmt.Sprintf(" avg %v, max %v", record.GetAverage(), record.Maximum)
+		if elapsed > 0 {
+			summary += fmt.Sprintf(", %.2f%%", float64(record.Total)/float64(elapsed)*100)
+		}
+	}
+	return summary
+}
+
 func NewStop(server *Server) *commands.Command {
 	return commands.NewCommand("stop", "Stops the server", "gomine.stop", []string{"shutdown"}, func() {
+		server.Stop()
+	})
+}
+
+func NewServerCommand(server *Server) *commands.Command {
+	var cmd = commands.NewCommand("server", "Transfers you to another server in the network", "gomine.server", []string{}, func(sender commands.Sender, name string) {
+		var session, ok = sender.(*net.MinecraftSession)
+		if !ok {
+			sender.SendMessage(text.Red + "Please run this command as a player.")
+			return
+		}
+		if name == server.Hub.GetName() {
+			sender.SendMessage(text.Red + "You are already connected to " + name + ".")
+			return
+		}
+		if err := server.TransferPlayer(session, name); err != nil {
+			sender.SendMessage(text.Red+"Could not transfer you to "+name+":", err.Error()+".")
+			sender.SendMessage(listServers(server))
+		}
+	})
+	cmd.AppendArgument(arguments.NewString("server", false))
+	cmd.ExemptFromPermissionCheck(true)
+	return cmd
+}
+
+func NewHub(server *Server) *commands.Command {
+	var cmd = commands.NewCommand("hub", "Transfers you to the hub server", "gomine.hub", []string{"lobby"}, func(sender commands.Sender) {
+		var session, ok = sender.(*net.MinecraftSession)
+		if !ok {
+			sender.SendMessage(text.Red + "Please run this command as a player.")
+			return
+		}
+		var name = server.Config.Hub.HubServer
+		if name == server.Hub.GetName() {
+			sender.SendMessage(text.Red + "You are already connected to the hub.")
+			return
+		}
+		if err := server.TransferPlayer(session, name); err != nil {
+			sender.SendMessage(text.Red+"Could not transfer you to the hub:", err.Error()+".")
+		}
+	})
+	cmd.ExemptFromPermissionCheck(true)
+	return cmd
+}
+
+// listServers returns a list of all servers in the network with their online player counts.
+func listServers(server *Server) string {
+	var list = text.BrightGreen + "-----" + text.White + " Servers (" + strconv.Itoa(server.Hub.GetTotalOnlinePlayers()) + " Players) " + text.BrightGreen + "-----"
+	for _, s := range server.Hub.GetServers() {
+		if status, ok := server.Hub.GetStatus(s.Name); ok {
+			list += "\n" + text.BrightGreen + s.Name + ": " + text.Yellow + strconv.Itoa(status.OnlinePlayers) + "/" + strconv.Itoa(status.MaximumPlayers)
+		} else {
+			list += "\n" + text.BrightGreen + s.Name + ": " + text.Red + "offline"
+		}
+	}
+	return list
+}
+
+func NewBan(server *Server) *commands.Command {
+	var cmd = commands.NewCommand("ban", "Bans a player from the server", "gomine.ban", []string{}, func(sender commands.Sender, name string, reason string) {
+		var ban = newBan(sender, moderation.BanName, name, reason)
+		if err := server.Moderation.Ban(ban); err != nil {
+			sender.SendMessage(text.Red+"Could not ban "+name+":", err.Error())
+			return
+		}
+		if session, ok := server.SessionManager.GetSession(name); ok {
+			if session.GetXUID() != "" {
+				var xuidBan = ban
+				xuidBan.Type, xuidBan.Target, xuidBan.Name = moderation.BanXUID, session.GetXUID(), session.GetName()
+				if err := server.Moderation.Ban(xuidBan); err != nil {
+					server.Logger.LogError(err)
+				}
+			}
+			session.Kick(ban.GetMessage(), false, true)
+		}
+		sender.SendMessage(text.Yellow + "Banned " + name + describeBan(ban) + ".")
+	})
+	cmd.AppendArgument(arguments.NewString("player", false))
+	cmd.AppendArgument(newReasonArgument())
+	return cmd
+}
+
+func NewBanIp(server *Server) *commands.Command {
+	var cmd = commands.NewCommand("ban-ip", "Bans an IP address from the server", "gomine.ban-ip", []string{}, func(sender commands.Sender, target string, reason string) {
+		var ip = net2.ParseIP(target)
+		var name string
+		if session, ok := server.SessionManager.GetSession(target); ok {
+			ip, name = session.GetConnection().GetAddress().IP, session.GetName()
+		}
+		if ip == nil {
+			sender.SendMessage(text.Red + target + " is not an online player or a valid IP address.")
+			return
+		}
+
+		var ban = newBan(sender, moderation.BanIP, ip.String(), reason)
+		ban.Name = name
+		if err := server.Moderation.Ban(ban); err != nil {
+			sender.SendMessage(text.Red+"Could not ban "+ip.String()+":", err.Error())
+			return
+		}
 		for _, session := range server.SessionManager.GetSessions() {
-			session.Kick("Server Stopped", false, true)
+			if session.GetConnection().GetAddress().IP.Equal(ip) {
+				session.Kick(ban.GetMessage(), false, true)
+			}
+		}
+		sender.SendMessage(text.Yellow + "Banned IP " + ip.String() + describeBan(ban) + ".")
+	})
+	cmd.AppendArgument(arguments.NewString("player|ip", false))
+	cmd.AppendArgument(newReasonArgument())
+	return cmd
+}
+
+func NewPardon(server *Server) *commands.Command {
+	var cmd = commands.NewCommand("pardon", "Removes the ban of a player or IP address", "gomine.pardon", []string{"unban"}, func(sender commands.Sender, target string) {
+		var pardoned bool
+		var err error
+		if ip := net2.ParseIP(target); ip != nil {
+			pardoned, err = server.Moderation.Pardon(moderation.BanIP, ip.String())
+		} else {
+			pardoned, err = server.Moderation.PardonPlayer(target)
+		}
+		if err != nil {
+			sender.SendMessage(text.Red+"Could not pardon "+target+":", err.Error())
+			return
+		}
+		if !pardoned {
+			sender.SendMessage(text.Red + target + " is not banned.")
+			return
+		}
+		sender.SendMessage(text.Yellow + "Pardoned " + target + ".")
+	})
+	cmd.AppendArgument(arguments.NewString("player|ip", false))
+	return cmd
+}
+
+func NewWhitelist(server *Server) *commands.Command {
+	var cmd = commands.NewCommand("whitelist", "Manages the whitelist of the server", "gomine.whitelist", []string{}, nil)
+	var report = func(sender commands.Sender, err error) {
+		if err != nil {
+			sender.SendMessage(text.Red+"Could not update the whitelist:", err.Error())
+		}
+	}
+	for _, state := range []string{"on", "off"} {
+		var state = state
+		cmd.AddSubcommand(commands.NewCommand(state, "Turns the whitelist "+state, "", []string{}, func(sender commands.Sender) {
+			var err = server.Moderation.SetWhitelistEnabled(state == "on")
+			if err == nil {
+				sender.SendMessage(text.Yellow + "The whitelist is now " + state + ".")
+			}
+			report(sender, err)
+		}))
+	}
+	cmd.AddSubcommand(commands.NewCommand("list", "Lists all whitelisted players", "", []string{}, func(sender commands.Sender) {
+		var names = server.Moderation.GetWhitelist()
+		sender.SendMessage(text.BrightGreen + "-----" + text.White + " Whitelist (" + strconv.Itoa(len(names)) + " Players) " + text.BrightGreen + "-----\n" + text.Yellow + strings.Join(names, ", "))
+	}))
+
+	var add = commands.NewCommand("add", "Adds a player to the whitelist", "", []string{}, func(sender commands.Sender, name string) {
+		var err = server.Moderation.AddToWhitelist(name)
+		if err == nil {
+			sender.SendMessage(text.Yellow + "Added " + name + " to the whitelist.")
+		}
+		report(sender, err)
+	})
+	add.AppendArgument(arguments.NewString("player", false))
+	cmd.AddSubcommand(add)
+
+	var remove = commands.NewCommand("remove", "Removes a player from the whitelist", "", []string{}, func(sender commands.Sender, name string) {
+		var removed, err = server.Moderation.RemoveFromWhitelist(name)
+		if err == nil {
+			if removed {
+				sender.SendMessage(text.Yellow + "Removed " + name + " from the whitelist.")
+			} else {
+				sender.SendMessage(text.Red + name + " is not whitelisted.")
+			}
+		}
+		report(sender, err)
+	})
+	remove.AppendArgument(arguments.NewString("player", false))
+	cmd.AddSubcommand(remove)
+	return cmd
+}
+
+func NewMaintenance(server *Server) *commands.Command {
+	var cmd = commands.NewCommand("maintenance", "Enables or disables maintenance mode", "gomine.maintenance", []string{}, func(sender commands.Sender, state string) {
+		server.SetMaintenanceMode(state == "on")
+		sender.SendMessage(text.Yellow + "Maintenance mode is now " + state + ".")
+	})
+	cmd.AppendArgument(arguments.NewStringEnum("state", false, []string{"on", "off"}))
+	return cmd
+}
+
+func NewLogin(server *Server) *commands.Command {
+	var cmd = commands.NewCommand("login", "Logs you in with your password", "gomine.login", []string{}, func(sender commands.Sender, password string) {
+		var session, ok = sender.(*net.MinecraftSession)
+		if !ok {
+			sender.SendMessage(text.Red + "Please run this command as a player.")
+			return
+		}
+		if !server.IsFrozen(session) {
+			sender.SendMessage(text.Red + "You are already logged in.")
+			return
+		}
+		var provider, _ = server.getPasswordProvider()
+		switch err := provider.Login(session.GetName(), password); err {
+		case nil:
+			server.unfreeze(session.GetConnection())
+			server.Logger.Info(session.GetName(), "has logged in.")
+			sender.SendMessage(text.BrightGreen + "You are now logged in.")
+		case auth.NotRegistered:
+			sender.SendMessage(text.Red + "You are not registered yet. Please register using /register <password> <password>.")
+		case auth.WrongPassword:
+			sender.SendMessage(text.Red + "Wrong password.")
+			server.failPasswordLogin(session)
+		default:
+			sender.SendMessage(text.Red+"Could not log you in:", err.Error())
 		}
+	})
+	cmd.AppendArgument(arguments.NewString("password", false))
+	cmd.ExemptFromPermissionCheck(true)
+	return cmd
+}
 
-		server.Shutdown()
+func NewRegister(server *Server) *commands.Command {
+	var cmd = commands.NewCommand("register", "Registers a password for your account", "gomine.register", []string{}, func(sender commands.Sender, password string, confirmation string) {
+		var session, ok = sender.(*net.MinecraftSession)
+		if !ok {
+			sender.SendMessage(text.Red + "Please run this command as a player.")
+			return
+		}
+		if !server.IsFrozen(session) {
+			sender.SendMessage(text.Red + "You are already logged in.")
+			return
+		}
+		if password != confirmation {
+			sender.SendMessage(text.Red + "The passwords do not match.")
+			return
+		}
+		var provider, _ = server.getPasswordProvider()
+		switch err := provider.Register(session.GetName(), password); err {
+		case nil:
+			server.unfreeze(session.GetConnection())
+			server.Logger.Info(session.GetName(), "has registered.")
+			sender.SendMessage(text.BrightGreen + "You are now registered and logged in.")
+		case auth.AlreadyRegistered:
+			sender.SendMessage(text.Red + "You are already registered. Please log in using /login <password>.")
+		default:
+			sender.SendMessage(text.Red+"Could not register you:", err.Error()+".")
+		}
 	})
+	cmd.AppendArgument(arguments.NewString("password", false))
+	cmd.AppendArgument(arguments.NewString("password", false))
+	cmd.ExemptFromPermissionCheck(true)
+	return cmd
+}
+
+// newReasonArgument returns the optional reason argument of ban commands.
+// The first word of the reason is used as the duration of the ban if it is a valid duration, such as 7d or 1h30m.
+func newReasonArgument() *arguments.Argument {
+	var reason = arguments.NewString("duration|reason", true)
+	reason.SetInputAmount(maximumReasonWords)
+	return reason
+}
+
+// newBan returns a new ban issued by the sender, with the duration and reason parsed from the reason argument.
+func newBan(sender commands.Sender, banType moderation.BanType, target string, reason string) moderation.Ban {
+	var ban = moderation.Ban{Type: banType, Target: target, Name: target, Reason: reason, Source: "CONSOLE"}
+	if banType != moderation.BanName {
+		ban.Name = ""
+	}
+	if session, ok := sender.(*net.MinecraftSession); ok {
+		ban.Source = session.GetName()
+	}
+	var words = strings.SplitN(reason, " ", 2)
+	if duration, err := arguments.ParseDuration(words[0]); err == nil {
+		ban.Expires = time.Now().Add(duration)
+		ban.Reason = ""
+		if len(words) > 1 {
+			ban.Reason = words[1]
+		}
+	}
+	return ban
+}
+
+// describeBan returns a description of the expiry and reason of a ban.
+func describeBan(ban moderation.Ban) string {
+	var description = ""
+	if !ban.IsPermanent() {
+		description += " until " + ban.Expires.Format("2006-01-02 15:04 MST")
+	}
+	if ban.Reason != "" {
+		description += " for: " + ban.Reason
+	}
+	return description
 }
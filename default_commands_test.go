@@ -0,0 +1,23 @@
+package gomine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/irmine/gomine/moderation"
+)
+
+func TestBanDuration(t *testing.T) {
+	var ban = newBan(nil, moderation.BanName, "Steve", "1h30m griefing")
+	if ban.IsPermanent() || ban.Reason != "griefing" {
+		t.Fatalf("expected a temporary ban for griefing, got %+v", ban)
+	}
+	if until := time.Until(ban.Expires); until < time.Minute*89 || until > time.Minute*90 {
+		t.Errorf("expected the ban to expire in 1h30m, expires in %v", until)
+	}
+
+	ban = newBan(nil, moderation.BanName, "Steve", "griefing for 1h")
+	if !ban.IsPermanent() || ban.Reason != "griefing for 1h" {
+		t.Errorf("expected a permanent ban for griefing for 1h, got %+v", ban)
+	}
+}
@@ -0,0 +1,62 @@
+package gomine
+
+import (
+	"github.com/irmine/gomine/net"
+)
+
+const (
+	// DuplicateLoginKickOld disconnects the session a player is already online with,
+	// and continues the login of the new session.
+	DuplicateLoginKickOld = "kick-old"
+	// DuplicateLoginRejectNew disconnects the new session of a player that is already online.
+	DuplicateLoginRejectNew = "reject-new"
+)
+
+// findDuplicateSession returns the online or queued session of the player logging in with the session.
+// Sessions are matched on XUID if the new session is authenticated with XBOX Live, and on name otherwise.
+// Authenticated sessions also match on name, because names of online players are unique.
+func (server *Server) findDuplicateSession(session *net.MinecraftSession, authenticated bool) (*net.MinecraftSession, bool) {
+	if authenticated && session.GetXUID() != "" {
+		if old, ok := server.SessionManager.GetSessionByXUID(session.GetXUID()); ok {
+			return old, true
+		}
+	}
+	if old, ok := server.SessionManager.GetSession(session.GetName()); ok {
+		return old, true
+	}
+	if server.JoinQueue != nil {
+		for _, old := range server.JoinQueue.GetSessions() {
+			if old.GetName() == session.GetName() || (authenticated && session.GetXUID() != "" && old.GetXUID() == session.GetXUID()) {
+				return old, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// handleDuplicateLogin applies the duplicate login policy if the player logging in with the session is already online or queued.
+// The old session gets disconnected and cleaned up when kicking the old session,
+// and the new session gets disconnected when rejecting new sessions.
+// New sessions are always rejected if players log in with a password and the old session already logged in.
+// Returns true if the login of the new session may continue.
+func (server *Server) handleDuplicateLogin(session *net.MinecraftSession, authenticated bool) bool {
+	var old, ok = server.findDuplicateSession(session, authenticated)
+	if !ok {
+		return true
+	}
+	// Players logging in with a password could otherwise be kicked by anybody joining with their name.
+	var _, passwords = server.getPasswordProvider()
+	if server.Config.DuplicateLoginPolicy == DuplicateLoginRejectNew || (passwords && !server.IsFrozen(old)) {
+		server.Logger.Info(session.GetName(), "tried to join while already being online.")
+		session.Kick("You are already logged in.", false, false)
+		return false
+	}
+
+	server.Logger.Info(session.GetName(), "logged in from another location, replacing the old session.")
+	if server.JoinQueue != nil && server.JoinQueue.Remove(old.GetConnection()) {
+		server.removeQueuedSession(old)
+	}
+	old.Kick("Logged in from another location.", false, false)
+	server.removeSession(old)
+	return true
+}
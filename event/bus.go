@@ -0,0 +1,204 @@
+// Package event implements a typed event bus.
+// Events are pointers to structs, and listeners are functions taking a single event of the type they listen for.
+// Listeners run in order of their priority, and may cancel or modify events.
+package event
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/irmine/gomine/text"
+)
+
+// Priority is the priority of a listener.
+// Listeners with a lower priority run first, so that listeners with a higher priority have the final say.
+type Priority int
+
+const (
+	Lowest Priority = iota
+	Low
+	Normal
+	High
+	Highest
+	// Monitor is the priority of listeners only observing the outcome of an event.
+	// Listeners of this priority should not modify events.
+	Monitor
+)
+
+// InvalidListener gets returned when registering a listener that is not
+// a function taking a single pointer to an event struct.
+var InvalidListener = errors.New("listener must be a function taking a single pointer to an event struct")
+
+// InvalidPriority gets returned when registering a listener with an unknown priority.
+var InvalidPriority = errors.New("unknown listener priority")
+
+// Cancellable is implemented by events that can be cancelled.
+// Embed Cancel in an event struct to make it cancellable.
+type Cancellable interface {
+	IsCancelled() bool
+	SetCancelled(cancelled bool)
+}
+
+// Cancel implements Cancellable, and is embedded in cancellable events.
+type Cancel struct {
+	cancelled bool
+}
+
+// IsCancelled checks if the event was cancelled.
+func (cancel *Cancel) IsCancelled() bool {
+	return cancel.cancelled
+}
+
+// SetCancelled cancels or uncancels the event.
+func (cancel *Cancel) SetCancelled(cancelled bool) {
+	cancel.cancelled = cancelled
+}
+
+// Listener is a listener registered on a bus.
+type Listener struct {
+	owner            string
+	priority         Priority
+	eventType        reflect.Type
+	function         reflect.Value
+	receiveCancelled bool
+}
+
+// GetOwner returns the name of the plugin that registered the listener.
+func (listener *Listener) GetOwner() string {
+	return listener.owner
+}
+
+// GetPriority returns the priority of the listener.
+func (listener *Listener) GetPriority() Priority {
+	return listener.priority
+}
+
+// SetReceiveCancelled sets if the listener receives events cancelled by listeners before it.
+// Listeners do not receive cancelled events by default.
+func (listener *Listener) SetReceiveCancelled(value bool) {
+	listener.receiveCancelled = value
+}
+
+// Bus dispatches events to the listeners registered for their type.
+// Listeners may be registered and events may be called from any goroutine.
+type Bus struct {
+	logger *text.Logger
+
+	mutex     sync.RWMutex
+	listeners map[reflect.Type][]*Listener
+}
+
+// NewBus returns a new bus without listeners.
+func NewBus() *Bus {
+	return &Bus{logger: text.DefaultLogger, listeners: make(map[reflect.Type][]*Listener)}
+}
+
+// SetLogger sets the logger panics of listeners are logged to.
+// The default logger is used if not set.
+func (bus *Bus) SetLogger(logger *text.Logger) {
+	bus.logger = logger
+}
+
+// Listen registers a listener owned by the given plugin, with the given priority.
+// The listener must be a function taking a single pointer to the event struct it listens for, for example:
+// func(event *gomine.PlayerChatEvent) { ... }
+// InvalidListener is returned if the listener is not such a function.
+func (bus *Bus) Listen(owner string, priority Priority, listener interface{}) (*Listener, error) {
+	if priority < Lowest || priority > Monitor {
+		return nil, InvalidPriority
+	}
+	if listener == nil {
+		return nil, InvalidListener
+	}
+	var function = reflect.ValueOf(listener)
+	var functionType = function.Type()
+	if functionType.Kind() != reflect.Func || functionType.NumIn() != 1 || functionType.NumOut() != 0 {
+		return nil, InvalidListener
+	}
+	var eventType = functionType.In(0)
+	if eventType.Kind() != reflect.Ptr || eventType.Elem().Kind() != reflect.Struct {
+		return nil, InvalidListener
+	}
+
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+	var l = &Listener{owner: owner, priority: priority, eventType: eventType, function: function}
+	var listeners = append(append([]*Listener{}, bus.listeners[eventType]...), l)
+	sort.SliceStable(listeners, func(i, j int) bool {
+		return listeners[i].priority < listeners[j].priority
+	})
+	bus.listeners[eventType] = listeners
+	return l, nil
+}
+
+// Unregister unregisters the listener from the bus.
+func (bus *Bus) Unregister(listener *Listener) {
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+	bus.listeners[listener.eventType] = remove(bus.listeners[listener.eventType], func(l *Listener) bool {
+		return l == listener
+	})
+}
+
+// UnregisterAll unregisters all listeners owned by the given plugin.
+func (bus *Bus) UnregisterAll(owner string) {
+	bus.mutex.Lock()
+	defer bus.mutex.Unlock()
+	for eventType, listeners := range bus.listeners {
+		bus.listeners[eventType] = remove(listeners, func(l *Listener) bool {
+			return l.owner == owner
+		})
+	}
+}
+
+// HasListeners checks if any listeners are registered for the type of the event.
+// Events that are expensive to create may be skipped if nobody listens for them.
+func (bus *Bus) HasListeners(event interface{}) bool {
+	bus.mutex.RLock()
+	defer bus.mutex.RUnlock()
+	return len(bus.listeners[reflect.TypeOf(event)]) != 0
+}
+
+// Call calls all listeners registered for the type of the event, in order of their priority.
+// Listeners panicking are logged, after which the next listener is called.
+// Returns false if the event is cancellable and was cancelled.
+func (bus *Bus) Call(event interface{}) bool {
+	var eventType = reflect.TypeOf(event)
+	bus.mutex.RLock()
+	var listeners = bus.listeners[eventType]
+	bus.mutex.RUnlock()
+
+	var cancellable, isCancellable = event.(Cancellable)
+	var value = reflect.ValueOf(event)
+	for _, listener := range listeners {
+		if isCancellable && cancellable.IsCancelled() && !listener.receiveCancelled {
+			continue
+		}
+		bus.call(listener, eventType, value)
+	}
+	return !isCancellable || !cancellable.IsCancelled()
+}
+
+// call calls a single listener, recovering from any panic.
+func (bus *Bus) call(listener *Listener, eventType reflect.Type, event reflect.Value) {
+	defer func() {
+		if err := recover(); err != nil {
+			bus.logger.Error("Listener of", listener.owner, "for", eventType.Elem().Name(), "panicked:", err)
+		}
+	}()
+	listener.function.Call([]reflect.Value{event})
+}
+
+// remove returns a new slice of the listeners without the listeners matching the function.
+// A new slice is returned, so that events being called concurrently keep their own listeners.
+func remove(listeners []*Listener, matches func(l *Listener) bool) []*Listener {
+	var kept = make([]*Listener, 0, len(listeners))
+	for _, listener := range listeners {
+		if !matches(listener) {
+			kept = append(kept, listener)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,122 @@
+package event
+
+import (
+	"reflect"
+	"testing"
+)
+
+type chatEvent struct {
+	Cancel
+	Message string
+}
+
+type joinEvent struct {
+	Name string
+}
+
+func TestCall(t *testing.T) {
+	var bus = NewBus()
+	var calls []string
+	mustListen(t, bus, "A", Monitor, func(event *chatEvent) {
+		calls = append(calls, "monitor:"+event.Message)
+	})
+	mustListen(t, bus, "A", Lowest, func(event *chatEvent) {
+		calls = append(calls, "lowest")
+		event.Message = "modified"
+	})
+	mustListen(t, bus, "B", Normal, func(event *chatEvent) {
+		calls = append(calls, "normal:"+event.Message)
+	})
+	mustListen(t, bus, "B", Normal, func(event *joinEvent) {
+		calls = append(calls, "join")
+	})
+
+	if !bus.Call(&chatEvent{Message: "hello"}) {
+		t.Error("expected the event not to be cancelled")
+	}
+	var expected = []string{"lowest", "normal:modified", "monitor:modified"}
+	if !reflect.DeepEqual(calls, expected) {
+		t.Errorf("expected listeners to be called as %v, got %v", expected, calls)
+	}
+	if !bus.Call(&joinEvent{}) {
+		t.Error("expected events that can not be cancelled to never be cancelled")
+	}
+}
+
+func TestCancel(t *testing.T) {
+	var bus = NewBus()
+	var received = 0
+	mustListen(t, bus, "A", Low, func(event *chatEvent) {
+		event.SetCancelled(true)
+	})
+	mustListen(t, bus, "A", Normal, func(event *chatEvent) {
+		received++
+	})
+	var listener = mustListen(t, bus, "A", High, func(event *chatEvent) {
+		received += 10
+	})
+	listener.SetReceiveCancelled(true)
+
+	if bus.Call(&chatEvent{}) {
+		t.Error("expected the event to be cancelled")
+	}
+	if received != 10 {
+		t.Errorf("expected only the listener receiving cancelled events to be called, got %v", received)
+	}
+}
+
+func TestUnregister(t *testing.T) {
+	var bus = NewBus()
+	var calls = 0
+	var listener = mustListen(t, bus, "A", Normal, func(event *joinEvent) { calls++ })
+	mustListen(t, bus, "B", Normal, func(event *joinEvent) { calls += 10 })
+	mustListen(t, bus, "B", Normal, func(event *chatEvent) { calls += 100 })
+
+	bus.Unregister(listener)
+	bus.Call(&joinEvent{})
+	if calls != 10 {
+		t.Errorf("expected the unregistered listener not to be called, got %v", calls)
+	}
+	bus.UnregisterAll("B")
+	if bus.HasListeners(&joinEvent{}) || bus.HasListeners(&chatEvent{}) {
+		t.Error("expected all listeners of the plugin to be unregistered")
+	}
+}
+
+func TestPanickingListener(t *testing.T) {
+	var bus = NewBus()
+	var called = false
+	mustListen(t, bus, "A", Low, func(event *joinEvent) { panic("test") })
+	mustListen(t, bus, "A", Normal, func(event *joinEvent) { called = true })
+	bus.Call(&joinEvent{})
+	if !called {
+		t.Error("expected a panicking listener not to prevent other listeners from being called")
+	}
+}
+
+func TestInvalidListeners(t *testing.T) {
+	var bus = NewBus()
+	for _, listener := range []interface{}{
+		"listener",
+		func() {},
+		func(event joinEvent) {},
+		func(event *joinEvent, other int) {},
+		func(event *joinEvent) bool { return true },
+		func(event *int) {},
+	} {
+		if _, err := bus.Listen("A", Normal, listener); err != InvalidListener {
+			t.Errorf("expected %T to be an invalid listener, got %v", listener, err)
+		}
+	}
+	if _, err := bus.Listen("A", Monitor+1, func(event *joinEvent) {}); err != InvalidPriority {
+		t.Errorf("expected an invalid priority, got %v", err)
+	}
+}
+
+func mustListen(t *testing.T, bus *Bus, owner string, priority Priority, listener interface{}) *Listener {
+	var l, err = bus.Listen(owner, priority, listener)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return l
+}
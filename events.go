@@ -0,0 +1,124 @@
+package gomine
+
+import (
+	net2 "net"
+
+	"github.com/golang/geo/r3"
+	"github.com/irmine/gomine/commands"
+	"github.com/irmine/gomine/event"
+	"github.com/irmine/gomine/items"
+	"github.com/irmine/gomine/net"
+	"github.com/irmine/worlds/blocks"
+	"github.com/irmine/worlds/entities/data"
+)
+
+// PlayerPreLoginEvent is called once a player logged in and was authenticated,
+// before the player joins the server. Cancelling it disconnects the player with the kick message.
+type PlayerPreLoginEvent struct {
+	event.Cancel
+	Session       *net.MinecraftSession
+	Name          string
+	XUID          string
+	Address       net2.IP
+	Authenticated bool
+	KickMessage   string
+}
+
+// PlayerJoinEvent is called once a player spawned.
+// The join message is broadcast to all players, unless it is empty.
+type PlayerJoinEvent struct {
+	Session     *net.MinecraftSession
+	JoinMessage string
+}
+
+// PlayerQuitEvent is called once a player that spawned left the server.
+// The quit message is broadcast to all players, unless it is empty.
+type PlayerQuitEvent struct {
+	Session     *net.MinecraftSession
+	QuitMessage string
+}
+
+// PlayerChatEvent is called when a player sends a chat message.
+// The message is sent to all recipients, or to nobody if the event is cancelled.
+type PlayerChatEvent struct {
+	event.Cancel
+	Session    *net.MinecraftSession
+	Message    string
+	Recipients []*net.MinecraftSession
+}
+
+// PlayerMoveEvent is called when a player moves.
+// Cancelling it moves the player back to where they came from.
+// The player is moved to another position if the destination was changed.
+type PlayerMoveEvent struct {
+	event.Cancel
+	Session  *net.MinecraftSession
+	From     r3.Vector
+	To       r3.Vector
+	Rotation data.Rotation
+	OnGround bool
+}
+
+// PlayerCommandPreprocessEvent is called when a player runs a command, before the command is looked up.
+// The command line may be changed to run another command, and cancelling the event prevents running any command.
+type PlayerCommandPreprocessEvent struct {
+	event.Cancel
+	Session     *net.MinecraftSession
+	CommandLine string
+}
+
+// ServerCommandEvent is called when a command is run from the console, before the command is looked up.
+// The command line may be changed to run another command, and cancelling the event prevents running any command.
+type ServerCommandEvent struct {
+	event.Cancel
+	Sender      commands.Sender
+	CommandLine string
+}
+
+// BlockBreakEvent is called when a player breaks a block.
+// The block is not broken if the event is cancelled.
+type BlockBreakEvent struct {
+	event.Cancel
+	Session  *net.MinecraftSession
+	Position blocks.Position
+	Item     *items.Stack
+}
+
+// BlockPlaceEvent is called when a player places a block against another block.
+// Position is the position the block is placed at, and Item is the item the block is placed with.
+type BlockPlaceEvent struct {
+	event.Cancel
+	Session  *net.MinecraftSession
+	Position blocks.Position
+	Item     *items.Stack
+}
+
+// PlayerInteractEvent is called when a player clicks a block.
+// Cancelling it prevents the interaction, including placing a block.
+type PlayerInteractEvent struct {
+	event.Cancel
+	Session       *net.MinecraftSession
+	Position      blocks.Position
+	Face          int32
+	ClickPosition r3.Vector
+	Item          *items.Stack
+}
+
+// getAdjacentPosition returns the position next to the position, on the given face of the block.
+func getAdjacentPosition(position blocks.Position, face int32) blocks.Position {
+	switch face {
+	case 0:
+		position.Y--
+	case 1:
+		position.Y++
+	case 2:
+		position.Z--
+	case 3:
+		position.Z++
+	case 4:
+		position.X--
+	case 5:
+		position.X++
+	}
+	return position
+}
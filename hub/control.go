@@ -0,0 +1,81 @@
+package hub
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+// NoControlAddress gets returned when starting a hub of which the server has no control address.
+var NoControlAddress = errors.New("server has no control address")
+
+// controlChannel shares the status of a server with other servers over TCP.
+// Every connection accepted gets the status of the server written to it as a single line of JSON,
+// after which the connection is closed.
+type controlChannel struct {
+	listener   net.Listener
+	statusFunc func() Status
+
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// listenControl starts listening for status polls on the given address.
+func listenControl(address string, statusFunc func() Status) (*controlChannel, error) {
+	if address == "" {
+		return nil, NoControlAddress
+	}
+	var listener, err = net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	var control = &controlChannel{listener: listener, statusFunc: statusFunc, closed: make(chan struct{})}
+	go control.accept()
+	return control, nil
+}
+
+// accept accepts connections until the control channel gets closed.
+func (control *controlChannel) accept() {
+	for {
+		var conn, err = control.listener.Accept()
+		if err != nil {
+			select {
+			case <-control.closed:
+				return
+			default:
+				continue
+			}
+		}
+		go control.writeStatus(conn)
+	}
+}
+
+// writeStatus writes the status of the server to the connection and closes it.
+func (control *controlChannel) writeStatus(conn net.Conn) {
+	defer conn.Close()
+	conn.SetWriteDeadline(time.Now().Add(DialTimeout))
+	json.NewEncoder(conn).Encode(control.statusFunc())
+}
+
+// close stops listening for status polls.
+func (control *controlChannel) close() {
+	control.closeOnce.Do(func() {
+		close(control.closed)
+		control.listener.Close()
+	})
+}
+
+// pollStatus polls the status of the server listening on the given control address.
+func pollStatus(address string) (Status, error) {
+	var status Status
+	var conn, err = net.DialTimeout("tcp", address, DialTimeout)
+	if err != nil {
+		return status, err
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(DialTimeout))
+	err = json.NewDecoder(conn).Decode(&status)
+	return status, err
+}
@@ -0,0 +1,208 @@
+// Package hub implements a lightweight network of GoMine servers.
+// Servers in the network share their online player counts over a local control channel,
+// and players are moved between servers using the Transfer packet.
+package hub
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultPollInterval is the default interval in which the statuses of other servers get polled.
+const DefaultPollInterval = time.Second * 5
+
+// DialTimeout is the timeout used when polling the status of another server.
+const DialTimeout = time.Second
+
+// UnknownServer gets returned when a server could not be found in the network.
+var UnknownServer = errors.New("unknown server")
+
+// ServerOffline gets returned when a server in the network is not online.
+var ServerOffline = errors.New("server is offline")
+
+// Server is a server in the network.
+// Address and Port are the address players get transferred to,
+// ControlAddress is the loopback TCP address the server shares its status on.
+type Server struct {
+	Name           string
+	Address        string
+	Port           uint16
+	ControlAddress string
+}
+
+// Status is the status of a server, shared over the control channel.
+type Status struct {
+	Name           string `json:"name"`
+	OnlinePlayers  int    `json:"online"`
+	MaximumPlayers int    `json:"maximum"`
+}
+
+// Hub is a single server in the network.
+// It shares its own status with the other servers, and keeps track of their statuses.
+type Hub struct {
+	name       string
+	servers    map[string]Server
+	statusFunc func() Status
+
+	mutex    sync.RWMutex
+	statuses map[string]Status
+
+	control *controlChannel
+}
+
+// New returns a new hub for the server with the given name, in a network of the given servers.
+// The status function gets called every time another server polls the status of this server.
+// The list of servers may include this server itself.
+func New(name string, servers []Server, statusFunc func() Status) *Hub {
+	var hub = &Hub{name: name, servers: make(map[string]Server), statusFunc: statusFunc, statuses: make(map[string]Status)}
+	for _, server := range servers {
+		hub.servers[server.Name] = server
+	}
+	return hub
+}
+
+// GetName returns the name of the server of this hub.
+func (hub *Hub) GetName() string {
+	return hub.name
+}
+
+// GetServer returns a server in the network by its name.
+// A bool is returned indicating if the server was found.
+func (hub *Hub) GetServer(name string) (Server, bool) {
+	var server, ok = hub.servers[name]
+	return server, ok
+}
+
+// GetTarget returns the server with the given name to transfer players to.
+// UnknownServer is returned if the server is not in the network,
+// and ServerOffline is returned if the server is not online.
+func (hub *Hub) GetTarget(name string) (Server, error) {
+	var server, ok = hub.servers[name]
+	if !ok {
+		return server, UnknownServer
+	}
+	if !hub.IsOnline(name) {
+		return server, ServerOffline
+	}
+	return server, nil
+}
+
+// GetServers returns all servers in the network, sorted by name.
+func (hub *Hub) GetServers() []Server {
+	var servers = make([]Server, 0, len(hub.servers))
+	for _, server := range hub.servers {
+		servers = append(servers, server)
+	}
+	sort.Slice(servers, func(i, j int) bool {
+		return servers[i].Name < servers[j].Name
+	})
+	return servers
+}
+
+// IsOnline checks if the server with the given name is online.
+// Other servers are online if they responded to the last poll of their status.
+// The server of this hub is always online.
+func (hub *Hub) IsOnline(name string) bool {
+	if name == hub.name {
+		return true
+	}
+	hub.mutex.RLock()
+	defer hub.mutex.RUnlock()
+	_, ok := hub.statuses[name]
+	return ok
+}
+
+// GetStatus returns the last known status of the server with the given name.
+// A bool is returned indicating if the server is online.
+func (hub *Hub) GetStatus(name string) (Status, bool) {
+	if name == hub.name {
+		return hub.statusFunc(), true
+	}
+	hub.mutex.RLock()
+	defer hub.mutex.RUnlock()
+	var status, ok = hub.statuses[name]
+	return status, ok
+}
+
+// GetTotalOnlinePlayers returns the amount of players online on all online servers in the network.
+func (hub *Hub) GetTotalOnlinePlayers() int {
+	var total = hub.statusFunc().OnlinePlayers
+	hub.mutex.RLock()
+	for _, status := range hub.statuses {
+		total += status.OnlinePlayers
+	}
+	hub.mutex.RUnlock()
+	return total
+}
+
+// setStatus sets the status of another server, or marks it offline if the status is nil.
+func (hub *Hub) setStatus(name string, status *Status) {
+	hub.mutex.Lock()
+	defer hub.mutex.Unlock()
+	if status == nil {
+		delete(hub.statuses, name)
+		return
+	}
+	hub.statuses[name] = *status
+}
+
+// Poll polls the status of every other server in the network once.
+// Servers that do not respond are marked offline.
+func (hub *Hub) Poll() {
+	var wg sync.WaitGroup
+	for _, server := range hub.servers {
+		if server.Name == hub.name || server.ControlAddress == "" {
+			continue
+		}
+		wg.Add(1)
+		go func(server Server) {
+			defer wg.Done()
+			var status, err = pollStatus(server.ControlAddress)
+			if err != nil {
+				hub.setStatus(server.Name, nil)
+				return
+			}
+			hub.setStatus(server.Name, &status)
+		}(server)
+	}
+	wg.Wait()
+}
+
+// Start starts sharing the status of this server on its control address,
+// and polls the statuses of other servers in the given interval.
+// An error is returned if the control address could not be listened on.
+func (hub *Hub) Start(pollInterval time.Duration) error {
+	var address string
+	if server, ok := hub.servers[hub.name]; ok {
+		address = server.ControlAddress
+	}
+	var control, err = listenControl(address, hub.statusFunc)
+	if err != nil {
+		return err
+	}
+	hub.control = control
+
+	hub.Poll()
+	go func() {
+		var ticker = time.NewTicker(pollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				hub.Poll()
+			case <-control.closed:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Close stops sharing the status of this server and stops polling other servers.
+func (hub *Hub) Close() {
+	if hub.control != nil {
+		hub.control.close()
+	}
+}
@@ -0,0 +1,74 @@
+package hub
+
+import (
+	"net"
+	"testing"
+)
+
+// freeAddress returns a loopback address with a port that is currently free.
+func freeAddress(t *testing.T) string {
+	var listener, err = net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	return listener.Addr().String()
+}
+
+func TestHubStatuses(t *testing.T) {
+	var servers = []Server{
+		{Name: "lobby", Address: "127.0.0.1", Port: 19132, ControlAddress: freeAddress(t)},
+		{Name: "survival", Address: "127.0.0.1", Port: 19133, ControlAddress: freeAddress(t)},
+		{Name: "creative", Address: "127.0.0.1", Port: 19134, ControlAddress: freeAddress(t)},
+	}
+	var lobby = New("lobby", servers, func() Status {
+		return Status{Name: "lobby", OnlinePlayers: 3, MaximumPlayers: 20}
+	})
+	var survival = New("survival", servers, func() Status {
+		return Status{Name: "survival", OnlinePlayers: 5, MaximumPlayers: 20}
+	})
+	if err := lobby.Start(DefaultPollInterval); err != nil {
+		t.Fatal(err)
+	}
+	defer lobby.Close()
+	if err := survival.Start(DefaultPollInterval); err != nil {
+		t.Fatal(err)
+	}
+	defer survival.Close()
+
+	lobby.Poll()
+	if status, ok := lobby.GetStatus("survival"); !ok || status.OnlinePlayers != 5 {
+		t.Fatalf("expected survival online with 5 players, got %+v (online: %v)", status, ok)
+	}
+	if lobby.IsOnline("creative") {
+		t.Fatal("creative is online, but was never started")
+	}
+	if total := lobby.GetTotalOnlinePlayers(); total != 8 {
+		t.Fatalf("expected 8 players online in the network, got %v", total)
+	}
+
+	if _, err := lobby.GetTarget("creative"); err != ServerOffline {
+		t.Fatalf("expected ServerOffline for creative, got %v", err)
+	}
+	if _, err := lobby.GetTarget("minigames"); err != UnknownServer {
+		t.Fatalf("expected UnknownServer for minigames, got %v", err)
+	}
+	if server, err := lobby.GetTarget("survival"); err != nil || server.Port != 19133 {
+		t.Fatalf("expected survival at port 19133, got %+v (%v)", server, err)
+	}
+
+	survival.Close()
+	lobby.Poll()
+	if lobby.IsOnline("survival") {
+		t.Fatal("survival is still online after closing")
+	}
+}
+
+func TestStartWithoutControlAddress(t *testing.T) {
+	var hub = New("lobby", nil, func() Status {
+		return Status{}
+	})
+	if err := hub.Start(DefaultPollInterval); err != NoControlAddress {
+		t.Fatalf("expected NoControlAddress, got %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+// Package gominetest provides GoMine servers accepting connections over a loopback transport, for use in tests.
+package gominetest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/irmine/gomine"
+	"github.com/irmine/gomine/net"
+	"github.com/irmine/gomine/resources"
+	"github.com/irmine/gomine/text"
+)
+
+// NewServer returns a new server in a temporary directory, which accepts connections over the returned loopback transport.
+// XBOX Live authentication is disabled, so that clients can log in offline.
+// The configuration may be changed by the configure function before the server is created, if it is not nil.
+// The server does not get started, and the temporary directory gets removed once the test finishes.
+func NewServer(t testing.TB, configure func(config *resources.GoMineConfig)) (*gomine.Server, *net.LoopbackTransport) {
+	var path = t.TempDir() + "/"
+	gomine.SetUpDirectories(path)
+	var config = resources.NewGoMineConfig(path)
+	config.XBOXLiveAuth = false
+	config.ShutdownTimeout = 5
+	if configure != nil {
+		configure(config)
+	}
+
+	var transport = net.NewLoopbackTransport(1)
+	var server = gomine.New(path, gomine.WithConfig(config), gomine.WithTransport(transport), gomine.WithLogger(text.NewLogger(gomine.GoMineName, false)))
+	return server, transport
+}
+
+// StartServer returns a new server like NewServer, and starts running it.
+// The server gets shut down once the test finishes.
+func StartServer(t testing.TB, configure func(config *resources.GoMineConfig)) (*gomine.Server, *net.LoopbackTransport) {
+	var server, transport = NewServer(t, configure)
+	if err := server.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	var ctx, cancel = context.WithCancel(context.Background())
+	var done = make(chan struct{})
+	go func() {
+		server.Run(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return server, transport
+}
@@ -29,8 +29,8 @@ func (IOList *InventoryActionIOList) WriteToBuffer(bs *packets.MinecraftStream)
 }
 
 func (IOList *InventoryActionIOList) ReadFromBuffer(bs *packets.MinecraftStream) *InventoryActionIOList{
-	c := bs.GetUnsignedVarInt()
-	for i := uint32(0); i < c; i ++{
+	c := bs.Count(4)
+	for i := uint32(0); i < c && bs.Error() == nil; i ++{
 		a := NewInventoryActionIO()
 		a.ReadFromBuffer(bs)
 		IOList.PutAction(a)
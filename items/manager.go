@@ -18,13 +18,16 @@ type Manager struct {
 }
 
 // DefaultManager is the default item manager.
-// The default items are registered upon the init function.
-var DefaultManager = NewManager()
-
-// init initializes all default item types,
-// of the default item manager.
-func init() {
-	DefaultManager.RegisterDefaults()
+// The default items are registered upon initialization,
+// so that they are available to the item conversion maps.
+var DefaultManager = newDefaultManager()
+
+// newDefaultManager returns a new item registry,
+// with all default item types registered.
+func newDefaultManager() *Manager {
+	var registry = NewManager()
+	registry.RegisterDefaults()
+	return registry
 }
 
 // NewManager returns a new item registry.
@@ -0,0 +1,352 @@
+// Package moderation implements a persistent store of bans and the whitelist of a server.
+package moderation
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BanType is the type of a ban, specifying what the target of the ban is.
+type BanType string
+
+const (
+	// BanName bans a player by their name.
+	BanName BanType = "name"
+	// BanXUID bans a player by their XBOX Live user ID.
+	BanXUID BanType = "xuid"
+	// BanIP bans everybody connecting from an IP address.
+	BanIP BanType = "ip"
+)
+
+// UnknownBanType gets returned when adding a ban of an unknown type.
+var UnknownBanType = errors.New("unknown ban type")
+
+// Ban is a single ban entry.
+// Name is the name of the banned player if known, also for XUID and IP bans.
+// Source is the name of whoever issued the ban.
+// Bans with a zero expiry time are permanent.
+type Ban struct {
+	Type    BanType   `json:"type"`
+	Target  string    `json:"target"`
+	Name    string    `json:"name,omitempty"`
+	Reason  string    `json:"reason,omitempty"`
+	Source  string    `json:"source,omitempty"`
+	Created time.Time `json:"created"`
+	Expires time.Time `json:"expires"`
+}
+
+// IsPermanent checks if the ban never expires.
+func (ban Ban) IsPermanent() bool {
+	return ban.Expires.IsZero()
+}
+
+// IsExpired checks if the ban has expired at the given time.
+func (ban Ban) IsExpired(now time.Time) bool {
+	return !ban.IsPermanent() && now.After(ban.Expires)
+}
+
+// GetMessage returns the message shown to banned players when disconnected.
+func (ban Ban) GetMessage() string {
+	var message = "You are banned from this server."
+	if ban.Reason != "" {
+		message += "\nReason: " + ban.Reason
+	}
+	if !ban.IsPermanent() {
+		message += "\nExpires: " + ban.Expires.Format("2006-01-02 15:04 MST")
+	}
+	return message
+}
+
+// whitelistFile is the format of the whitelist file.
+type whitelistFile struct {
+	Enabled bool     `json:"enabled"`
+	Players []string `json:"players"`
+}
+
+// Store holds all bans and the whitelist of a server.
+// Every change to the store gets persisted immediately.
+type Store struct {
+	directory string
+
+	mutex            sync.RWMutex
+	bans             map[BanType]map[string]Ban
+	whitelistEnabled bool
+	whitelist        map[string]string
+}
+
+// NewStore returns a new store persisted in the given directory.
+// Bans and the whitelist are loaded from the directory if they were persisted before.
+func NewStore(directory string) (*Store, error) {
+	var store = &Store{directory: directory, bans: make(map[BanType]map[string]Ban), whitelist: make(map[string]string)}
+	for _, banType := range []BanType{BanName, BanXUID, BanIP} {
+		store.bans[banType] = make(map[string]Ban)
+	}
+
+	var bans []Ban
+	if err := readJson(store.getBansPath(), &bans); err != nil {
+		return nil, err
+	}
+	for _, ban := range bans {
+		if entries, ok := store.bans[ban.Type]; ok {
+			entries[normalize(ban.Type, ban.Target)] = ban
+		}
+	}
+
+	var whitelist whitelistFile
+	if err := readJson(store.getWhitelistPath(), &whitelist); err != nil {
+		return nil, err
+	}
+	store.whitelistEnabled = whitelist.Enabled
+	for _, name := range whitelist.Players {
+		store.whitelist[strings.ToLower(name)] = name
+	}
+	return store, nil
+}
+
+// Ban adds the ban to the store, replacing any existing ban of the same type and target.
+// The creation time of the ban is set if it was not yet set.
+func (store *Store) Ban(ban Ban) error {
+	if ban.Created.IsZero() {
+		ban.Created = time.Now()
+	}
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	var entries, ok = store.bans[ban.Type]
+	if !ok {
+		return UnknownBanType
+	}
+	entries[normalize(ban.Type, ban.Target)] = ban
+	return store.saveBans()
+}
+
+// Pardon removes the ban of the given type and target.
+// Returns true if a ban was removed.
+func (store *Store) Pardon(banType BanType, target string) (bool, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	var entries = store.bans[banType]
+	var key = normalize(banType, target)
+	if _, ok := entries[key]; !ok {
+		return false, nil
+	}
+	delete(entries, key)
+	return true, store.saveBans()
+}
+
+// PardonPlayer removes the name ban of the player with the given name,
+// and all XUID bans issued for a player with that name.
+// Returns true if any ban was removed.
+func (store *Store) PardonPlayer(name string) (bool, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	var pardoned = false
+	if _, ok := store.bans[BanName][strings.ToLower(name)]; ok {
+		delete(store.bans[BanName], strings.ToLower(name))
+		pardoned = true
+	}
+	for xuid, ban := range store.bans[BanXUID] {
+		if strings.EqualFold(ban.Name, name) {
+			delete(store.bans[BanXUID], xuid)
+			pardoned = true
+		}
+	}
+	if !pardoned {
+		return false, nil
+	}
+	return true, store.saveBans()
+}
+
+// GetBan returns the active ban of the given type and target.
+// A bool is returned indicating if the target is banned.
+// Expired bans are never returned.
+func (store *Store) GetBan(banType BanType, target string) (Ban, bool) {
+	if target == "" {
+		return Ban{}, false
+	}
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	var ban, ok = store.bans[banType][normalize(banType, target)]
+	if !ok || ban.IsExpired(time.Now()) {
+		return Ban{}, false
+	}
+	return ban, true
+}
+
+// GetPlayerBan returns the active ban of a player with the given name or XUID.
+// A bool is returned indicating if the player is banned.
+func (store *Store) GetPlayerBan(name string, xuid string) (Ban, bool) {
+	if ban, ok := store.GetBan(BanName, name); ok {
+		return ban, true
+	}
+	return store.GetBan(BanXUID, xuid)
+}
+
+// GetBans returns all active bans of the given type, sorted by creation time.
+func (store *Store) GetBans(banType BanType) []Ban {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	var now = time.Now()
+	var bans []Ban
+	for _, ban := range store.bans[banType] {
+		if !ban.IsExpired(now) {
+			bans = append(bans, ban)
+		}
+	}
+	sort.Slice(bans, func(i, j int) bool {
+		return bans[i].Created.Before(bans[j].Created)
+	})
+	return bans
+}
+
+// IsWhitelistEnabled checks if the whitelist is enabled.
+// Only whitelisted players may join once enabled.
+func (store *Store) IsWhitelistEnabled() bool {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	return store.whitelistEnabled
+}
+
+// SetWhitelistEnabled enables or disables the whitelist.
+func (store *Store) SetWhitelistEnabled(value bool) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	store.whitelistEnabled = value
+	return store.saveWhitelist()
+}
+
+// IsWhitelisted checks if the player with the given name is on the whitelist.
+// This does not check if the whitelist is enabled.
+func (store *Store) IsWhitelisted(name string) bool {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	var _, ok = store.whitelist[strings.ToLower(name)]
+	return ok
+}
+
+// CanJoin checks if the player with the given name may join with regards to the whitelist.
+func (store *Store) CanJoin(name string) bool {
+	return !store.IsWhitelistEnabled() || store.IsWhitelisted(name)
+}
+
+// AddToWhitelist adds the player with the given name to the whitelist.
+func (store *Store) AddToWhitelist(name string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	store.whitelist[strings.ToLower(name)] = name
+	return store.saveWhitelist()
+}
+
+// RemoveFromWhitelist removes the player with the given name from the whitelist.
+// Returns true if the player was on the whitelist.
+func (store *Store) RemoveFromWhitelist(name string) (bool, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	if _, ok := store.whitelist[strings.ToLower(name)]; !ok {
+		return false, nil
+	}
+	delete(store.whitelist, strings.ToLower(name))
+	return true, store.saveWhitelist()
+}
+
+// GetWhitelist returns the names of all whitelisted players, sorted alphabetically.
+func (store *Store) GetWhitelist() []string {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+	var names = make([]string, 0, len(store.whitelist))
+	for _, name := range store.whitelist {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// getBansPath returns the path of the bans file.
+func (store *Store) getBansPath() string {
+	return filepath.Join(store.directory, "bans.json")
+}
+
+// getWhitelistPath returns the path of the whitelist file.
+func (store *Store) getWhitelistPath() string {
+	return filepath.Join(store.directory, "whitelist.json")
+}
+
+// saveBans writes all bans to the bans file.
+// Expired bans are dropped when saving.
+func (store *Store) saveBans() error {
+	var now = time.Now()
+	var bans = make([]Ban, 0)
+	for _, entries := range store.bans {
+		for key, ban := range entries {
+			if ban.IsExpired(now) {
+				delete(entries, key)
+				continue
+			}
+			bans = append(bans, ban)
+		}
+	}
+	sort.Slice(bans, func(i, j int) bool {
+		return bans[i].Created.Before(bans[j].Created)
+	})
+	return writeJson(store.getBansPath(), bans)
+}
+
+// saveWhitelist writes the whitelist to the whitelist file.
+func (store *Store) saveWhitelist() error {
+	var file = whitelistFile{Enabled: store.whitelistEnabled, Players: make([]string, 0, len(store.whitelist))}
+	for _, name := range store.whitelist {
+		file.Players = append(file.Players, name)
+	}
+	sort.Strings(file.Players)
+	return writeJson(store.getWhitelistPath(), file)
+}
+
+// normalize returns the key a target of the given ban type is stored under.
+// Names are case insensitive.
+func normalize(banType BanType, target string) string {
+	if banType == BanName {
+		return strings.ToLower(target)
+	}
+	return target
+}
+
+// readJson reads the JSON file at the path into v.
+// Nothing is read if the file does not exist.
+func readJson(path string, v interface{}) error {
+	var data, err = ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// writeJson writes v as JSON to the file at the path.
+// The file is replaced atomically, so that a crash while writing never corrupts it.
+func writeJson(path string, v interface{}) error {
+	var data, err = json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	var temp = path + ".tmp"
+	if err := ioutil.WriteFile(temp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(temp, path)
+}
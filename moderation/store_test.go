@@ -0,0 +1,78 @@
+package moderation
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBans(t *testing.T) {
+	var directory = t.TempDir()
+	store, err := NewStore(directory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Ban(Ban{Type: BanName, Target: "Steve", Reason: "Griefing", Source: "CONSOLE"})
+	store.Ban(Ban{Type: BanXUID, Target: "2535400000000000", Name: "Alex"})
+	store.Ban(Ban{Type: BanIP, Target: "10.0.0.1", Expires: time.Now().Add(time.Hour)})
+	store.Ban(Ban{Type: BanIP, Target: "10.0.0.2", Expires: time.Now().Add(-time.Hour)})
+
+	store, err = NewStore(directory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ban, ok := store.GetPlayerBan("steve", ""); !ok || ban.Reason != "Griefing" {
+		t.Fatalf("expected name ban of steve to persist, got %+v (%v)", ban, ok)
+	}
+	if _, ok := store.GetPlayerBan("Alex2", "2535400000000000"); !ok {
+		t.Fatal("expected XUID ban to apply under a different name")
+	}
+	if _, ok := store.GetBan(BanIP, "10.0.0.1"); !ok {
+		t.Fatal("expected temporary IP ban to be active")
+	}
+	if _, ok := store.GetBan(BanIP, "10.0.0.2"); ok {
+		t.Fatal("expired IP ban is still active")
+	}
+	if bans := store.GetBans(BanIP); len(bans) != 1 {
+		t.Fatalf("expected 1 active IP ban, got %v", len(bans))
+	}
+
+	if pardoned, _ := store.PardonPlayer("alex"); !pardoned {
+		t.Fatal("pardoning alex did not remove the XUID ban issued for alex")
+	}
+	if _, ok := store.GetPlayerBan("", "2535400000000000"); ok {
+		t.Fatal("XUID ban is still active after pardoning")
+	}
+	if pardoned, _ := store.Pardon(BanIP, "10.0.0.1"); !pardoned {
+		t.Fatal("pardoning IP did not remove the IP ban")
+	}
+	if pardoned, _ := store.Pardon(BanIP, "10.0.0.1"); pardoned {
+		t.Fatal("pardoned an IP that was no longer banned")
+	}
+}
+
+func TestWhitelist(t *testing.T) {
+	var directory = t.TempDir()
+	store, err := NewStore(directory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !store.CanJoin("Steve") {
+		t.Fatal("player can not join while the whitelist is disabled")
+	}
+	store.SetWhitelistEnabled(true)
+	store.AddToWhitelist("Steve")
+
+	store, err = NewStore(directory)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !store.IsWhitelistEnabled() {
+		t.Fatal("whitelist state did not persist")
+	}
+	if !store.CanJoin("steve") || store.CanJoin("Alex") {
+		t.Fatal("whitelist did not persist its players")
+	}
+	if removed, _ := store.RemoveFromWhitelist("STEVE"); !removed || store.CanJoin("Steve") {
+		t.Fatal("removing from the whitelist did not remove the player")
+	}
+}
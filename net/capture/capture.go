@@ -0,0 +1,144 @@
+// Package capture implements reading and writing of packet capture files.
+// A capture file holds all packets exchanged with a single session, decrypted and decompressed,
+// each with the time it was sent or received and the direction it travelled in.
+package capture
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"time"
+)
+
+// Magic is the header every capture file starts with.
+var Magic = []byte("GMCAP")
+
+// Version is the version of the capture file format written.
+const Version = 1
+
+// MaximumRecordSize is the maximum size of the data of a single record.
+// Larger records are considered corrupted.
+const MaximumRecordSize = 1 << 26
+
+// InvalidHeader gets returned when reading a file that is not a capture file,
+// or a capture file of an unsupported version.
+var InvalidHeader = errors.New("invalid capture file header")
+
+// Direction is the direction a captured packet travelled in.
+type Direction byte
+
+const (
+	// Inbound packets were sent by the client to the server.
+	Inbound Direction = iota
+	// Outbound packets were sent by the server to the client.
+	Outbound
+)
+
+// String returns a readable representation of the direction.
+func (direction Direction) String() string {
+	if direction == Inbound {
+		return "C->S"
+	}
+	return "S->C"
+}
+
+// Record is a single captured packet.
+// Data is the full packet buffer, including the packet header.
+type Record struct {
+	Time      time.Time
+	Direction Direction
+	Data      []byte
+}
+
+// Writer writes records to a capture file.
+type Writer struct {
+	writer *bufio.Writer
+}
+
+// NewWriter returns a new writer writing to w.
+// The header of the capture file is written immediately.
+func NewWriter(w io.Writer) (*Writer, error) {
+	var writer = &Writer{bufio.NewWriter(w)}
+	writer.writer.Write(Magic)
+	if err := writer.writer.WriteByte(Version); err != nil {
+		return nil, err
+	}
+	return writer, nil
+}
+
+// WriteRecord writes a single record.
+// Records are buffered until the writer gets flushed.
+func (writer *Writer) WriteRecord(record Record) error {
+	var header = make([]byte, 9+binary.MaxVarintLen64)
+	header[0] = byte(record.Direction)
+	binary.BigEndian.PutUint64(header[1:9], uint64(record.Time.UnixNano()))
+	var n = binary.PutUvarint(header[9:], uint64(len(record.Data)))
+
+	if _, err := writer.writer.Write(header[:9+n]); err != nil {
+		return err
+	}
+	_, err := writer.writer.Write(record.Data)
+	return err
+}
+
+// Flush writes all buffered records to the underlying writer.
+func (writer *Writer) Flush() error {
+	return writer.writer.Flush()
+}
+
+// Reader reads records from a capture file.
+type Reader struct {
+	reader *bufio.Reader
+}
+
+// NewReader returns a new reader reading from r.
+// InvalidHeader is returned if r does not contain a capture file.
+func NewReader(r io.Reader) (*Reader, error) {
+	var reader = &Reader{bufio.NewReader(r)}
+	var header = make([]byte, len(Magic)+1)
+	if _, err := io.ReadFull(reader.reader, header); err != nil {
+		return nil, InvalidHeader
+	}
+	if string(header[:len(Magic)]) != string(Magic) || header[len(Magic)] != Version {
+		return nil, InvalidHeader
+	}
+	return reader, nil
+}
+
+// ReadRecord reads the next record.
+// io.EOF is returned once all records were read.
+func (reader *Reader) ReadRecord() (Record, error) {
+	var header = make([]byte, 9)
+	if _, err := io.ReadFull(reader.reader, header); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			return Record{}, err
+		}
+		return Record{}, io.EOF
+	}
+	length, err := binary.ReadUvarint(reader.reader)
+	if err != nil || length > MaximumRecordSize {
+		return Record{}, io.ErrUnexpectedEOF
+	}
+
+	var record = Record{Time: time.Unix(0, int64(binary.BigEndian.Uint64(header[1:9]))), Direction: Direction(header[0]), Data: make([]byte, length)}
+	if _, err := io.ReadFull(reader.reader, record.Data); err != nil {
+		return Record{}, io.ErrUnexpectedEOF
+	}
+	return record, nil
+}
+
+// ReadAll reads all records left in the capture file.
+func (reader *Reader) ReadAll() ([]Record, error) {
+	var records []Record
+	for {
+		var record, err = reader.ReadRecord()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return records, err
+		}
+		records = append(records, record)
+	}
+}
@@ -0,0 +1,90 @@
+package capture
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRoundTrip(t *testing.T) {
+	var buffer = bytes.Buffer{}
+	writer, err := NewWriter(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var records = []Record{
+		{time.Unix(0, 1), Inbound, []byte{0x01, 0x02, 0x03}},
+		{time.Unix(10, 5), Outbound, bytes.Repeat([]byte{0xFF}, 300)},
+		{time.Unix(11, 0), Inbound, []byte{}},
+	}
+	for _, record := range records {
+		writer.WriteRecord(record)
+	}
+	writer.Flush()
+
+	reader, err := NewReader(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	read, err := reader.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(read) != len(records) {
+		t.Fatalf("read %v records, expected %v", len(read), len(records))
+	}
+	for i, record := range read {
+		if !record.Time.Equal(records[i].Time) || record.Direction != records[i].Direction || !bytes.Equal(record.Data, records[i].Data) {
+			t.Errorf("record %v: got %+v, expected %+v", i, record, records[i])
+		}
+	}
+}
+
+func TestInvalidFiles(t *testing.T) {
+	if _, err := NewReader(bytes.NewReader([]byte("not a capture"))); err != InvalidHeader {
+		t.Fatalf("expected InvalidHeader, got %v", err)
+	}
+
+	var buffer = bytes.Buffer{}
+	var writer, _ = NewWriter(&buffer)
+	writer.WriteRecord(Record{time.Now(), Inbound, []byte{0x01, 0x02, 0x03}})
+	writer.Flush()
+
+	var reader, _ = NewReader(bytes.NewReader(buffer.Bytes()[:buffer.Len()-1]))
+	if _, err := reader.ReadRecord(); err != io.ErrUnexpectedEOF {
+		t.Fatalf("expected io.ErrUnexpectedEOF for a truncated record, got %v", err)
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	var directory = t.TempDir()
+	recorder, err := NewRecorder(filepath.Join(directory, "captures"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder.Record("127.0.0.1:19132", Inbound, []byte{0x01})
+	recorder.Record("127.0.0.1:19133", Inbound, []byte{0x02})
+	recorder.Record("127.0.0.1:19132", Outbound, []byte{0x03})
+	recorder.Close("127.0.0.1:19132")
+	recorder.CloseAll()
+
+	files, _ := filepath.Glob(filepath.Join(recorder.GetDirectory(), "*.gmcap"))
+	if len(files) != 2 {
+		t.Fatalf("expected 2 capture files, got %v", len(files))
+	}
+	for _, path := range files {
+		var file, _ = os.Open(path)
+		var reader, err = NewReader(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var records, _ = reader.ReadAll()
+		file.Close()
+		if len(records) == 0 {
+			t.Fatalf("capture file %v holds no records", path)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package capture
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Recorder records the packets of sessions, writing a capture file for every session.
+// Sessions are identified by a key, usually their address.
+type Recorder struct {
+	directory string
+
+	mutex    sync.Mutex
+	sessions map[string]*sessionCapture
+}
+
+// sessionCapture is the capture file of a single session.
+type sessionCapture struct {
+	file   *os.File
+	writer *Writer
+}
+
+// NewRecorder returns a new recorder writing capture files to the given directory.
+// The directory gets created if it does not yet exist.
+func NewRecorder(directory string) (*Recorder, error) {
+	if err := os.MkdirAll(directory, 0700); err != nil {
+		return nil, err
+	}
+	return &Recorder{directory: directory, sessions: make(map[string]*sessionCapture)}, nil
+}
+
+// GetDirectory returns the directory capture files are written to.
+func (recorder *Recorder) GetDirectory() string {
+	return recorder.directory
+}
+
+// Record records a packet of the session with the given key.
+// A new capture file is created for the session if it did not yet have one.
+// The data gets copied, and may be modified after recording.
+func (recorder *Recorder) Record(key string, direction Direction, data []byte) error {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	var capture, ok = recorder.sessions[key]
+	if !ok {
+		var name = fmt.Sprint(time.Now().Unix(), "-", strings.NewReplacer(":", "_", "[", "", "]", "").Replace(key), ".gmcap")
+		file, err := os.OpenFile(filepath.Join(recorder.directory, name), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+		if err != nil {
+			return err
+		}
+		writer, err := NewWriter(file)
+		if err != nil {
+			file.Close()
+			return err
+		}
+		capture = &sessionCapture{file, writer}
+		recorder.sessions[key] = capture
+	}
+	return capture.writer.WriteRecord(Record{Time: time.Now(), Direction: direction, Data: data})
+}
+
+// Close closes the capture file of the session with the given key.
+// Close does nothing if the session has no capture file.
+func (recorder *Recorder) Close(key string) error {
+	recorder.mutex.Lock()
+	var capture, ok = recorder.sessions[key]
+	delete(recorder.sessions, key)
+	recorder.mutex.Unlock()
+
+	if !ok {
+		return nil
+	}
+	return capture.close()
+}
+
+// Flush writes all buffered records of every session to their capture files.
+func (recorder *Recorder) Flush() {
+	recorder.mutex.Lock()
+	for _, capture := range recorder.sessions {
+		capture.writer.Flush()
+	}
+	recorder.mutex.Unlock()
+}
+
+// CloseAll closes the capture files of all sessions.
+func (recorder *Recorder) CloseAll() {
+	recorder.mutex.Lock()
+	var sessions = recorder.sessions
+	recorder.sessions = make(map[string]*sessionCapture)
+	recorder.mutex.Unlock()
+
+	for _, capture := range sessions {
+		capture.close()
+	}
+}
+
+// close flushes and closes the capture file.
+func (capture *sessionCapture) close() error {
+	if err := capture.writer.Flush(); err != nil {
+		capture.file.Close()
+		return err
+	}
+	return capture.file.Close()
+}
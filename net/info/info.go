@@ -8,6 +8,17 @@ const (
 
 type PacketIdList map[PacketName]int
 
+// GetPacketName returns the name of the packet with the given ID.
+// A bool is returned indicating success.
+func (list PacketIdList) GetPacketName(id int) (PacketName, bool) {
+	for name, packetId := range list {
+		if packetId == id {
+			return name, true
+		}
+	}
+	return "", false
+}
+
 type PacketName string
 const (
 	LoginPacket                       PacketName = "LoginPacket"
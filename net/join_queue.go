@@ -0,0 +1,145 @@
+package net
+
+import (
+	"sync"
+)
+
+// JoinQueue is a first-in first-out queue of sessions that logged in while the server was full.
+// Queued sessions are not yet added to the session manager, and get admitted once a slot frees up.
+type JoinQueue struct {
+	mutex       sync.Mutex
+	maximumSize int
+	sessions    []*MinecraftSession
+	held        map[Connection]bool
+}
+
+// NewJoinQueue returns a new join queue holding at most maximumSize sessions.
+// A maximum size of 0 or lower makes the size of the queue unlimited.
+func NewJoinQueue(maximumSize int) *JoinQueue {
+	return &JoinQueue{maximumSize: maximumSize, held: make(map[Connection]bool)}
+}
+
+// Add adds the session to the back of the queue, and returns its position in the queue, starting at 1.
+// A bool is returned indicating if the session was added. Sessions do not get added if the queue is full.
+// Adding a session that is already in the queue returns its current position.
+func (queue *JoinQueue) Add(session *MinecraftSession) (int, bool) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	if index := queue.indexOf(session.GetConnection()); index != -1 {
+		return index + 1, true
+	}
+	if queue.maximumSize > 0 && len(queue.sessions) >= queue.maximumSize {
+		return 0, false
+	}
+	queue.sessions = append(queue.sessions, session)
+	return len(queue.sessions), true
+}
+
+// Peek returns the session at the front of the queue without removing it.
+// A bool is returned indicating if the queue had any sessions.
+func (queue *JoinQueue) Peek() (*MinecraftSession, bool) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	if len(queue.sessions) == 0 {
+		return nil, false
+	}
+	return queue.sessions[0], true
+}
+
+// Pop removes and returns the session at the front of the queue.
+// A bool is returned indicating if the queue had any sessions.
+func (queue *JoinQueue) Pop() (*MinecraftSession, bool) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	if len(queue.sessions) == 0 {
+		return nil, false
+	}
+	var session = queue.sessions[0]
+	queue.sessions[0] = nil
+	queue.sessions = queue.sessions[1:]
+	return session, true
+}
+
+// Remove removes the session with the given connection from the queue.
+// Returns true if a session was removed.
+func (queue *JoinQueue) Remove(connection Connection) bool {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	delete(queue.held, connection)
+	var index = queue.indexOf(connection)
+	if index == -1 {
+		return false
+	}
+	queue.sessions = append(queue.sessions[:index], queue.sessions[index+1:]...)
+	return true
+}
+
+// Hold marks the queued session with the given connection as held, meaning it spawned while still in the queue.
+// Held sessions have to be spawned to other players once they leave the queue.
+// Returns false if the session is not in the queue, in which case it does not get marked.
+func (queue *JoinQueue) Hold(connection Connection) bool {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	if queue.indexOf(connection) == -1 {
+		return false
+	}
+	queue.held[connection] = true
+	return true
+}
+
+// Release removes the held mark of the session with the given connection, once it left the queue.
+// Returns true if the session was held.
+func (queue *JoinQueue) Release(connection Connection) bool {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	var held = queue.held[connection]
+	delete(queue.held, connection)
+	return held
+}
+
+// GetSession returns the queued session with the given connection.
+// A bool is returned indicating if the session is in the queue.
+func (queue *JoinQueue) GetSession(connection Connection) (*MinecraftSession, bool) {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	if index := queue.indexOf(connection); index != -1 {
+		return queue.sessions[index], true
+	}
+	return nil, false
+}
+
+// GetPosition returns the position of the session with the given connection in the queue, starting at 1.
+// 0 is returned if the session is not in the queue.
+func (queue *JoinQueue) GetPosition(connection Connection) int {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	return queue.indexOf(connection) + 1
+}
+
+// GetLength returns the amount of sessions in the queue.
+func (queue *JoinQueue) GetLength() int {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	return len(queue.sessions)
+}
+
+// GetSessions returns all sessions in the queue, in the order they are in the queue.
+func (queue *JoinQueue) GetSessions() []*MinecraftSession {
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+	var sessions = make([]*MinecraftSession, len(queue.sessions))
+	copy(sessions, queue.sessions)
+	return sessions
+}
+
+// indexOf returns the index of the session with the given connection in the queue, or -1 if it is not queued.
+func (queue *JoinQueue) indexOf(connection Connection) int {
+	for index, session := range queue.sessions {
+		if session.GetConnection() == connection {
+			return index
+		}
+	}
+	return -1
+}
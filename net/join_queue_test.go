@@ -0,0 +1,104 @@
+package net
+
+import (
+	"net"
+	"testing"
+)
+
+// queueConnection is a connection that only identifies a queued session.
+type queueConnection struct {
+	port int
+}
+
+func (connection *queueConnection) SendBatch(*MinecraftPacketBatch) {}
+
+func (connection *queueConnection) GetPing() int64 {
+	return 0
+}
+
+func (connection *queueConnection) GetAddress() *net.UDPAddr {
+	return &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: connection.port}
+}
+
+func (connection *queueConnection) Close() error {
+	return nil
+}
+
+func newQueuedSession(port int) *MinecraftSession {
+	return &MinecraftSession{connection: &queueConnection{port}}
+}
+
+func TestJoinQueue(t *testing.T) {
+	var queue = NewJoinQueue(3)
+	var sessions = []*MinecraftSession{newQueuedSession(1), newQueuedSession(2), newQueuedSession(3), newQueuedSession(4)}
+
+	for i, session := range sessions[:3] {
+		if position, ok := queue.Add(session); !ok || position != i+1 {
+			t.Fatalf("session %v got position %v (%v), expected %v", i, position, ok, i+1)
+		}
+	}
+	if _, ok := queue.Add(sessions[3]); ok {
+		t.Fatal("session was added to a full queue")
+	}
+	if position, ok := queue.Add(sessions[1]); !ok || position != 2 {
+		t.Fatalf("re-adding a queued session changed its position to %v", position)
+	}
+
+	if !queue.Remove(sessions[1].GetConnection()) {
+		t.Fatal("queued session was not removed")
+	}
+	if position := queue.GetPosition(sessions[2].GetConnection()); position != 2 {
+		t.Fatalf("session behind a removed session is at position %v, expected 2", position)
+	}
+	if position := queue.GetPosition(sessions[1].GetConnection()); position != 0 {
+		t.Fatalf("removed session is still at position %v", position)
+	}
+
+	if session, ok := queue.Pop(); !ok || session != sessions[0] {
+		t.Fatal("first session in the queue was not popped first")
+	}
+	if session, ok := queue.Peek(); !ok || session != sessions[2] {
+		t.Fatal("peek did not return the front of the queue")
+	}
+	if length := queue.GetLength(); length != 1 {
+		t.Fatalf("queue has length %v, expected 1", length)
+	}
+	queue.Pop()
+	if _, ok := queue.Pop(); ok {
+		t.Fatal("popped a session from an empty queue")
+	}
+}
+
+func TestJoinQueueHold(t *testing.T) {
+	var queue = NewJoinQueue(0)
+	var held, waiting = newQueuedSession(1), newQueuedSession(2)
+	queue.Add(held)
+	queue.Add(waiting)
+
+	if queue.Hold(newQueuedSession(3).GetConnection()) {
+		t.Fatal("held a session that is not in the queue")
+	}
+	if !queue.Hold(held.GetConnection()) {
+		t.Fatal("queued session could not be held")
+	}
+	if session, ok := queue.GetSession(held.GetConnection()); !ok || session != held {
+		t.Fatal("held session could not be found in the queue")
+	}
+
+	queue.Pop()
+	if queue.Hold(held.GetConnection()) {
+		t.Fatal("held a session that left the queue")
+	}
+	if !queue.Release(held.GetConnection()) {
+		t.Fatal("session that spawned in the queue was not released as held")
+	}
+	if queue.Release(held.GetConnection()) || queue.Release(waiting.GetConnection()) {
+		t.Fatal("released a session that is not held")
+	}
+
+	queue.Hold(waiting.GetConnection())
+	queue.Remove(waiting.GetConnection())
+	if queue.Release(waiting.GetConnection()) {
+		t.Fatal("removed session is still held")
+	}
+}
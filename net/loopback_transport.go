@@ -0,0 +1,250 @@
+package net
+
+import (
+	"errors"
+	"io"
+	"net"
+	"sync"
+)
+
+// LoopbackQueueSize is the amount of batches a loopback connection can hold in each direction,
+// before writing to the connection blocks.
+const LoopbackQueueSize = 1024
+
+// LoopbackNotRunning gets returned when connecting to a loopback transport that was not started,
+// or when writing to a connection of a stopped loopback transport.
+var LoopbackNotRunning = errors.New("loopback transport is not running")
+
+// LoopbackTransport is an in-memory transport, which exchanges batches over channels.
+// Clients connect to it within the same process using Connect,
+// which makes it suitable for tests, bots and replaying captured traffic without any sockets.
+type LoopbackTransport struct {
+	mutex       sync.RWMutex
+	running     bool
+	serverId    int64
+	pongData    string
+	nextPort    int
+	connections map[*LoopbackConnection]bool
+
+	packetFunction     func(packet []byte, connection Connection)
+	connectFunction    func(connection Connection)
+	disconnectFunction func(connection Connection)
+	rawPacketFunction  func(packet []byte, addr *net.UDPAddr)
+}
+
+// LoopbackConnection is a connection of the loopback transport.
+// The server side sends batches with SendBatch,
+// while the client side uses WriteBatch and ReadBatch.
+type LoopbackConnection struct {
+	transport   *LoopbackTransport
+	address     *net.UDPAddr
+	serverBound chan []byte
+	clientBound chan []byte
+	closed      chan struct{}
+	closeOnce   sync.Once
+}
+
+// NewLoopbackTransport returns a new loopback transport with the given server ID.
+func NewLoopbackTransport(serverId int64) *LoopbackTransport {
+	return &LoopbackTransport{serverId: serverId, nextPort: 1, connections: make(map[*LoopbackConnection]bool)}
+}
+
+// Start starts the loopback transport. The address and port are ignored,
+// as loopback connections are made in memory.
+func (transport *LoopbackTransport) Start(address string, port uint16) error {
+	transport.mutex.Lock()
+	transport.running = true
+	transport.mutex.Unlock()
+	return nil
+}
+
+// Stop stops the loopback transport and closes all of its connections.
+func (transport *LoopbackTransport) Stop() {
+	transport.mutex.Lock()
+	transport.running = false
+	var connections = make([]*LoopbackConnection, 0, len(transport.connections))
+	for connection := range transport.connections {
+		connections = append(connections, connection)
+	}
+	transport.mutex.Unlock()
+
+	for _, connection := range connections {
+		connection.Close()
+	}
+}
+
+// IsRunning checks if the loopback transport is running.
+func (transport *LoopbackTransport) IsRunning() bool {
+	transport.mutex.RLock()
+	defer transport.mutex.RUnlock()
+	return transport.running
+}
+
+// GetServerId returns the server ID of the loopback transport.
+func (transport *LoopbackTransport) GetServerId() int64 {
+	return transport.serverId
+}
+
+// GetPongData returns the pong data last set on the loopback transport.
+func (transport *LoopbackTransport) GetPongData() string {
+	transport.mutex.RLock()
+	defer transport.mutex.RUnlock()
+	return transport.pongData
+}
+
+// SetPongData sets the pong data of the loopback transport.
+func (transport *LoopbackTransport) SetPongData(data string) {
+	transport.mutex.Lock()
+	transport.pongData = data
+	transport.mutex.Unlock()
+}
+
+// SetPacketFunction sets the function called for every batch written by a client.
+func (transport *LoopbackTransport) SetPacketFunction(function func(packet []byte, connection Connection)) {
+	transport.packetFunction = function
+}
+
+// SetConnectFunction sets the function called once a client connected.
+func (transport *LoopbackTransport) SetConnectFunction(function func(connection Connection)) {
+	transport.connectFunction = function
+}
+
+// SetDisconnectFunction sets the function called once a connection got closed.
+func (transport *LoopbackTransport) SetDisconnectFunction(function func(connection Connection)) {
+	transport.disconnectFunction = function
+}
+
+// SetRawPacketFunction sets the function called for raw packets sent with WriteRaw.
+func (transport *LoopbackTransport) SetRawPacketFunction(function func(packet []byte, addr *net.UDPAddr)) {
+	transport.rawPacketFunction = function
+}
+
+// WriteRaw passes a raw packet, such as a query packet, to the raw packet function.
+func (transport *LoopbackTransport) WriteRaw(packet []byte, addr *net.UDPAddr) error {
+	if !transport.IsRunning() {
+		return LoopbackNotRunning
+	}
+	if transport.rawPacketFunction != nil {
+		transport.rawPacketFunction(packet, addr)
+	}
+	return nil
+}
+
+// GetConnectionCount returns the amount of open connections of the loopback transport.
+func (transport *LoopbackTransport) GetConnectionCount() int {
+	transport.mutex.RLock()
+	defer transport.mutex.RUnlock()
+	return len(transport.connections)
+}
+
+// Connect opens a new connection to the loopback transport.
+// Every connection gets a unique synthetic 127.0.0.1 address.
+func (transport *LoopbackTransport) Connect() (*LoopbackConnection, error) {
+	transport.mutex.Lock()
+	if !transport.running {
+		transport.mutex.Unlock()
+		return nil, LoopbackNotRunning
+	}
+	var connection = &LoopbackConnection{
+		transport:   transport,
+		address:     &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: transport.nextPort},
+		serverBound: make(chan []byte, LoopbackQueueSize),
+		clientBound: make(chan []byte, LoopbackQueueSize),
+		closed:      make(chan struct{}),
+	}
+	transport.nextPort++
+	transport.connections[connection] = true
+	transport.mutex.Unlock()
+
+	if transport.connectFunction != nil {
+		transport.connectFunction(connection)
+	}
+	go connection.process()
+	return connection, nil
+}
+
+// process passes all batches written by the client to the packet function,
+// until the connection gets closed.
+func (connection *LoopbackConnection) process() {
+	for {
+		select {
+		case <-connection.closed:
+			return
+		case packet := <-connection.serverBound:
+			if connection.transport.packetFunction != nil {
+				connection.transport.packetFunction(packet, connection)
+			}
+		}
+	}
+}
+
+// SendBatch encodes the batch and queues it for the client to read.
+// SendBatch blocks if the client does not read the batches fast enough.
+func (connection *LoopbackConnection) SendBatch(batch *MinecraftPacketBatch) {
+	batch.Encode()
+	var buffer = make([]byte, len(batch.Buffer))
+	copy(buffer, batch.Buffer)
+
+	select {
+	case <-connection.closed:
+	case connection.clientBound <- buffer:
+	}
+}
+
+// GetPing returns the ping of the connection, which is always 0 for loopback connections.
+func (connection *LoopbackConnection) GetPing() int64 {
+	return 0
+}
+
+// GetAddress returns the synthetic address of the connection.
+func (connection *LoopbackConnection) GetAddress() *net.UDPAddr {
+	return connection.address
+}
+
+// WriteBatch writes an encoded batch from the client to the server.
+func (connection *LoopbackConnection) WriteBatch(packet []byte) error {
+	var buffer = make([]byte, len(packet))
+	copy(buffer, packet)
+
+	select {
+	case <-connection.closed:
+		return LoopbackNotRunning
+	case connection.serverBound <- buffer:
+		return nil
+	}
+}
+
+// ReadBatch reads an encoded batch sent by the server, blocking until one is available.
+// Batches sent before the connection got closed can still be read,
+// after which io.EOF is returned.
+func (connection *LoopbackConnection) ReadBatch() ([]byte, error) {
+	select {
+	case packet := <-connection.clientBound:
+		return packet, nil
+	case <-connection.closed:
+		select {
+		case packet := <-connection.clientBound:
+			return packet, nil
+		default:
+			return nil, io.EOF
+		}
+	}
+}
+
+// Close closes the connection, and calls the disconnect function of the transport.
+// Closing a connection more than once has no effect.
+func (connection *LoopbackConnection) Close() error {
+	connection.closeOnce.Do(func() {
+		close(connection.closed)
+
+		var transport = connection.transport
+		transport.mutex.Lock()
+		delete(transport.connections, connection)
+		transport.mutex.Unlock()
+
+		if transport.disconnectFunction != nil {
+			transport.disconnectFunction(connection)
+		}
+	})
+	return nil
+}
@@ -0,0 +1,67 @@
+package net
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestLoopbackTransport(t *testing.T) {
+	var transport = NewLoopbackTransport(1)
+	var received = make(chan []byte, 1)
+	var disconnected = make(chan Connection, 1)
+	transport.SetPacketFunction(func(packet []byte, connection Connection) {
+		received <- packet
+	})
+	transport.SetDisconnectFunction(func(connection Connection) {
+		disconnected <- connection
+	})
+
+	if _, err := transport.Connect(); err != LoopbackNotRunning {
+		t.Fatalf("expected LoopbackNotRunning before start, got %v", err)
+	}
+	transport.Start("", 0)
+
+	connection, err := transport.Connect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second, _ := transport.Connect(); second.GetAddress().String() == connection.GetAddress().String() {
+		t.Fatal("loopback connections share the same address")
+	}
+
+	var batch = []byte{McpeFlag, 0x01, 0x02}
+	connection.WriteBatch(batch)
+	select {
+	case packet := <-received:
+		if !bytes.Equal(packet, batch) {
+			t.Fatalf("received %x, expected %x", packet, batch)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("batch written by client was never received")
+	}
+
+	connection.SendBatch(NewMinecraftPacketBatch(nil))
+	packet, err := connection.ReadBatch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(packet) == 0 || packet[0] != McpeFlag {
+		t.Fatalf("sent batch %x does not start with the Minecraft flag", packet)
+	}
+
+	connection.Close()
+	connection.Close()
+	if <-disconnected != Connection(connection) {
+		t.Fatal("disconnect function called with wrong connection")
+	}
+	if _, err := connection.ReadBatch(); err != io.EOF {
+		t.Fatalf("expected io.EOF after close, got %v", err)
+	}
+
+	transport.Stop()
+	if transport.GetConnectionCount() != 0 {
+		t.Fatal("connections left open after stopping the transport")
+	}
+}
@@ -1,9 +1,7 @@
 package net
 
 import (
-	"fmt"
 	"github.com/google/uuid"
-	"github.com/irmine/goraklib/server"
 	"sync"
 )
 
@@ -14,17 +12,24 @@ type SessionManager struct {
 	nameMap    map[string]*MinecraftSession
 	uuidMap    map[uuid.UUID]*MinecraftSession
 	xuidMap    map[string]*MinecraftSession
-	sessionMap map[string]*MinecraftSession
+	sessionMap map[Connection]*MinecraftSession
 }
 
 // NewSessionManager returns a new session manager.
 func NewSessionManager() *SessionManager {
-	return &SessionManager{sync.RWMutex{}, make(map[string]*MinecraftSession), make(map[uuid.UUID]*MinecraftSession), make(map[string]*MinecraftSession), make(map[string]*MinecraftSession)}
+	return &SessionManager{sync.RWMutex{}, make(map[string]*MinecraftSession), make(map[uuid.UUID]*MinecraftSession), make(map[string]*MinecraftSession), make(map[Connection]*MinecraftSession)}
 }
 
-// GetSessions returns the name => session map of the manager.
+// GetSessions returns a copy of the name => session map of the manager.
+// The copy may be ranged over safely while sessions get added or removed.
 func (manager *SessionManager) GetSessions() map[string]*MinecraftSession {
-	return manager.nameMap
+	manager.mutex.RLock()
+	defer manager.mutex.RUnlock()
+	var sessions = make(map[string]*MinecraftSession, len(manager.nameMap))
+	for name, session := range manager.nameMap {
+		sessions[name] = session
+	}
+	return sessions
 }
 
 // AddMinecraftSession adds the given Minecraft session to the manager.
@@ -33,24 +38,40 @@ func (manager *SessionManager) AddMinecraftSession(session *MinecraftSession) {
 	manager.nameMap[session.GetName()] = session
 	manager.uuidMap[session.GetUUID()] = session
 	manager.xuidMap[session.GetXUID()] = session
-	manager.sessionMap[fmt.Sprint(session.GetSession())] = session
+	manager.sessionMap[session.GetConnection()] = session
 	manager.mutex.Unlock()
 }
 
 // RemoveMinecraftSession removes a Minecraft session from the manager.
-func (manager *SessionManager) RemoveMinecraftSession(session *MinecraftSession) {
-	if session != nil {
-		manager.mutex.Lock()
+// Keys that were taken over by a newer session, such as the name of a player
+// that logged in again, are left untouched.
+// Returns true if the session was in the manager, and false if it was already removed.
+func (manager *SessionManager) RemoveMinecraftSession(session *MinecraftSession) bool {
+	if session == nil {
+		return false
+	}
+	manager.mutex.Lock()
+	defer manager.mutex.Unlock()
+	if manager.sessionMap[session.GetConnection()] != session {
+		return false
+	}
+	if manager.nameMap[session.GetPlayer().GetName()] == session {
 		delete(manager.nameMap, session.GetPlayer().GetName())
+	}
+	if manager.uuidMap[session.GetUUID()] == session {
 		delete(manager.uuidMap, session.GetUUID())
+	}
+	if manager.xuidMap[session.GetXUID()] == session {
 		delete(manager.xuidMap, session.GetXUID())
-		delete(manager.sessionMap, fmt.Sprint(session.GetSession()))
-		manager.mutex.Unlock()
 	}
+	delete(manager.sessionMap, session.GetConnection())
+	return true
 }
 
 // GetSessionCount returns the session count of the manager.
 func (manager *SessionManager) GetSessionCount() int {
+	manager.mutex.RLock()
+	defer manager.mutex.RUnlock()
 	return len(manager.nameMap)
 }
 
@@ -71,19 +92,19 @@ func (manager *SessionManager) GetSession(name string) (*MinecraftSession, bool)
 	return session, ok
 }
 
-// HasSessionWithRakNetSession checks if the session manager has a session with the given RakNet session.
-func (manager *SessionManager) HasSessionWithRakNetSession(rakNetSession *server.Session) bool {
+// HasSessionWithConnection checks if the session manager has a session with the given transport connection.
+func (manager *SessionManager) HasSessionWithConnection(connection Connection) bool {
 	manager.mutex.RLock()
-	var _, ok = manager.sessionMap[fmt.Sprint(rakNetSession)]
+	var _, ok = manager.sessionMap[connection]
 	manager.mutex.RUnlock()
 	return ok
 }
 
-// GetSessionByRakNetSession attempts to retrieve a session by its RakNet session.
+// GetSessionByConnection attempts to retrieve a session by its transport connection.
 // A bool is returned indicating success.
-func (manager *SessionManager) GetSessionByRakNetSession(rakNetSession *server.Session) (*MinecraftSession, bool) {
+func (manager *SessionManager) GetSessionByConnection(connection Connection) (*MinecraftSession, bool) {
 	manager.mutex.RLock()
-	var session, ok = manager.sessionMap[fmt.Sprint(rakNetSession)]
+	var session, ok = manager.sessionMap[connection]
 	manager.mutex.RUnlock()
 	return session, ok
 }
@@ -3,78 +3,133 @@ package net
 import (
 	"bytes"
 	"compress/zlib"
-	"crypto/cipher"
 	"encoding/hex"
 	"errors"
+	"io"
 	"io/ioutil"
 
 	"github.com/irmine/binutils"
+	"github.com/irmine/gomine/net/capture"
+	"github.com/irmine/gomine/net/info"
 	"github.com/irmine/gomine/net/packets"
 	"github.com/irmine/gomine/text"
+	"github.com/irmine/gomine/utils"
 )
 
 const McpeFlag = 0xFE
 
+// DefaultMaximumDecompressedSize is the default maximum size of a batch once decompressed.
+const DefaultMaximumDecompressedSize = 1 << 23
+
+// EmptyBatch gets returned when decoding a batch without any data.
+var EmptyBatch = errors.New("empty batch")
+
+// BatchTooLarge gets returned when decoding a batch that exceeds the maximum size once decompressed.
+var BatchTooLarge = errors.New("batch exceeds maximum decompressed size")
+
+// BatchEndpoint is an endpoint batches get encoded for and decoded from.
+// Minecraft sessions are the endpoints on the server side,
+// while clients implement BatchEndpoint to exchange batches with a server.
+type BatchEndpoint interface {
+	// UsesEncryption checks if batches of the endpoint are encrypted.
+	UsesEncryption() bool
+	// GetEncryptionHandler returns the encryption handler used to encrypt and decrypt batches.
+	GetEncryptionHandler() *utils.EncryptionHandler
+	// IsPacketRegistered checks if a packet with the given ID can be decoded by the endpoint.
+	IsPacketRegistered(packetId int) bool
+	// GetPacket returns a new packet with the given ID.
+	GetPacket(packetId int) packets.IPacket
+}
+
 type MinecraftPacketBatch struct {
 	*binutils.Stream
 	raw             []byte
 	packets         []packets.IPacket
+	endpoint        BatchEndpoint
 	session         *MinecraftSession
 	needsEncryption bool
+	encoded         bool
+
+	maximumDecompressedSize int
 }
 
 // NewMinecraftPacketBatch returns a new Minecraft Packet Batch used to decode/encode batches from Encapsulated Packets.
 func NewMinecraftPacketBatch(session *MinecraftSession) *MinecraftPacketBatch {
+	if session == nil {
+		return NewEndpointBatch(nil)
+	}
+	return NewEndpointBatch(session)
+}
+
+// NewEndpointBatch returns a new Minecraft Packet Batch used to decode/encode batches of the given endpoint.
+func NewEndpointBatch(endpoint BatchEndpoint) *MinecraftPacketBatch {
 	var batch = &MinecraftPacketBatch{}
 	batch.Stream = binutils.NewStream()
-	batch.session = session
+	batch.endpoint = endpoint
+	batch.maximumDecompressedSize = DefaultMaximumDecompressedSize
+	batch.session, _ = endpoint.(*MinecraftSession)
 
-	if session == nil {
+	if endpoint == nil {
 		batch.needsEncryption = false
 	} else {
-		batch.needsEncryption = session.UsesEncryption()
+		batch.needsEncryption = endpoint.UsesEncryption()
 	}
 
 	return batch
 }
 
 // Decode decodes the batch and separates packets. This does not decode the packets.
-func (batch *MinecraftPacketBatch) Decode() {
-	defer func() {
-		if err := recover(); err != nil {
-			text.DefaultLogger.Debug(err)
-		}
-	}()
-
+// An error is returned if the batch could not be decrypted or decompressed,
+// or if the packets in it could not be separated.
+func (batch *MinecraftPacketBatch) Decode() error {
+	if batch.Offset >= len(batch.Buffer) {
+		return EmptyBatch
+	}
 	var mcpeFlag = batch.GetByte()
 	if mcpeFlag != McpeFlag {
-		return
+		return nil
 	}
 	batch.raw = batch.Buffer[batch.Offset:]
 
 	if batch.needsEncryption {
-		batch.decrypt()
+		if err := batch.decrypt(); err != nil {
+			return err
+		}
 	}
-	var err = batch.decompress()
-	if err != nil {
-		text.DefaultLogger.LogError(err)
-		return
+	if err := batch.decompress(); err != nil {
+		return err
 	}
 
-	batch.ResetStream()
-	batch.SetBuffer(batch.raw)
+	var stream = packets.NewMinecraftStream()
+	stream.SetBuffer(batch.raw)
 
 	var packetData [][]byte
 
-	for !batch.Feof() {
-		packetData = append(packetData, batch.GetLengthPrefixedBytes())
+	for stream.Remaining() > 0 {
+		packetData = append(packetData, stream.GetLengthPrefixedBytes())
+		if err := stream.Error(); err != nil {
+			return err
+		}
 	}
 
 	batch.fetchPackets(packetData)
+	return nil
+}
+
+// SetMaximumDecompressedSize sets the maximum size of the batch once decompressed.
+// Decoding a batch exceeding it returns BatchTooLarge, which guards against zlib bombs.
+func (batch *MinecraftPacketBatch) SetMaximumDecompressedSize(size int) {
+	batch.maximumDecompressedSize = size
 }
 
 // Encode encodes all packets in the batch and zlib encodes them.
+// A batch is only encoded once, as encrypting it again would advance the key stream of the endpoint.
+// Encoding a batch that was already encoded does nothing.
 func (batch *MinecraftPacketBatch) Encode() {
+	if batch.encoded {
+		return
+	}
+	batch.encoded = true
 	batch.ResetStream()
 	batch.PutByte(McpeFlag)
 
@@ -91,6 +146,8 @@ func (batch *MinecraftPacketBatch) Encode() {
 }
 
 // fetchPackets fetches all packets from the raw packet buffers.
+// Login packets bind Minecraft sessions to the protocol they were sent with,
+// so that the login packet and all packets after it use the right packet manager.
 func (batch *MinecraftPacketBatch) fetchPackets(packetData [][]byte) {
 	for _, data := range packetData {
 		if len(data) == 0 {
@@ -98,11 +155,17 @@ func (batch *MinecraftPacketBatch) fetchPackets(packetData [][]byte) {
 		}
 		packetId := int(data[0])
 
-		if !batch.session.adapter.packetManager.IsPacketRegistered(packetId) {
-			text.DefaultLogger.Debug("Unknown Minecraft packet with ID:", packetId)
+		if batch.session != nil && packetId == info.PacketIds[info.LoginPacket] {
+			if manager, ok := batch.session.adapter.protocols.GetProtocol(batch.peekProtocol(data)); ok {
+				batch.session.SetPacketManager(manager)
+			}
+		}
+
+		if !batch.endpoint.IsPacketRegistered(packetId) {
+			batch.getLogger().Debug("Unknown Minecraft packet with ID:", packetId)
 			continue
 		}
-		packet := batch.session.adapter.packetManager.GetPacket(packetId)
+		packet := batch.endpoint.GetPacket(packetId)
 
 		packet.SetBuffer(data)
 		batch.packets = append(batch.packets, packet)
@@ -111,7 +174,7 @@ func (batch *MinecraftPacketBatch) fetchPackets(packetData [][]byte) {
 
 // peekProtocol peeks in the packet's payload, looking for the bedrock.
 func (batch *MinecraftPacketBatch) peekProtocol(packetData []byte) int32 {
-	if packetData[0] != 0x01 {
+	if len(packetData) < 7 || packetData[0] != 0x01 {
 		return 0
 	}
 	var protocolBytes = packetData[1:5]
@@ -125,36 +188,40 @@ func (batch *MinecraftPacketBatch) peekProtocol(packetData []byte) int32 {
 	return protocol
 }
 
-// encrypt encrypts the data passed to the function.
+// encrypt appends the checksum to the data passed to the function and encrypts it.
 func (batch *MinecraftPacketBatch) encrypt(d []byte) []byte {
-	var data = batch.session.GetEncryptionHandler().Data
-	d = append(d, batch.session.GetEncryptionHandler().ComputeSendChecksum(d)...)
-
-	for i := range d {
-		var cfb = cipher.NewCFBEncrypter(data.EncryptCipher, data.EncryptIV)
-		cfb.XORKeyStream(d[i:i+1], d[i:i+1])
-		data.EncryptIV = append(data.EncryptIV[1:], d[i])
-	}
+	var handler = batch.endpoint.GetEncryptionHandler()
+	d = append(d, handler.ComputeSendChecksum(d)...)
+	handler.Data.EncryptStream.XORKeyStream(d, d)
 
 	return d
 }
 
-// decrypt decrypts the buffer of the packet.
-func (batch *MinecraftPacketBatch) decrypt() {
-	var data = batch.session.GetEncryptionHandler().Data
-	for i, b := range batch.raw {
-		var cfb = cipher.NewCFBDecrypter(data.DecryptCipher, data.DecryptIV)
-		cfb.XORKeyStream(batch.raw[i:i+1], batch.raw[i:i+1])
-		data.DecryptIV = append(data.DecryptIV[1:], b)
+// decrypt decrypts the buffer of the packet and verifies its checksum.
+// The checksum gets stripped from the buffer once verified.
+func (batch *MinecraftPacketBatch) decrypt() error {
+	var handler = batch.endpoint.GetEncryptionHandler()
+	handler.Data.DecryptStream.XORKeyStream(batch.raw, batch.raw)
+
+	var payload, err = handler.VerifyReceiveChecksum(batch.raw)
+	if err != nil {
+		return err
 	}
+	batch.raw = payload
+	return nil
 }
 
 // putPackets puts all packets of the batch inside of the stream.
+// Packets sent to Minecraft sessions get recorded once encoded.
 func (batch *MinecraftPacketBatch) putPackets(stream *binutils.Stream) {
 	for _, packet := range batch.GetPackets() {
 		packet.EncodeHeader()
 		packet.Encode()
 		stream.PutLengthPrefixedBytes(packet.GetBuffer())
+
+		if batch.session != nil {
+			batch.session.record(capture.Outbound, packet.GetBuffer())
+		}
 	}
 }
 
@@ -172,10 +239,8 @@ func (batch *MinecraftPacketBatch) compress(stream *binutils.Stream) []byte {
 func (batch *MinecraftPacketBatch) decompress() error {
 	var reader = bytes.NewReader(batch.raw)
 	zlibReader, err := zlib.NewReader(reader)
-	text.DefaultLogger.LogError(err)
-
 	if err != nil {
-		text.DefaultLogger.Debug(hex.EncodeToString(batch.raw))
+		batch.getLogger().Debug(hex.EncodeToString(batch.raw))
 		return err
 	}
 	if zlibReader == nil {
@@ -183,11 +248,22 @@ func (batch *MinecraftPacketBatch) decompress() error {
 	}
 	zlibReader.Close()
 
-	batch.raw, err = ioutil.ReadAll(zlibReader)
-
+	batch.raw, err = ioutil.ReadAll(io.LimitReader(zlibReader, int64(batch.maximumDecompressedSize)+1))
+	if err == nil && len(batch.raw) > batch.maximumDecompressedSize {
+		return BatchTooLarge
+	}
 	return err
 }
 
+// getLogger returns the logger of the network adapter of the session of the batch.
+// The default logger is returned for batches without a session.
+func (batch *MinecraftPacketBatch) getLogger() *text.Logger {
+	if batch.session == nil || batch.session.adapter == nil {
+		return text.DefaultLogger
+	}
+	return batch.session.adapter.logger
+}
+
 // AddPacket adds a packet to the batch when encoding.
 func (batch *MinecraftPacketBatch) AddPacket(packet packets.IPacket) {
 	batch.packets = append(batch.packets, packet)
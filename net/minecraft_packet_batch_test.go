@@ -0,0 +1,134 @@
+package net
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/irmine/gomine/net/info"
+	"github.com/irmine/gomine/net/packets"
+	"github.com/irmine/gomine/net/packets/bedrock"
+	"github.com/irmine/gomine/utils"
+)
+
+// testEndpoint is an unencrypted batch endpoint able to decode every bedrock packet.
+type testEndpoint struct{}
+
+func (testEndpoint) UsesEncryption() bool {
+	return false
+}
+
+func (testEndpoint) GetEncryptionHandler() *utils.EncryptionHandler {
+	return nil
+}
+
+func (testEndpoint) IsPacketRegistered(packetId int) bool {
+	var name, ok = info.PacketIds.GetPacketName(packetId)
+	if ok {
+		_, ok = bedrock.Packets[name]
+	}
+	return ok
+}
+
+func (testEndpoint) GetPacket(packetId int) packets.IPacket {
+	var name, _ = info.PacketIds.GetPacketName(packetId)
+	return bedrock.Packets[name]()
+}
+
+func FuzzBatchDecode(f *testing.F) {
+	var text = bedrock.NewTextPacket()
+	text.Message = "Hello"
+	var chunkRadius = bedrock.NewRequestChunkRadiusPacket()
+	chunkRadius.Radius = 8
+
+	var batch = NewEndpointBatch(testEndpoint{})
+	batch.AddPacket(text)
+	batch.AddPacket(chunkRadius)
+	batch.Encode()
+
+	f.Add(batch.Buffer)
+	f.Add([]byte{})
+	f.Add([]byte{McpeFlag})
+	f.Add([]byte{McpeFlag, 0x78, 0x9c, 0x03, 0x00, 0x00, 0x00, 0x00, 0x01})
+
+	f.Fuzz(func(t *testing.T, buffer []byte) {
+		var batch = NewEndpointBatch(testEndpoint{})
+		batch.Buffer = buffer
+		if err := batch.Decode(); err != nil {
+			return
+		}
+		for _, packet := range batch.GetPackets() {
+			if err := packet.DecodeHeader(); err != nil {
+				continue
+			}
+			packet.Decode()
+		}
+	})
+}
+
+func TestBatchRoundTrip(t *testing.T) {
+	var text = bedrock.NewTextPacket()
+	text.Message = "Hello"
+
+	var batch = NewEndpointBatch(testEndpoint{})
+	batch.AddPacket(text)
+	batch.Encode()
+
+	var decoded = NewEndpointBatch(testEndpoint{})
+	decoded.Buffer = batch.Buffer
+	if err := decoded.Decode(); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.GetPackets()) != 1 {
+		t.Fatalf("decoded %v packets, expected 1", len(decoded.GetPackets()))
+	}
+	var packet = decoded.GetPackets()[0].(*bedrock.TextPacket)
+	if err := packet.DecodeHeader(); err != nil {
+		t.Fatal(err)
+	}
+	if err := packet.Decode(); err != nil {
+		t.Fatal(err)
+	}
+	if packet.Message != "Hello" {
+		t.Errorf("decoded message %q, expected Hello", packet.Message)
+	}
+
+	var truncated = NewEndpointBatch(testEndpoint{})
+	truncated.Buffer = batch.Buffer[:len(batch.Buffer)-2]
+	if err := truncated.Decode(); err == nil {
+		t.Error("decoding a truncated batch succeeded")
+	}
+	if err := NewEndpointBatch(testEndpoint{}).Decode(); err != EmptyBatch {
+		t.Errorf("expected EmptyBatch decoding an empty batch, got %v", err)
+	}
+}
+
+func TestBatchDecompressedSizeLimit(t *testing.T) {
+	var text = bedrock.NewTextPacket()
+	text.Message = strings.Repeat("a", 4096)
+
+	var batch = NewEndpointBatch(testEndpoint{})
+	batch.AddPacket(text)
+	batch.Encode()
+
+	var decoded = NewEndpointBatch(testEndpoint{})
+	decoded.Buffer = batch.Buffer
+	decoded.SetMaximumDecompressedSize(1024)
+	if err := decoded.Decode(); err != BatchTooLarge {
+		t.Fatalf("expected BatchTooLarge, got %v", err)
+	}
+}
+
+func TestBatchEncodeOnce(t *testing.T) {
+	var text = bedrock.NewTextPacket()
+	text.Message = "Hello"
+
+	var batch = NewEndpointBatch(testEndpoint{})
+	batch.AddPacket(text)
+	batch.Encode()
+	var encoded = append([]byte{}, batch.Buffer...)
+	batch.Encode()
+	if !bytes.Equal(batch.Buffer, encoded) {
+		t.Fatal("encoding a batch twice changed its buffer")
+	}
+}
@@ -3,24 +3,31 @@ package net
 import (
 	"fmt"
 	"github.com/google/uuid"
+	"github.com/irmine/gomine/net/capture"
 	"github.com/irmine/gomine/net/packets"
 	"github.com/irmine/gomine/net/packets/types"
+	protocol2 "github.com/irmine/gomine/net/protocol"
 	"github.com/irmine/gomine/permissions"
 	"github.com/irmine/gomine/players"
-	"github.com/irmine/gomine/text"
+	"github.com/irmine/gomine/timings"
 	"github.com/irmine/gomine/utils"
-	"github.com/irmine/goraklib/protocol"
-	"github.com/irmine/goraklib/server"
 	"github.com/irmine/worlds"
 	"github.com/irmine/worlds/blocks"
 	"github.com/irmine/worlds/chunks"
 	"math"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 type MinecraftSession struct {
-	adapter *NetworkAdapter
-	session *server.Session
+	adapter    *NetworkAdapter
+	connection Connection
+
+	// packetManagerMutex guards the packet manager, which gets swapped once the login packet has been received.
+	packetManagerMutex sync.RWMutex
+	packetManager      protocol2.IPacketManager
 
 	player *players.Player
 
@@ -45,12 +52,27 @@ type MinecraftSession struct {
 	permissions     map[string]*permissions.Permission
 	permissionGroup *permissions.Group
 
+	queueMutex sync.Mutex
+	queue      []packets.IPacket
+	// sendMutex is held while batches are encoded and sent,
+	// as encryption requires batches to be encoded in the order they are sent.
+	sendMutex sync.Mutex
+
+	decodeFailures int32
+	buckets        map[int]*TokenBucket
+
 	Connected         bool
 }
 
-// NewMinecraftSession returns a new Minecraft session with the given RakNet session.
-func NewMinecraftSession(adapter *NetworkAdapter, session *server.Session) *MinecraftSession {
-	return &MinecraftSession{adapter, session, nil, uuid.New(), "", 0, 0, "", "", 0, utils.NewEncryptionHandler(), false, false, 0, nil, nil, nil, false}
+// NewMinecraftSession returns a new Minecraft session with the given transport connection.
+func NewMinecraftSession(adapter *NetworkAdapter, connection Connection) *MinecraftSession {
+	return &MinecraftSession{
+		adapter:           adapter,
+		connection:        connection,
+		packetManager:     adapter.protocols.GetLatestProtocol(),
+		uuid:              uuid.New(),
+		encryptionHandler: utils.NewEncryptionHandler(),
+	}
 }
 
 // SetData sets the basic session data of the Minecraft Session
@@ -140,19 +162,45 @@ func (session *MinecraftSession) GetProtocolNumber() int32 {
 	return session.protocolNumber
 }
 
+// GetPacketManager returns the packet manager of the protocol the session is bound to.
+// Sessions use the latest protocol until their login packet has been received.
+func (session *MinecraftSession) GetPacketManager() protocol2.IPacketManager {
+	session.packetManagerMutex.RLock()
+	defer session.packetManagerMutex.RUnlock()
+	return session.packetManager
+}
+
+// SetPacketManager binds the session to the protocol of the given packet manager.
+// All packets sent and received by the session will use this packet manager.
+func (session *MinecraftSession) SetPacketManager(manager protocol2.IPacketManager) {
+	session.packetManagerMutex.Lock()
+	session.packetManager = manager
+	session.packetManagerMutex.Unlock()
+}
+
+// IsPacketRegistered checks if the packet manager of the session has a packet with the given ID registered.
+func (session *MinecraftSession) IsPacketRegistered(packetId int) bool {
+	return session.GetPacketManager().IsPacketRegistered(packetId)
+}
+
+// GetPacket returns a new packet with the given ID from the packet manager of the session.
+func (session *MinecraftSession) GetPacket(packetId int) packets.IPacket {
+	return session.GetPacketManager().GetPacket(packetId)
+}
+
 // GetGameVersion returns the Minecraft version the player used to join the server.
 func (session *MinecraftSession) GetGameVersion() string {
 	return session.minecraftVersion
 }
 
-// GetSession returns the GoRakLib session of this session.
-func (session *MinecraftSession) GetSession() *server.Session {
-	return session.session
+// GetConnection returns the transport connection of this session.
+func (session *MinecraftSession) GetConnection() Connection {
+	return session.connection
 }
 
 // GetPing returns the ping of the session in milliseconds.
 func (session *MinecraftSession) GetPing() int64 {
-	return session.session.CurrentPing
+	return session.connection.GetPing()
 }
 
 // GetUUID returns the UUID of this session.
@@ -257,28 +305,138 @@ func (session *MinecraftSession) SendSkin(target *MinecraftSession) {
 	target.SendPlayerSkin(player.GetUUID(), player.GetSkinId(), player.GetGeometryName(), player.GetGeometryData(), player.GetSkinData(), player.GetCapeData())
 }
 
-// SendPacket sends a packet to this session.
+// SendPacket queues a packet to be sent to this session.
+// Queued packets are sent in a single batch once the session gets flushed,
+// which happens at the end of every server tick.
+// Interceptors registered for the packet run before the packet gets queued,
+// and the packet does not get sent if an interceptor discarded it.
 func (session *MinecraftSession) SendPacket(packet packets.IPacket) {
-	if session.session == nil {
+	if session.connection == nil {
+		return
+	}
+	if !session.intercept(packet) {
+		return
+	}
+	session.queueMutex.Lock()
+	session.queue = append(session.queue, packet)
+	session.queueMutex.Unlock()
+}
+
+// SendPacketImmediately sends a packet to this session without waiting for the end of the tick.
+// All packets queued before are sent along in the same batch to preserve the packet order.
+// This should only be used for latency sensitive packets, or packets sent right before
+// the state of the session changes, such as the server handshake or disconnect.
+func (session *MinecraftSession) SendPacketImmediately(packet packets.IPacket) {
+	session.SendPacket(packet)
+	session.Flush()
+}
+
+// Flush sends all queued packets of the session in a single batch.
+// Flush does nothing if no packets were queued.
+// The send lock is held from taking the queue until the batch was sent,
+// so that batches flushed from multiple goroutines are encrypted in the order they are sent.
+func (session *MinecraftSession) Flush() {
+	session.sendMutex.Lock()
+	defer session.sendMutex.Unlock()
+
+	session.queueMutex.Lock()
+	if len(session.queue) == 0 {
+		session.queueMutex.Unlock()
 		return
 	}
 	var b = NewMinecraftPacketBatch(session)
-	b.AddPacket(packet)
+	for _, packet := range session.queue {
+		b.AddPacket(packet)
+	}
+	session.queue = nil
+	session.queueMutex.Unlock()
 
-	session.SendBatch(b)
+	session.sendBatch(b)
 }
 
 // SendBatch sends a batch to this session.
+// The batch gets encoded and sent while holding the send lock of the session.
 func (session *MinecraftSession) SendBatch(batch *MinecraftPacketBatch) {
-	if session.session == nil {
+	session.sendMutex.Lock()
+	session.sendBatch(batch)
+	session.sendMutex.Unlock()
+}
+
+// sendBatch encodes and sends a batch to this session. The send lock must be held.
+// The batch is encoded before it is passed to the connection,
+// so that the order of encryption does not depend on the transport.
+func (session *MinecraftSession) sendBatch(batch *MinecraftPacketBatch) {
+	if session.connection == nil {
 		return
 	}
-	session.session.SendPacket(batch, protocol.ReliabilityReliable, server.PriorityMedium)
+	batch.Encode()
+	session.connection.SendBatch(batch)
+}
+
+// record records a packet sent or received by this session,
+// if the network adapter of the session is capturing packets.
+func (session *MinecraftSession) record(direction capture.Direction, data []byte) {
+	if recorder := session.adapter.recorder; recorder != nil && session.connection != nil {
+		if err := recorder.Record(session.connection.GetAddress().String(), direction, data); err != nil {
+			session.adapter.logger.LogError(err)
+		}
+	}
+}
+
+// GetDecodeFailures returns the amount of batches and packets of the session that failed to decode.
+func (session *MinecraftSession) GetDecodeFailures() int {
+	return int(atomic.LoadInt32(&session.decodeFailures))
+}
+
+// addDecodeFailure counts a batch or packet of the session that failed to decode,
+// and returns the amount of decode failures of the session so far.
+func (session *MinecraftSession) addDecodeFailure() int {
+	return int(atomic.AddInt32(&session.decodeFailures, 1))
+}
+
+// takeToken takes a token from the bucket of the packet type with the given ID.
+// The bucket gets created with the given rate limit if the session has none yet.
+// Returns false if the bucket was empty.
+func (session *MinecraftSession) takeToken(packetId int, limit RateLimit) bool {
+	if session.buckets == nil {
+		session.buckets = make(map[int]*TokenBucket)
+	}
+	var bucket, ok = session.buckets[packetId]
+	if !ok {
+		bucket = NewTokenBucket(limit.Rate, limit.Burst)
+		session.buckets[packetId] = bucket
+	}
+	return bucket.Take(time.Now())
+}
+
+// logName returns the name of the player of the session for logging,
+// or the address of the session if it has no player yet.
+func (session *MinecraftSession) logName() string {
+	if name := session.GetName(); name != "" {
+		return name
+	}
+	return session.connection.GetAddress().String()
+}
+
+// intercept runs all interceptors registered for the outbound packet, ordered by their priority.
+// Returns false if the packet got discarded, and should not be sent.
+func (session *MinecraftSession) intercept(packet packets.IPacket) bool {
+	for _, h := range session.GetPacketManager().GetInterceptorsById(packet.GetId()) {
+		for _, iInterceptor := range h {
+			if interceptor, ok := iInterceptor.(*PacketInterceptor); ok {
+				if packet.IsDiscarded() {
+					return false
+				}
+				interceptor.function(packet, session)
+			}
+		}
+	}
+	return !packet.IsDiscarded()
 }
 
 // HandlePacket handles packets of this session.
 func (session *MinecraftSession) HandlePacket(packet packets.IPacket) {
-	priorityHandlers := session.adapter.packetManager.GetHandlersById(packet.GetId())
+	priorityHandlers := session.GetPacketManager().GetHandlersById(packet.GetId())
 
 	var handled = false
 handling:
@@ -289,7 +447,11 @@ handling:
 					break handling
 				}
 
+				var start = session.adapter.timings.Start()
 				ret := handler.function(packet, session)
+				if !start.IsZero() {
+					session.adapter.timings.Stop(timings.PacketHandler, getHandlerTimingName(packet, handler), start)
+				}
 				if !handled {
 					handled = ret
 				}
@@ -297,10 +459,21 @@ handling:
 		}
 	}
 	if !handled {
-		text.DefaultLogger.Debug("Unhandled Minecraft packet with ID:", packet.GetId())
+		session.adapter.logger.Debug("Unhandled Minecraft packet with ID:", packet.GetId())
 	}
 }
 
+// getHandlerTimingName returns the name the time spent handling the packet is recorded under in timings.
+func getHandlerTimingName(packet packets.IPacket, handler *PacketHandler) string {
+	var owner = handler.GetOwner()
+	if owner == "" {
+		owner = "GoMine"
+	}
+	var name = fmt.Sprintf("%T", packet)
+	name = name[strings.LastIndex(name, ".")+1:]
+	return fmt.Sprintf("%v (0x%02x) by %v", name, packet.GetId(), owner)
+}
+
 func (session *MinecraftSession) Close(reason string, hideDisconnectionScreen bool) {
 	if session.Connected {
 		loadedChunks := session.GetChunkLoader().GetLoadedChunks()
@@ -318,16 +491,19 @@ func (session *MinecraftSession) Close(reason string, hideDisconnectionScreen bo
 		session.player.Close()
 	}
 	session.SendDisconnect(reason, hideDisconnectionScreen)
+	if session.connection != nil {
+		session.connection.Close()
+	}
 }
 
 func (session *MinecraftSession) Kick(reason string, hideDisconnectionScreen bool, isAdmin bool) {
 	if isAdmin {
 		reason = "Kicked By Admin. Reason: " + reason
 		session.Close(reason, hideDisconnectionScreen)
-		text.DefaultLogger.Info(session.GetDisplayName() + " Disconnected.", reason)
+		session.adapter.logger.Info(session.GetDisplayName() + " Disconnected.", reason)
 	}else{
 		session.Close(reason, hideDisconnectionScreen)
-		text.DefaultLogger.Info(session.GetDisplayName() + " Disconnected.", reason)
+		session.adapter.logger.Info(session.GetDisplayName() + " Disconnected.", reason)
 	}
 }
 
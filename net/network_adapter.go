@@ -1,81 +1,381 @@
 package net
 
 import (
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/irmine/gomine/net/capture"
+	"github.com/irmine/gomine/net/info"
 	"github.com/irmine/gomine/net/packets"
 	protocol2 "github.com/irmine/gomine/net/protocol"
 	"github.com/irmine/gomine/text"
-	"github.com/irmine/goraklib/protocol"
-	"github.com/irmine/goraklib/server"
-	"net"
+	"github.com/irmine/gomine/timings"
+	"github.com/irmine/gomine/utils"
 )
 
+// DefaultDecodeFailureThreshold is the default amount of batches and packets
+// a session may send that fail to decode, before the session gets disconnected.
+const DefaultDecodeFailureThreshold = 10
+
+// DefaultMaximumBatchSize is the default maximum size of a batch received, before decompression.
+const DefaultMaximumBatchSize = 1 << 21
+
+// DefaultTemporaryBanDuration is the default duration IPs get banned for when exceeding a rate limit with the ban action.
+const DefaultTemporaryBanDuration = time.Minute * 5
+
 type NetworkAdapter struct {
-	rakLibManager   *server.Manager
-	packetManager   protocol2.IPacketManager
-	sessionManager  *SessionManager
+	transport      Transport
+	protocols      *protocol2.Registry
+	sessionManager *SessionManager
+	recorder       *capture.Recorder
+	logger         *text.Logger
+	timings        *timings.Timings
+
+	decodeFailureThreshold  int
+	maximumBatchSize        int
+	maximumDecompressedSize int
+
+	limitMutex           sync.RWMutex
+	rateLimits           map[int]RateLimit
+	temporaryBanDuration time.Duration
+	temporaryBans        map[string]time.Time
+	connectionFilter     func(ip net.IP) (string, bool)
+
+	pendingMutex    sync.Mutex
+	pendingSessions map[Connection]*MinecraftSession
 }
 
-// NewNetworkAdapter returns a new Network adapter to adapt to the RakNet server.
-func NewNetworkAdapter(packetManager protocol2.IPacketManager, sessionManager *SessionManager) *NetworkAdapter {
-	var manager = server.NewManager()
-	var adapter = &NetworkAdapter{manager, packetManager, sessionManager}
+// NewNetworkAdapter returns a new Network adapter to adapt to the given transport.
+// Sessions get bound to the protocol of the registry matching their login protocol.
+func NewNetworkAdapter(transport Transport, protocols *protocol2.Registry, sessionManager *SessionManager) *NetworkAdapter {
+	var adapter = &NetworkAdapter{
+		transport:               transport,
+		protocols:               protocols,
+		sessionManager:          sessionManager,
+		logger:                  text.DefaultLogger,
+		decodeFailureThreshold:  DefaultDecodeFailureThreshold,
+		maximumBatchSize:        DefaultMaximumBatchSize,
+		maximumDecompressedSize: DefaultMaximumDecompressedSize,
+		rateLimits:              make(map[int]RateLimit),
+		temporaryBanDuration:    DefaultTemporaryBanDuration,
+		temporaryBans:           make(map[string]time.Time),
+		pendingSessions:         make(map[Connection]*MinecraftSession),
+	}
 
-	manager.PacketFunction = func(packet []byte, session *server.Session) {
-		var minecraftSession *MinecraftSession
-		var ok bool
-		if minecraftSession, ok = adapter.sessionManager.GetSessionByRakNetSession(session); !ok {
-			minecraftSession = NewMinecraftSession(adapter, session)
+	transport.SetPacketFunction(func(packet []byte, connection Connection) {
+		adapter.HandlePacket(adapter.getSession(connection), packet)
+	})
+	transport.SetConnectFunction(func(connection Connection) {
+		if _, denied := adapter.filterConnection(connection.GetAddress().IP); denied {
+			adapter.logger.Debug(connection.GetAddress(), "connected, but is denied access.")
+			return
 		}
-		adapter.HandlePacket(minecraftSession, packet)
+		adapter.logger.Debug(connection.GetAddress(), "connected!")
+	})
+	return adapter
+}
+
+// GetTransport returns the transport the network adapter receives batches from.
+func (adapter *NetworkAdapter) GetTransport() Transport {
+	return adapter.transport
+}
+
+// GetLogger returns the logger the network adapter and its sessions log to.
+func (adapter *NetworkAdapter) GetLogger() *text.Logger {
+	return adapter.logger
+}
+
+// SetLogger sets the logger the network adapter and its sessions log to.
+// The default logger is used if not set.
+func (adapter *NetworkAdapter) SetLogger(logger *text.Logger) {
+	adapter.logger = logger
+}
+
+// SetTimings sets the timings the packet handlers of sessions are measured with.
+func (adapter *NetworkAdapter) SetTimings(timings *timings.Timings) {
+	adapter.timings = timings
+}
+
+// GetRecorder returns the packet recorder of the network adapter.
+// Nil is returned if packets are not being captured.
+func (adapter *NetworkAdapter) GetRecorder() *capture.Recorder {
+	return adapter.recorder
+}
+
+// SetRecorder sets the packet recorder of the network adapter.
+// All packets sent and received by sessions get recorded once set.
+// Setting a nil recorder stops capturing packets.
+func (adapter *NetworkAdapter) SetRecorder(recorder *capture.Recorder) {
+	adapter.recorder = recorder
+}
+
+// GetDecodeFailureThreshold returns the amount of batches and packets a session
+// may send that fail to decode, before the session gets disconnected.
+func (adapter *NetworkAdapter) GetDecodeFailureThreshold() int {
+	return adapter.decodeFailureThreshold
+}
+
+// SetDecodeFailureThreshold sets the amount of batches and packets a session
+// may send that fail to decode, before the session gets disconnected.
+// A threshold of 0 or lower resets the threshold to DefaultDecodeFailureThreshold.
+func (adapter *NetworkAdapter) SetDecodeFailureThreshold(threshold int) {
+	if threshold <= 0 {
+		threshold = DefaultDecodeFailureThreshold
 	}
-	manager.DisconnectFunction = func(session *server.Session) {
-		text.DefaultLogger.Debug(session, "disconnected!")
+	adapter.decodeFailureThreshold = threshold
+}
 
+// GetBatchLimits returns the maximum size of batches received,
+// and the maximum size of batches once decompressed.
+func (adapter *NetworkAdapter) GetBatchLimits() (maximumSize int, maximumDecompressedSize int) {
+	return adapter.maximumBatchSize, adapter.maximumDecompressedSize
+}
+
+// SetBatchLimits sets the maximum size of batches received, and the maximum size of batches once decompressed.
+// Sessions sending larger batches get disconnected.
+// Limits of 0 or lower reset the limits to DefaultMaximumBatchSize and DefaultMaximumDecompressedSize.
+func (adapter *NetworkAdapter) SetBatchLimits(maximumSize int, maximumDecompressedSize int) {
+	if maximumSize <= 0 {
+		maximumSize = DefaultMaximumBatchSize
 	}
-	manager.ConnectFunction = func(session *server.Session) {
-		text.DefaultLogger.Debug(session, "connected!")
+	if maximumDecompressedSize <= 0 {
+		maximumDecompressedSize = DefaultMaximumDecompressedSize
 	}
-	return adapter
+	adapter.maximumBatchSize, adapter.maximumDecompressedSize = maximumSize, maximumDecompressedSize
+}
+
+// GetRateLimit returns the rate limit of packets with the given ID.
+// A bool is returned indicating if packets with the ID are rate limited.
+func (adapter *NetworkAdapter) GetRateLimit(packetId int) (RateLimit, bool) {
+	adapter.limitMutex.RLock()
+	defer adapter.limitMutex.RUnlock()
+	var limit, ok = adapter.rateLimits[packetId]
+	return limit, ok
+}
+
+// SetRateLimits sets the rate limits of packets, keyed by the name of the packet.
+// All previously set rate limits get replaced.
+// An error is returned, and no rate limits get changed, if a packet is unknown or a rate limit is invalid.
+func (adapter *NetworkAdapter) SetRateLimits(limits map[info.PacketName]RateLimit) error {
+	var rateLimits, err = buildRateLimits(limits)
+	if err != nil {
+		return err
+	}
+	adapter.limitMutex.Lock()
+	adapter.rateLimits = rateLimits
+	adapter.limitMutex.Unlock()
+	return nil
+}
+
+// SetTemporaryBanDuration sets the duration IPs get banned for when exceeding a rate limit with the ban action.
+// A duration of 0 or lower resets the duration to DefaultTemporaryBanDuration.
+func (adapter *NetworkAdapter) SetTemporaryBanDuration(duration time.Duration) {
+	if duration <= 0 {
+		duration = DefaultTemporaryBanDuration
+	}
+	adapter.limitMutex.Lock()
+	adapter.temporaryBanDuration = duration
+	adapter.limitMutex.Unlock()
+}
+
+// TemporarilyBan bans the IP from the server for the given duration.
+// Sessions of the IP get disconnected as soon as they send a batch.
+func (adapter *NetworkAdapter) TemporarilyBan(ip net.IP, duration time.Duration) {
+	adapter.limitMutex.Lock()
+	adapter.temporaryBans[ip.String()] = time.Now().Add(duration)
+	adapter.limitMutex.Unlock()
 }
 
-// GetRakLibManager returns the GoRakLib manager of the network adapter.
-func (adapter *NetworkAdapter) GetRakLibManager() *server.Manager {
-	return adapter.rakLibManager
+// IsTemporarilyBanned checks if the IP is currently temporarily banned.
+func (adapter *NetworkAdapter) IsTemporarilyBanned(ip net.IP) bool {
+	adapter.limitMutex.Lock()
+	defer adapter.limitMutex.Unlock()
+
+	var until, ok = adapter.temporaryBans[ip.String()]
+	if ok && time.Now().After(until) {
+		delete(adapter.temporaryBans, ip.String())
+		return false
+	}
+	return ok
+}
+
+// SetConnectionFilter sets the function deciding if connections from an IP are denied access.
+// The filter returns the reason shown to the client and true if the IP is denied.
+// Sessions of denied IPs get disconnected as soon as they send a batch, before it gets decoded.
+func (adapter *NetworkAdapter) SetConnectionFilter(filter func(ip net.IP) (string, bool)) {
+	adapter.limitMutex.Lock()
+	adapter.connectionFilter = filter
+	adapter.limitMutex.Unlock()
+}
+
+// filterConnection checks if connections from the IP are denied access by the connection filter.
+func (adapter *NetworkAdapter) filterConnection(ip net.IP) (string, bool) {
+	adapter.limitMutex.RLock()
+	var filter = adapter.connectionFilter
+	adapter.limitMutex.RUnlock()
+	if filter == nil {
+		return "", false
+	}
+	return filter(ip)
+}
+
+// getSession returns the session of the connection.
+// Connections without a session in the session manager, such as connections that did not log in yet
+// or that are in the join queue, get a pending session that is kept until the connection disconnects,
+// so that rate limits and decode failures are counted over all batches of the connection.
+func (adapter *NetworkAdapter) getSession(connection Connection) *MinecraftSession {
+	if session, ok := adapter.sessionManager.GetSessionByConnection(connection); ok {
+		return session
+	}
+	adapter.pendingMutex.Lock()
+	defer adapter.pendingMutex.Unlock()
+
+	var session, ok = adapter.pendingSessions[connection]
+	if !ok {
+		session = NewMinecraftSession(adapter, connection)
+		adapter.pendingSessions[connection] = session
+	}
+	return session
+}
+
+// RemovePendingSession removes the pending session of the connection.
+// It should be called once the connection disconnected.
+func (adapter *NetworkAdapter) RemovePendingSession(connection Connection) {
+	adapter.pendingMutex.Lock()
+	delete(adapter.pendingSessions, connection)
+	adapter.pendingMutex.Unlock()
+}
+
+// GetProtocolRegistry returns the registry of all protocols supported by the network adapter.
+func (adapter *NetworkAdapter) GetProtocolRegistry() *protocol2.Registry {
+	return adapter.protocols
 }
 
 // HandlePackets handles all packets of the given session + player.
 func (adapter *NetworkAdapter) HandlePacket(session *MinecraftSession, buffer []byte) {
+	if adapter.IsTemporarilyBanned(session.GetConnection().GetAddress().IP) {
+		session.Kick("You are temporarily banned.", false, false)
+		return
+	}
+	if reason, denied := adapter.filterConnection(session.GetConnection().GetAddress().IP); denied {
+		session.Kick(reason, false, false)
+		return
+	}
+	if len(buffer) > adapter.maximumBatchSize {
+		adapter.logger.Notice(session.logName(), "sent a batch of", len(buffer), "bytes, disconnecting.")
+		session.Kick("Batch too large.", false, false)
+		return
+	}
+
 	batch := NewMinecraftPacketBatch(session)
 	batch.Buffer = buffer
-	batch.Decode()
+	batch.SetMaximumDecompressedSize(adapter.maximumDecompressedSize)
+	if err := batch.Decode(); err != nil {
+		if err == utils.InvalidChecksum {
+			adapter.logger.LogError(err)
+			session.Kick("Invalid batch checksum.", false, false)
+			return
+		}
+		if err == BatchTooLarge {
+			adapter.logger.Notice(session.logName(), "sent a batch exceeding", adapter.maximumDecompressedSize, "bytes decompressed, disconnecting.")
+			session.Kick("Batch too large.", false, false)
+			return
+		}
+		adapter.handleDecodeFailure(session, "batch", err)
+		return
+	}
 
 	for _, packet := range batch.GetPackets() {
-		if session.GetProtocolNumber() < 120 {
-			packet.DecodeId()
-		} else {
-			packet.DecodeHeader()
+		session.record(capture.Inbound, packet.GetBuffer())
+
+		if action, exceeded := adapter.checkRateLimit(session, packet); exceeded {
+			if action != RateLimitDrop {
+				return
+			}
+			continue
+		}
+
+		if err := adapter.decodePacket(session, packet); err != nil {
+			if !adapter.handleDecodeFailure(session, fmt.Sprintf("packet 0x%02x", packet.GetId()), err) {
+				return
+			}
+			continue
 		}
-		packet.Decode()
 
 		session.HandlePacket(packet)
 	}
 }
 
-// GetSession returns a GoRakLib session by an address and port.
-func (adapter *NetworkAdapter) GetSession(address string, port uint16) *server.Session {
-	var session, _ = adapter.rakLibManager.Sessions.GetSession(&net.UDPAddr{IP: net.ParseIP(address), Port: int(port)})
-	return session
+// checkRateLimit takes a token from the bucket of the packet type of the session.
+// If the session exceeded the rate limit of the packet, the action of the rate limit is taken and returned,
+// along with true to indicate the packet should not be handled.
+func (adapter *NetworkAdapter) checkRateLimit(session *MinecraftSession, packet packets.IPacket) (RateLimitAction, bool) {
+	var limit, ok = adapter.GetRateLimit(packet.GetId())
+	if !ok || session.takeToken(packet.GetId(), limit) {
+		return "", false
+	}
+
+	var name, _ = info.PacketIds.GetPacketName(packet.GetId())
+	adapter.logger.Notice(session.logName(), "exceeded the rate limit of", name+",", "action:", limit.Action)
+
+	switch limit.Action {
+	case RateLimitKick:
+		session.Kick("You are sending too many packets.", false, false)
+	case RateLimitBan:
+		adapter.limitMutex.RLock()
+		var duration = adapter.temporaryBanDuration
+		adapter.limitMutex.RUnlock()
+
+		adapter.TemporarilyBan(session.GetConnection().GetAddress().IP, duration)
+		session.Kick("You are sending too many packets.", false, false)
+	}
+	return limit.Action, true
+}
+
+// decodePacket decodes the header and the payload of a packet received by the session.
+func (adapter *NetworkAdapter) decodePacket(session *MinecraftSession, packet packets.IPacket) error {
+	var err error
+	if session.GetProtocolNumber() < 120 {
+		err = packet.DecodeId()
+	} else {
+		err = packet.DecodeHeader()
+	}
+	if err != nil {
+		return err
+	}
+	return packet.Decode()
 }
 
-// SendPacket sends a packet to the given Minecraft session with the given priority.
-func (adapter *NetworkAdapter) SendPacket(pk packets.IPacket, session *MinecraftSession, priority server.Priority) {
-	var b = NewMinecraftPacketBatch(session)
-	b.AddPacket(pk)
+// handleDecodeFailure counts a batch or packet of the session that failed to decode.
+// The session gets disconnected once it exceeds the decode failure threshold,
+// in which case false is returned and no more packets of the session should be handled.
+func (adapter *NetworkAdapter) handleDecodeFailure(session *MinecraftSession, what string, err error) bool {
+	var failures = session.addDecodeFailure()
+	adapter.logger.Debug("Failed to decode", what, "from", session.logName(), "-", err)
 
-	adapter.SendBatch(b, session.GetSession(), priority)
+	if failures > adapter.decodeFailureThreshold {
+		adapter.logger.Notice(session.logName(), "sent too many malformed packets, disconnecting.")
+		session.Kick("Malformed packets.", false, false)
+		return false
+	}
+	return true
 }
 
-// SendBatch sends a Minecraft packet batch to the given GoRakLib session with the given priority.
-func (adapter *NetworkAdapter) SendBatch(batch *MinecraftPacketBatch, session *server.Session, priority server.Priority) {
-	session.SendPacket(batch, protocol.ReliabilityReliableOrdered, priority)
+// SendPacket sends a packet to the given Minecraft session immediately, without waiting for the end of the tick.
+// Packets queued before are sent along, and interceptors registered for the packet still run before it gets sent.
+func (adapter *NetworkAdapter) SendPacket(pk packets.IPacket, session *MinecraftSession) {
+	session.SendPacketImmediately(pk)
+}
+
+// SendBatch sends a Minecraft packet batch to the given connection.
+// Batches to connections with a session are sent through the session,
+// so that they are encoded in order with all other batches of the session.
+func (adapter *NetworkAdapter) SendBatch(batch *MinecraftPacketBatch, connection Connection) {
+	if session, ok := adapter.sessionManager.GetSessionByConnection(connection); ok {
+		session.SendBatch(batch)
+		return
+	}
+	connection.SendBatch(batch)
 }
@@ -10,12 +10,13 @@ import (
 type PacketHandler struct {
 	function func(packet packets.IPacket, session *MinecraftSession) bool
 	priority int
+	owner    string
 }
 
 // NewPacketHandler returns a new packet handler with the given ID.
 // NewPacketHandler will by default use a priority of 5.
 func NewPacketHandler(function func(packet packets.IPacket, session *MinecraftSession) bool) *PacketHandler {
-	return &PacketHandler{function, 5}
+	return &PacketHandler{function, 5, ""}
 }
 
 // SetPriority sets the priority of this handler in an integer 0 - 10.
@@ -32,3 +33,15 @@ func (handler *PacketHandler) SetPriority(priority int) bool {
 func (handler *PacketHandler) GetPriority() int {
 	return handler.priority
 }
+
+// SetOwner sets the name of the plugin that registered this handler.
+// The time spent in the handler is attributed to the owner in timings.
+func (handler *PacketHandler) SetOwner(owner string) {
+	handler.owner = owner
+}
+
+// GetOwner returns the name of the plugin that registered this handler.
+// An empty string is returned for handlers of the server itself.
+func (handler *PacketHandler) GetOwner() string {
+	return handler.owner
+}
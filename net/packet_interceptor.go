@@ -0,0 +1,35 @@
+package net
+
+import (
+	"github.com/irmine/gomine/net/packets"
+)
+
+// Packet interceptors can be registered to intercept packets the server sends with certain packet IDs.
+// Interceptors run before the packet gets queued, and may modify the packet to rewrite it.
+// Discarding the packet in an interceptor cancels it, after which it does not get sent,
+// and no interceptors with a lower priority get executed.
+type PacketInterceptor struct {
+	function func(packet packets.IPacket, session *MinecraftSession)
+	priority int
+}
+
+// NewPacketInterceptor returns a new packet interceptor with the given function.
+// NewPacketInterceptor will by default use a priority of 5.
+func NewPacketInterceptor(function func(packet packets.IPacket, session *MinecraftSession)) *PacketInterceptor {
+	return &PacketInterceptor{function, 5}
+}
+
+// SetPriority sets the priority of this interceptor in an integer 0 - 10.
+// 0 is executed first, 10 is executed last.
+func (interceptor *PacketInterceptor) SetPriority(priority int) bool {
+	if priority > 10 || priority < 0 {
+		return false
+	}
+	interceptor.priority = priority
+	return true
+}
+
+// GetPriority returns the priority of this interceptor in an integer 0 - 10.
+func (interceptor *PacketInterceptor) GetPriority() int {
+	return interceptor.priority
+}
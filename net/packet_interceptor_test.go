@@ -0,0 +1,101 @@
+package net
+
+import (
+	"testing"
+
+	"github.com/irmine/gomine/net/info"
+	"github.com/irmine/gomine/net/packets"
+	"github.com/irmine/gomine/net/packets/bedrock"
+	"github.com/irmine/gomine/net/protocol"
+)
+
+// unimplementedManager leaves all packet functions of a packet manager unimplemented.
+type unimplementedManager struct {
+	protocol.IPacketManager
+}
+
+// interceptorManager is a packet manager that only keeps track of interceptors.
+type interceptorManager struct {
+	*protocol.PacketManagerBase
+	unimplementedManager
+}
+
+// newInterceptedSession returns a session with a packet manager that the interceptors are registered on.
+func newInterceptedSession(interceptors ...*PacketInterceptor) *MinecraftSession {
+	var manager = &interceptorManager{PacketManagerBase: protocol.NewPacketManagerBase(info.LatestProtocol, info.PacketIds, make(map[int]func() packets.IPacket), make(map[int][][]protocol.Handler))}
+	for _, interceptor := range interceptors {
+		manager.RegisterInterceptor(info.TextPacket, interceptor)
+	}
+	return &MinecraftSession{connection: &queueConnection{1}, packetManager: manager}
+}
+
+// newTextPacket returns a new chat text packet with the given message.
+func newTextPacket(message string) *bedrock.TextPacket {
+	var pk = bedrock.NewTextPacket()
+	pk.Message = message
+	return pk
+}
+
+func TestInterceptorPriority(t *testing.T) {
+	var order []int
+	var interceptors []*PacketInterceptor
+	for _, priority := range []int{7, 0, 10, 5} {
+		var p = priority
+		var interceptor = NewPacketInterceptor(func(packet packets.IPacket, session *MinecraftSession) {
+			order = append(order, p)
+		})
+		interceptor.SetPriority(priority)
+		interceptors = append(interceptors, interceptor)
+	}
+	var session = newInterceptedSession(interceptors...)
+
+	session.SendPacket(newTextPacket("Hello"))
+	if len(order) != 4 || order[0] != 0 || order[1] != 5 || order[2] != 7 || order[3] != 10 {
+		t.Fatalf("interceptors ran in order %v, expected [0 5 7 10]", order)
+	}
+	if len(session.queue) != 1 {
+		t.Fatalf("expected the packet to be queued, %v packets queued", len(session.queue))
+	}
+}
+
+func TestInterceptorRewrite(t *testing.T) {
+	var session = newInterceptedSession(NewPacketInterceptor(func(packet packets.IPacket, session *MinecraftSession) {
+		var pk = packet.(*bedrock.TextPacket)
+		pk.Message = "[Server] " + pk.Message
+	}))
+
+	session.SendPacket(newTextPacket("Hello"))
+	if len(session.queue) != 1 {
+		t.Fatalf("expected the packet to be queued, %v packets queued", len(session.queue))
+	}
+	if message := session.queue[0].(*bedrock.TextPacket).Message; message != "[Server] Hello" {
+		t.Fatalf("queued message %q, expected the rewritten message", message)
+	}
+}
+
+func TestInterceptorCancel(t *testing.T) {
+	var ranAfter = false
+	var cancel = NewPacketInterceptor(func(packet packets.IPacket, session *MinecraftSession) {
+		if packet.(*bedrock.TextPacket).Message == "secret" {
+			packet.Discard()
+		}
+	})
+	cancel.SetPriority(0)
+	var after = NewPacketInterceptor(func(packet packets.IPacket, session *MinecraftSession) {
+		ranAfter = true
+	})
+	var session = newInterceptedSession(cancel, after)
+
+	session.SendPacket(newTextPacket("secret"))
+	if len(session.queue) != 0 {
+		t.Fatal("cancelled packet was queued")
+	}
+	if ranAfter {
+		t.Fatal("interceptor with a lower priority ran after the packet was cancelled")
+	}
+
+	session.SendPacket(newTextPacket("Hello"))
+	if len(session.queue) != 1 || !ranAfter {
+		t.Fatal("packet that was not cancelled did not go through all interceptors")
+	}
+}
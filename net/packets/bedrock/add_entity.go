@@ -36,7 +36,7 @@ func (pk *AddEntityPacket) Encode() {
 	pk.PutUnsignedVarInt(0)
 }
 
-func (pk *AddEntityPacket) Decode() {
+func (pk *AddEntityPacket) Decode() error {
 	pk.UniqueId = pk.GetEntityUniqueId()
 	pk.RuntimeId = pk.GetEntityRuntimeId()
 	pk.EntityType = pk.GetUnsignedVarInt()
@@ -45,4 +45,5 @@ func (pk *AddEntityPacket) Decode() {
 	pk.Rotation = pk.GetEntityRotation()
 	pk.Attributes = pk.GetAttributeMap()
 	pk.EntityData = pk.GetEntityData()
+	return pk.Error()
 }
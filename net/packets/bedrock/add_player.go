@@ -61,6 +61,6 @@ func (pk *AddPlayerPacket) Encode() {
 	pk.PutString(pk.DeviceID)
 }
 
-func (pk *AddPlayerPacket) Decode() {
-
+func (pk *AddPlayerPacket) Decode() error {
+	return pk.Error()
 }
@@ -30,10 +30,11 @@ func (pk *AnimatePacket) Encode() {
 	}
 }
 
-func (pk *AnimatePacket) Decode() {
+func (pk *AnimatePacket) Decode() error {
 	pk.Action = pk.GetVarInt()
 	pk.RuntimeId = pk.GetUnsignedVarLong()
 	if uint(pk.Action) & 0x80 == 1 {
 		pk.Float = pk.GetLittleFloat()
 	}
+	return pk.Error()
 }
\ No newline at end of file
@@ -0,0 +1,218 @@
+package bedrock
+
+import (
+	"math"
+	"strings"
+
+	"github.com/irmine/gomine/net/info"
+	"github.com/irmine/gomine/net/packets"
+	"github.com/irmine/gomine/net/packets/data"
+	"github.com/irmine/gomine/net/packets/types"
+)
+
+// AvailableCommandsPacket is sent to show the client the commands it may use, for autocompletion.
+// Enums of the commands are written once in a shared list, and their values in another,
+// so that commands with equal enums share them.
+type AvailableCommandsPacket struct {
+	*packets.Packet
+	Commands  []types.CommandData
+	SoftEnums []types.CommandEnum
+}
+
+func NewAvailableCommandsPacket() *AvailableCommandsPacket {
+	return &AvailableCommandsPacket{packets.NewPacket(info.PacketIds[info.AvailableCommandsPacket]), []types.CommandData{}, []types.CommandEnum{}}
+}
+
+func (pk *AvailableCommandsPacket) Encode() {
+	var values []string
+	var valueIndexes = make(map[string]int)
+	var enums []*types.CommandEnum
+	var enumIndexes = make(map[string]int)
+	var addEnum = func(enum *types.CommandEnum) {
+		var key = getEnumKey(enum)
+		if _, ok := enumIndexes[key]; ok {
+			return
+		}
+		enumIndexes[key] = len(enums)
+		enums = append(enums, enum)
+		for _, value := range enum.Values {
+			if _, ok := valueIndexes[value]; !ok {
+				valueIndexes[value] = len(values)
+				values = append(values, value)
+			}
+		}
+	}
+	for _, command := range pk.Commands {
+		if command.Aliases != nil {
+			addEnum(command.Aliases)
+		}
+		for _, overload := range command.Overloads {
+			for _, parameter := range overload.Parameters {
+				if parameter.Enum != nil {
+					addEnum(parameter.Enum)
+				}
+			}
+		}
+	}
+
+	pk.PutUnsignedVarInt(uint32(len(values)))
+	for _, value := range values {
+		pk.PutString(value)
+	}
+	pk.PutUnsignedVarInt(0)
+
+	pk.PutUnsignedVarInt(uint32(len(enums)))
+	for _, enum := range enums {
+		pk.PutString(enum.Name)
+		pk.PutUnsignedVarInt(uint32(len(enum.Values)))
+		for _, value := range enum.Values {
+			pk.putEnumValueIndex(valueIndexes[value], len(values))
+		}
+	}
+
+	pk.PutUnsignedVarInt(uint32(len(pk.Commands)))
+	for _, command := range pk.Commands {
+		pk.PutString(command.Name)
+		pk.PutString(command.Description)
+		pk.PutByte(command.Flags)
+		pk.PutByte(command.Permission)
+		if command.Aliases != nil {
+			pk.PutLittleInt(int32(enumIndexes[getEnumKey(command.Aliases)]))
+		} else {
+			pk.PutLittleInt(-1)
+		}
+		pk.PutUnsignedVarInt(uint32(len(command.Overloads)))
+		for _, overload := range command.Overloads {
+			pk.PutUnsignedVarInt(uint32(len(overload.Parameters)))
+			for _, parameter := range overload.Parameters {
+				pk.PutString(parameter.Name)
+				if parameter.Enum != nil {
+					pk.PutLittleInt(int32(data.CommandArgValid | data.CommandArgEnum | enumIndexes[getEnumKey(parameter.Enum)]))
+				} else {
+					pk.PutLittleInt(int32(data.CommandArgValid | parameter.Type))
+				}
+				pk.PutBool(parameter.Optional)
+			}
+		}
+	}
+
+	pk.PutUnsignedVarInt(uint32(len(pk.SoftEnums)))
+	for _, enum := range pk.SoftEnums {
+		pk.PutString(enum.Name)
+		pk.PutUnsignedVarInt(uint32(len(enum.Values)))
+		for _, value := range enum.Values {
+			pk.PutString(value)
+		}
+	}
+}
+
+func (pk *AvailableCommandsPacket) Decode() error {
+	var valueCount = pk.Count(1)
+	var values = make([]string, 0, valueCount)
+	for i := uint32(0); i < valueCount; i++ {
+		values = append(values, pk.GetString())
+	}
+	var postfixCount = pk.Count(1)
+	for i := uint32(0); i < postfixCount; i++ {
+		pk.GetString()
+	}
+
+	var enumCount = pk.Count(2)
+	var enums = make([]*types.CommandEnum, 0, enumCount)
+	for i := uint32(0); i < enumCount; i++ {
+		var enum = &types.CommandEnum{Name: pk.GetString()}
+		var count = pk.Count(1)
+		for j := uint32(0); j < count; j++ {
+			var index = pk.getEnumValueIndex(len(values))
+			if pk.Error() != nil {
+				return pk.Error()
+			}
+			enum.Values = append(enum.Values, values[index])
+		}
+		enums = append(enums, enum)
+	}
+
+	var commandCount = pk.Count(9)
+	pk.Commands = make([]types.CommandData, 0, commandCount)
+	for i := uint32(0); i < commandCount; i++ {
+		var command = types.CommandData{Name: pk.GetString(), Description: pk.GetString(), Flags: pk.GetByte(), Permission: pk.GetByte()}
+		if aliases := pk.GetLittleInt(); aliases >= 0 {
+			if int(aliases) >= len(enums) {
+				pk.Failf("alias enum index %v out of range", aliases)
+				return pk.Error()
+			}
+			command.Aliases = enums[aliases]
+		}
+		var overloadCount = pk.Count(1)
+		for j := uint32(0); j < overloadCount; j++ {
+			var overload = types.CommandOverload{}
+			var parameterCount = pk.Count(6)
+			for k := uint32(0); k < parameterCount; k++ {
+				var parameter = types.CommandParameter{Name: pk.GetString()}
+				var t = uint32(pk.GetLittleInt())
+				parameter.Optional = pk.GetBool()
+				if t&data.CommandArgEnum != 0 {
+					var index = int(t & 0xffff)
+					if index >= len(enums) {
+						pk.Failf("parameter enum index %v out of range", index)
+						return pk.Error()
+					}
+					parameter.Enum = enums[index]
+				} else {
+					parameter.Type = t &^ data.CommandArgValid
+				}
+				overload.Parameters = append(overload.Parameters, parameter)
+			}
+			command.Overloads = append(command.Overloads, overload)
+		}
+		pk.Commands = append(pk.Commands, command)
+	}
+
+	var softEnumCount = pk.Count(2)
+	pk.SoftEnums = make([]types.CommandEnum, 0, softEnumCount)
+	for i := uint32(0); i < softEnumCount; i++ {
+		var enum = types.CommandEnum{Name: pk.GetString()}
+		var count = pk.Count(1)
+		for j := uint32(0); j < count; j++ {
+			enum.Values = append(enum.Values, pk.GetString())
+		}
+		pk.SoftEnums = append(pk.SoftEnums, enum)
+	}
+	return pk.Error()
+}
+
+// putEnumValueIndex writes the index of an enum value.
+// The size of the index depends on the amount of enum values.
+func (pk *AvailableCommandsPacket) putEnumValueIndex(index int, valueCount int) {
+	switch {
+	case valueCount <= math.MaxUint8:
+		pk.PutByte(byte(index))
+	case valueCount <= math.MaxUint16:
+		pk.PutLittleShort(int16(uint16(index)))
+	default:
+		pk.PutLittleInt(int32(index))
+	}
+}
+
+// getEnumValueIndex reads the index of an enum value, and checks if it is in range.
+func (pk *AvailableCommandsPacket) getEnumValueIndex(valueCount int) int {
+	var index int
+	switch {
+	case valueCount <= math.MaxUint8:
+		index = int(pk.GetByte())
+	case valueCount <= math.MaxUint16:
+		index = int(uint16(pk.GetLittleShort()))
+	default:
+		index = int(pk.GetLittleInt())
+	}
+	if index < 0 || index >= valueCount {
+		pk.Failf("enum value index %v out of range", index)
+		return 0
+	}
+	return index
+}
+
+// getEnumKey returns the key used to write enums with the same name and values only once.
+func getEnumKey(enum *types.CommandEnum) string {
+	return enum.Name + "\x00" + strings.Join(enum.Values, "\x00")
+}
@@ -18,6 +18,7 @@ func (pk *ChunkRadiusUpdatedPacket) Encode() {
 	pk.PutVarInt(pk.Radius)
 }
 
-func (pk *ChunkRadiusUpdatedPacket) Decode() {
-
+func (pk *ChunkRadiusUpdatedPacket) Decode() error {
+	pk.Radius = pk.GetVarInt()
+	return pk.Error()
 }
@@ -17,6 +17,6 @@ func (pk *ClientHandshakePacket) Encode() {
 
 }
 
-func (pk *ClientHandshakePacket) Decode() {
-
+func (pk *ClientHandshakePacket) Decode() error {
+	return pk.Error()
 }
@@ -20,13 +20,18 @@ func NewCommandRequestPacket() *CommandRequestPacket {
 }
 
 func (pk *CommandRequestPacket) Encode() {
-
+	pk.PutString(pk.CommandText)
+	pk.PutUnsignedVarInt(pk.Type)
+	pk.PutUUID(pk.UUID)
+	pk.PutString(pk.RequestId)
+	pk.PutBool(pk.Internal)
 }
 
-func (pk *CommandRequestPacket) Decode() {
+func (pk *CommandRequestPacket) Decode() error {
 	pk.CommandText = pk.GetString()
 	pk.Type = pk.GetUnsignedVarInt()
 	pk.UUID = pk.GetUUID()
 	pk.RequestId = pk.GetString()
 	pk.Internal = pk.GetBool()
+	return pk.Error()
 }
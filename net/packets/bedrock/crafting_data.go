@@ -18,6 +18,6 @@ func (pk *CraftingDataPacket) Encode() {
 	pk.PutBool(true)
 }
 
-func (pk *CraftingDataPacket) Decode() {
-
+func (pk *CraftingDataPacket) Decode() error {
+	return pk.Error()
 }
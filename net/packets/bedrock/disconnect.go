@@ -20,7 +20,8 @@ func (pk *DisconnectPacket) Encode() {
 	pk.PutString(pk.Message)
 }
 
-func (pk *DisconnectPacket) Decode() {
+func (pk *DisconnectPacket) Decode() error {
 	pk.HideDisconnectionScreen = pk.GetBool()
 	pk.Message = pk.GetString()
+	return pk.Error()
 }
@@ -22,6 +22,9 @@ func (pk *FullChunkDataPacket) Encode() {
 	pk.PutLengthPrefixedBytes(pk.ChunkData)
 }
 
-func (pk *FullChunkDataPacket) Decode() {
-
+func (pk *FullChunkDataPacket) Decode() error {
+	pk.ChunkX = pk.GetVarInt()
+	pk.ChunkZ = pk.GetVarInt()
+	pk.ChunkData = pk.GetLengthPrefixedBytes()
+	return pk.Error()
 }
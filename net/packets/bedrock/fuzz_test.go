@@ -0,0 +1,99 @@
+package bedrock
+
+import (
+	"testing"
+
+	"github.com/irmine/gomine/net/info"
+	"github.com/irmine/gomine/net/packets"
+	"github.com/irmine/gomine/net/packets/data"
+	"github.com/irmine/gomine/net/packets/types"
+)
+
+// newPacket returns a new packet for the packet ID in the first byte of the buffer.
+func newPacket(buffer []byte) (packets.IPacket, bool) {
+	if len(buffer) == 0 {
+		return nil, false
+	}
+	var name, ok = info.PacketIds.GetPacketName(int(buffer[0]))
+	if !ok {
+		return nil, false
+	}
+	packetFunc, ok := Packets[name]
+	if !ok {
+		return nil, false
+	}
+	return packetFunc(), true
+}
+
+func FuzzDecode(f *testing.F) {
+	for name := range Packets {
+		var id = byte(info.PacketIds[name])
+		f.Add([]byte{id})
+		f.Add([]byte{id, 0xff, 0xff, 0xff, 0xff, 0x0f})
+		f.Add(append([]byte{id}, make([]byte, 64)...))
+	}
+
+	var text = NewTextPacket()
+	text.TextType = data.TextChat
+	text.SourceName = "Steve"
+	text.Message = "Hello"
+	text.EncodeHeader()
+	text.Encode()
+	f.Add(text.GetBuffer())
+
+	var commands = NewAvailableCommandsPacket()
+	commands.Commands = []types.CommandData{{
+		Name:        "give",
+		Description: "Gives an item to a player",
+		Aliases:     &types.CommandEnum{Name: "giveAliases", Values: []string{"give", "g"}},
+		Overloads: []types.CommandOverload{{Parameters: []types.CommandParameter{
+			{Name: "player", Type: data.CommandArgTarget},
+			{Name: "item", Enum: &types.CommandEnum{Name: "Item", Values: []string{"stone", "air"}}},
+			{Name: "amount", Type: data.CommandArgInt, Optional: true},
+		}}},
+	}}
+	commands.EncodeHeader()
+	commands.Encode()
+	f.Add(commands.GetBuffer())
+
+	f.Fuzz(func(t *testing.T, buffer []byte) {
+		var packet, ok = newPacket(buffer)
+		if !ok {
+			return
+		}
+		packet.SetBuffer(buffer)
+		if err := packet.DecodeHeader(); err != nil {
+			t.Fatalf("decoding header of packet 0x%02x: %v", buffer[0], err)
+		}
+		packet.Decode()
+	})
+}
+
+func TestDecodeTruncated(t *testing.T) {
+	var text = NewTextPacket()
+	text.TextType = data.TextChat
+	text.SourceName = "Steve"
+	text.Message = "Hello"
+	text.EncodeHeader()
+	text.Encode()
+	var buffer = text.GetBuffer()
+
+	for i := 1; i < len(buffer); i++ {
+		var packet = NewTextPacket()
+		packet.SetBuffer(append([]byte{}, buffer[:i]...))
+		packet.DecodeHeader()
+		if err := packet.Decode(); err == nil {
+			t.Errorf("decoding text packet truncated to %v of %v bytes succeeded", i, len(buffer))
+		}
+	}
+
+	var packet = NewTextPacket()
+	packet.SetBuffer(buffer)
+	packet.DecodeHeader()
+	if err := packet.Decode(); err != nil {
+		t.Fatal(err)
+	}
+	if packet.SourceName != "Steve" || packet.Message != "Hello" {
+		t.Errorf("decoded %q: %q, expected Steve: Hello", packet.SourceName, packet.Message)
+	}
+}
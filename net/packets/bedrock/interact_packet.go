@@ -27,7 +27,8 @@ func (pk *InteractPacket) Encode() {
 	pk.PutUnsignedVarLong(pk.RuntimeId)
 }
 
-func (pk *InteractPacket) Decode() {
+func (pk *InteractPacket) Decode() error {
 	pk.Action = pk.GetByte()
 	pk.RuntimeId = pk.GetUnsignedVarLong()
+	return pk.Error()
 }
\ No newline at end of file
@@ -97,7 +97,7 @@ func (pk *InventoryTransactionPacket) Encode()  {
 	}
 }
 
-func (pk *InventoryTransactionPacket) Decode() {
+func (pk *InventoryTransactionPacket) Decode() error {
 	pk.TransactionType = pk.GetUnsignedVarInt()
 	pk.ActionList.ReadFromBuffer(pk.MinecraftStream)
 
@@ -128,6 +128,7 @@ func (pk *InventoryTransactionPacket) Decode() {
 		pk.HeadPosition = pk.GetVector()
 		break
 	default:
-		panic("Error: Unknown transaction type received: " + string(pk.TransactionType))
+		pk.Failf("unknown transaction type received: %v", pk.TransactionType)
 	}
+	return pk.Error()
 }
@@ -3,6 +3,7 @@ package bedrock
 import (
 	"encoding/base64"
 	"encoding/json"
+	"fmt"
 	"strings"
 
 	"github.com/google/uuid"
@@ -32,42 +33,72 @@ type LoginPacket struct {
 
 	ClientData types.ClientDataKeys
 	Chains     []types.Chain
+
+	// RawChains are the encoded JWT chains of the login.
+	// RawClientData is the encoded JWT containing the client data.
+	// Both are set when decoding, and are written when encoding.
+	RawChains     []string
+	RawClientData string
 }
 
 func NewLoginPacket() *LoginPacket {
-	pk := &LoginPacket{packets.NewPacket(info.PacketIds[info.LoginPacket]), "", 0, uuid.New(), 0, "", "", "", "", "", []byte{}, []byte{}, "", "", types.ClientDataKeys{}, []types.Chain{}}
+	pk := &LoginPacket{packets.NewPacket(info.PacketIds[info.LoginPacket]), "", 0, uuid.New(), 0, "", "", "", "", "", []byte{}, []byte{}, "", "", types.ClientDataKeys{}, []types.Chain{}, []string{}, ""}
 	return pk
 }
 
 func (pk *LoginPacket) Encode() {
+	pk.PutInt(pk.Protocol)
+
+	var chainData, _ = json.Marshal(map[string][]string{"chain": pk.RawChains})
+	var stream = binutils.NewStream()
+	stream.PutLittleInt(int32(len(chainData)))
+	stream.PutBytes(chainData)
+	stream.PutLittleInt(int32(len(pk.RawClientData)))
+	stream.PutBytes([]byte(pk.RawClientData))
 
+	pk.PutString(string(stream.Buffer))
 }
 
-func (pk *LoginPacket) Decode() {
+func (pk *LoginPacket) Decode() error {
 	pk.Protocol = pk.GetInt()
 
-	var stream = binutils.NewStream()
-	stream.Buffer = []byte(pk.GetString())
+	var stream = packets.NewMinecraftStream()
+	stream.SetBuffer(pk.GetLengthPrefixedBytes())
+	if err := pk.Error(); err != nil {
+		return err
+	}
 
-	var length = int(stream.GetLittleInt())
+	var chainJson = stream.Get(int(stream.GetLittleInt()))
+	if err := stream.Error(); err != nil {
+		return err
+	}
 
 	var chainData = &types.ChainDataKeys{}
-	json.Unmarshal(stream.Get(length), &chainData)
+	if err := json.Unmarshal(chainJson, &chainData); err != nil {
+		return fmt.Errorf("invalid login chain data: %v", err)
+	}
+	pk.RawChains = chainData.RawChains
 
 	for _, v := range chainData.RawChains {
 		WebToken := &types.WebTokenKeys{}
 		pk.Chains = append(pk.Chains, pk.BuildChain(v))
 
-		utils.DecodeJwtPayload(v, WebToken)
+		if err := utils.DecodeJwtPayload(v, WebToken); err != nil {
+			return fmt.Errorf("invalid login chain: %v", err)
+		}
 
-		if v, ok := WebToken.ExtraData["displayName"]; ok {
-			pk.Username = v.(string)
+		if v, ok := WebToken.ExtraData["displayName"].(string); ok {
+			pk.Username = v
 		}
-		if v, ok := WebToken.ExtraData["identity"]; ok {
-			pk.ClientUUID = uuid.Must(uuid.Parse(v.(string)))
+		if v, ok := WebToken.ExtraData["identity"].(string); ok {
+			var identity, err = uuid.Parse(v)
+			if err != nil {
+				return fmt.Errorf("invalid identity in login chain: %v", err)
+			}
+			pk.ClientUUID = identity
 		}
-		if v, ok := WebToken.ExtraData["XUID"]; ok {
-			pk.ClientXUID = v.(string)
+		if v, ok := WebToken.ExtraData["XUID"].(string); ok {
+			pk.ClientXUID = v
 		}
 		if len(WebToken.IdentityPublicKey) > 0 {
 			pk.IdentityPublicKey = WebToken.IdentityPublicKey
@@ -75,9 +106,15 @@ func (pk *LoginPacket) Decode() {
 	}
 
 	var clientDataJwt = stream.Get(int(stream.GetLittleInt()))
+	if err := stream.Error(); err != nil {
+		return err
+	}
 	var clientData = &types.ClientDataKeys{}
+	pk.RawClientData = string(clientDataJwt)
 
-	utils.DecodeJwtPayload(string(clientDataJwt), clientData)
+	if err := utils.DecodeJwtPayload(string(clientDataJwt), clientData); err != nil {
+		return fmt.Errorf("invalid client data: %v", err)
+	}
 
 	pk.ClientId = clientData.ClientRandomId
 	pk.ServerAddress = clientData.ServerAddress
@@ -99,6 +136,7 @@ func (pk *LoginPacket) Decode() {
 	}
 
 	pk.ClientData = *clientData
+	return pk.Error()
 }
 
 func (pk *LoginPacket) BuildChain(raw string) types.Chain {
@@ -26,9 +26,10 @@ func (pk *MoveEntityPacket) Encode() {
 	pk.PutEntityRotationBytes(pk.Rotation)
 }
 
-func (pk *MoveEntityPacket) Decode() {
+func (pk *MoveEntityPacket) Decode() error {
 	pk.RuntimeId = pk.GetEntityRuntimeId()
 	pk.Flags = pk.GetByte()
 	pk.Position = pk.GetVector()
 	pk.Rotation = pk.GetEntityRotationBytes()
+	return pk.Error()
 }
@@ -36,7 +36,7 @@ func (pk *MovePlayerPacket) Encode() {
 	}
 }
 
-func (pk *MovePlayerPacket) Decode() {
+func (pk *MovePlayerPacket) Decode() error {
 	pk.RuntimeId = pk.GetEntityRuntimeId()
 	pk.Position = pk.GetVector()
 	pk.Rotation = pk.GetPlayerRotation()
@@ -47,4 +47,5 @@ func (pk *MovePlayerPacket) Decode() {
 		pk.TeleportCause = pk.GetLittleInt()
 		pk.TeleportItem = pk.GetLittleInt()
 	}
+	return pk.Error()
 }
@@ -21,5 +21,6 @@ func (pk *NetworkChunkPublisherUpdatePacket) Encode() {
 	pk.PutUnsignedVarInt(pk.Radius)
 }
 
-func (pk *NetworkChunkPublisherUpdatePacket) Decode() {
+func (pk *NetworkChunkPublisherUpdatePacket) Decode() error {
+	return pk.Error()
 }
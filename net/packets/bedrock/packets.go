@@ -0,0 +1,46 @@
+package bedrock
+
+import (
+	"github.com/irmine/gomine/net/info"
+	"github.com/irmine/gomine/net/packets"
+)
+
+// Packets holds a function returning a new packet for every packet implemented in this package.
+// It may be used to decode packets of either direction, for example when inspecting captured packets.
+var Packets = map[info.PacketName]func() packets.IPacket{
+	info.AddEntityPacket:                   func() packets.IPacket { return NewAddEntityPacket() },
+	info.AddPlayerPacket:                   func() packets.IPacket { return NewAddPlayerPacket() },
+	info.AnimatePacket:                     func() packets.IPacket { return NewAnimatePacket() },
+	info.AvailableCommandsPacket:           func() packets.IPacket { return NewAvailableCommandsPacket() },
+	info.ChunkRadiusUpdatedPacket:          func() packets.IPacket { return NewChunkRadiusUpdatedPacket() },
+	info.ClientHandshakePacket:             func() packets.IPacket { return NewClientHandshakePacket() },
+	info.CommandRequestPacket:              func() packets.IPacket { return NewCommandRequestPacket() },
+	info.CraftingDataPacket:                func() packets.IPacket { return NewCraftingDataPacket() },
+	info.DisconnectPacket:                  func() packets.IPacket { return NewDisconnectPacket() },
+	info.FullChunkDataPacket:               func() packets.IPacket { return NewFullChunkDataPacket() },
+	info.InteractPacket:                    func() packets.IPacket { return NewInteractPacket() },
+	info.InventoryTransactionPacket:        func() packets.IPacket { return NewInventoryTransactionPacket() },
+	info.LoginPacket:                       func() packets.IPacket { return NewLoginPacket() },
+	info.MoveEntityPacket:                  func() packets.IPacket { return NewMoveEntityPacket() },
+	info.MovePlayerPacket:                  func() packets.IPacket { return NewMovePlayerPacket() },
+	info.NetworkChunkPublisherUpdatePacket: func() packets.IPacket { return NewNetworkChunkPublisherUpdatePacket() },
+	info.PlayStatusPacket:                  func() packets.IPacket { return NewPlayStatusPacket() },
+	info.PlayerActionPacket:                func() packets.IPacket { return NewPlayerActionPacket() },
+	info.PlayerListPacket:                  func() packets.IPacket { return NewPlayerListPacket() },
+	info.PlayerSkinPacket:                  func() packets.IPacket { return NewPlayerSkinPacket() },
+	info.RemoveEntityPacket:                func() packets.IPacket { return NewRemoveEntityPacket() },
+	info.RequestChunkRadiusPacket:          func() packets.IPacket { return NewRequestChunkRadiusPacket() },
+	info.ResourcePackChunkDataPacket:       func() packets.IPacket { return NewResourcePackChunkDataPacket() },
+	info.ResourcePackChunkRequestPacket:    func() packets.IPacket { return NewResourcePackChunkRequestPacket() },
+	info.ResourcePackClientResponsePacket:  func() packets.IPacket { return NewResourcePackClientResponsePacket() },
+	info.ResourcePackDataInfoPacket:        func() packets.IPacket { return NewResourcePackDataInfoPacket() },
+	info.ResourcePackInfoPacket:            func() packets.IPacket { return NewResourcePackInfoPacket() },
+	info.ResourcePackStackPacket:           func() packets.IPacket { return NewResourcePackStackPacket() },
+	info.ServerHandshakePacket:             func() packets.IPacket { return NewServerHandshakePacket() },
+	info.SetEntityDataPacket:               func() packets.IPacket { return NewSetEntityDataPacket() },
+	info.StartGamePacket:                   func() packets.IPacket { return NewStartGamePacket() },
+	info.TextPacket:                        func() packets.IPacket { return NewTextPacket() },
+	info.TransferPacket:                    func() packets.IPacket { return NewTransferPacket() },
+	info.UpdateAttributesPacket:            func() packets.IPacket { return NewUpdateAttributesPacket() },
+	info.UpdateBlockPacket:                 func() packets.IPacket { return NewUpdateBlockPacket() },
+}
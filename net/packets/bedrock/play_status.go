@@ -18,6 +18,7 @@ func (pk *PlayStatusPacket) Encode() {
 	pk.PutInt(pk.Status)
 }
 
-func (pk *PlayStatusPacket) Decode() {
+func (pk *PlayStatusPacket) Decode() error {
 	pk.Status = pk.GetInt()
+	return pk.Error()
 }
@@ -49,9 +49,10 @@ func (pk *PlayerActionPacket) Encode() {
 	pk.PutVarInt(pk.Face)
 }
 
-func (pk *PlayerActionPacket) Decode() {
+func (pk *PlayerActionPacket) Decode() error {
 	pk.RuntimeId = pk.GetEntityRuntimeId()
 	pk.Action = pk.GetVarInt()
 	pk.Position = pk.GetBlockPosition()
 	pk.Face = pk.GetVarInt()
+	return pk.Error()
 }
\ No newline at end of file
@@ -41,6 +41,6 @@ func (pk *PlayerListPacket) Encode() {
 	}
 }
 
-func (pk *PlayerListPacket) Decode() {
-
+func (pk *PlayerListPacket) Decode() error {
+	return pk.Error()
 }
@@ -38,7 +38,7 @@ func (pk *PlayerSkinPacket) Encode() {
 	pk.PutBool(pk.PremiumSkin)
 }
 
-func (pk *PlayerSkinPacket) Decode() {
+func (pk *PlayerSkinPacket) Decode() error {
 	pk.UUID = pk.GetUUID()
 	pk.SkinId = pk.GetString()
 	pk.NewSkinName = pk.GetString()
@@ -48,4 +48,5 @@ func (pk *PlayerSkinPacket) Decode() {
 	pk.GeometryName = pk.GetString()
 	pk.GeometryData = pk.GetString()
 	pk.PremiumSkin = pk.GetBool()
+	return pk.Error()
 }
@@ -18,6 +18,6 @@ func (pk *RemoveEntityPacket) Encode() {
 	pk.PutEntityUniqueId(pk.EntityUniqueId)
 }
 
-func (pk *RemoveEntityPacket) Decode() {
-
+func (pk *RemoveEntityPacket) Decode() error {
+	return pk.Error()
 }
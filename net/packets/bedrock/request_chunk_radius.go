@@ -15,9 +15,10 @@ func NewRequestChunkRadiusPacket() *RequestChunkRadiusPacket {
 }
 
 func (pk *RequestChunkRadiusPacket) Encode() {
-
+	pk.PutVarInt(pk.Radius)
 }
 
-func (pk *RequestChunkRadiusPacket) Decode() {
+func (pk *RequestChunkRadiusPacket) Decode() error {
 	pk.Radius = pk.GetVarInt()
+	return pk.Error()
 }
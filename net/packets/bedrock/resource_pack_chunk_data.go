@@ -25,6 +25,6 @@ func (pk *ResourcePackChunkDataPacket) Encode() {
 	pk.PutBytes(pk.ChunkData)
 }
 
-func (pk *ResourcePackChunkDataPacket) Decode() {
-
+func (pk *ResourcePackChunkDataPacket) Decode() error {
+	return pk.Error()
 }
@@ -19,7 +19,8 @@ func (pk *ResourcePackChunkRequestPacket) Encode() {
 
 }
 
-func (pk *ResourcePackChunkRequestPacket) Decode() {
+func (pk *ResourcePackChunkRequestPacket) Decode() error {
 	pk.PackUUID = pk.GetString()
 	pk.ChunkIndex = pk.GetLittleInt()
+	return pk.Error()
 }
@@ -16,13 +16,18 @@ func NewResourcePackClientResponsePacket() *ResourcePackClientResponsePacket {
 }
 
 func (pk *ResourcePackClientResponsePacket) Encode() {
-
+	pk.PutByte(pk.Status)
+	pk.PutLittleShort(int16(len(pk.PackUUIDs)))
+	for _, packUUID := range pk.PackUUIDs {
+		pk.PutString(packUUID)
+	}
 }
 
-func (pk *ResourcePackClientResponsePacket) Decode() {
+func (pk *ResourcePackClientResponsePacket) Decode() error {
 	pk.Status = pk.GetByte()
 	var idCount = pk.GetLittleShort()
-	for i := int16(0); i < idCount; i++ {
+	for i := int16(0); i < idCount && pk.Error() == nil; i++ {
 		pk.PackUUIDs = append(pk.PackUUIDs, pk.GetString())
 	}
+	return pk.Error()
 }
@@ -26,6 +26,6 @@ func (pk *ResourcePackDataInfoPacket) Encode() {
 	pk.PutString(pk.Sha256)
 }
 
-func (pk *ResourcePackDataInfoPacket) Decode() {
-
+func (pk *ResourcePackDataInfoPacket) Decode() error {
+	return pk.Error()
 }
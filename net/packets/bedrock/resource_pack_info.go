@@ -25,6 +25,10 @@ func (pk *ResourcePackInfoPacket) Encode() {
 	pk.PutPackInfo(pk.ResourcePacks)
 }
 
-func (pk *ResourcePackInfoPacket) Decode() {
-
+func (pk *ResourcePackInfoPacket) Decode() error {
+	pk.MustAccept = pk.GetBool()
+	pk.Bool1 = pk.GetBool()
+	pk.BehaviorPacks = pk.GetPackInfo()
+	pk.ResourcePacks = pk.GetPackInfo()
+	return pk.Error()
 }
@@ -25,6 +25,10 @@ func (pk *ResourcePackStackPacket) Encode() {
 	pk.PutBool(pk.Experimental)
 }
 
-func (pk *ResourcePackStackPacket) Decode() {
-
+func (pk *ResourcePackStackPacket) Decode() error {
+	pk.MustAccept = pk.GetBool()
+	pk.BehaviorPacks = pk.GetPackStack()
+	pk.ResourcePacks = pk.GetPackStack()
+	pk.Experimental = pk.GetBool()
+	return pk.Error()
 }
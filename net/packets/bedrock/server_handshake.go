@@ -18,6 +18,7 @@ func (pk *ServerHandshakePacket) Encode() {
 	pk.PutString(pk.Jwt)
 }
 
-func (pk *ServerHandshakePacket) Decode() {
-
+func (pk *ServerHandshakePacket) Decode() error {
+	pk.Jwt = pk.GetString()
+	return pk.Error()
 }
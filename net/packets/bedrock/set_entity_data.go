@@ -20,7 +20,8 @@ func (pk *SetEntityDataPacket) Encode() {
 	pk.PutEntityData(pk.EntityData)
 }
 
-func (pk *SetEntityDataPacket) Decode() {
+func (pk *SetEntityDataPacket) Decode() error {
 	pk.RuntimeId = pk.GetEntityRuntimeId()
 	pk.EntityData = pk.GetEntityData()
+	return pk.Error()
 }
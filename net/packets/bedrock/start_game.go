@@ -126,6 +126,70 @@ func (pk *StartGamePacket) Encode() {
 	pk.PutString(pk.MultiplayerCorrelationID)
 }
 
-func (pk *StartGamePacket) Decode() {
-
+func (pk *StartGamePacket) Decode() error {
+	pk.EntityUniqueId = pk.GetEntityUniqueId()
+	pk.EntityRuntimeId = pk.GetEntityRuntimeId()
+
+	pk.PlayerGameMode = pk.GetVarInt()
+	pk.PlayerPosition = pk.GetVector()
+
+	pk.Pitch = pk.GetLittleFloat()
+	pk.Yaw = pk.GetLittleFloat()
+
+	pk.LevelSeed = pk.GetVarInt()
+	pk.Dimension = pk.GetVarInt()
+	pk.Generator = pk.GetVarInt()
+	pk.LevelGameMode = pk.GetVarInt()
+	pk.Difficulty = pk.GetVarInt()
+
+	pk.LevelSpawnPosition = pk.GetBlockPosition()
+	pk.AchievementsDisabled = pk.GetBool()
+	pk.Time = pk.GetVarInt()
+	pk.EduMode = pk.GetBool()
+	pk.EduFeaturesEnabled = pk.GetBool()
+
+	pk.RainLevel = pk.GetLittleFloat()
+	pk.LightningLevel = pk.GetLittleFloat()
+
+	pk.Bool1 = pk.GetBool()
+	pk.MultiPlayerGame = pk.GetBool()
+	pk.BroadcastToLan = pk.GetBool()
+	pk.XBOXBroadcastIntent = pk.GetVarInt()
+	pk.PlatformBroadcastIntent = pk.GetVarInt()
+
+	pk.CommandsEnabled = pk.GetBool()
+	pk.ForcedResourcePacks = pk.GetBool()
+
+	pk.GameRules = pk.GetGameRules()
+
+	pk.BonusChest = pk.GetBool()
+	pk.StartMap = pk.GetBool()
+	pk.DefaultPermissionLevel = pk.GetVarInt()
+	pk.ServerChunkTickRange = pk.GetLittleInt()
+	pk.LockedBehaviorPack = pk.GetBool()
+	pk.LockedResourcePack = pk.GetBool()
+	pk.FromLockedWorldTemplate = pk.GetBool()
+	pk.UseMsaGamertagsOnly = pk.GetBool()
+	pk.FromWorldTemplate = pk.GetBool()
+	pk.WorldTemplateOptionLocked = pk.GetBool()
+
+	pk.GetString() // Level name base64 encoded
+	pk.LevelName = pk.GetString()
+	pk.GetString() // Premium world template ID
+	pk.IsTrial = pk.GetBool()
+	pk.CurrentTick = pk.GetLittleLong()
+	pk.EnchantmentSeed = pk.GetVarInt()
+
+	// The runtime ID table is a count followed by a block name and data value for every entry.
+	var tableOffset = pk.Offset
+	var count = pk.Count(3)
+	for i := uint32(0); i < count; i++ {
+		pk.GetString()
+		pk.GetLittleShort()
+	}
+	if pk.Error() == nil {
+		pk.RuntimeIdsTable = append([]byte{}, pk.Buffer[tableOffset:pk.Offset]...)
+	}
+	pk.MultiplayerCorrelationID = pk.GetString()
+	return pk.Error()
 }
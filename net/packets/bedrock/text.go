@@ -56,12 +56,12 @@ func (pk *TextPacket) Encode()  {
 	pk.PutString(pk.PlatformChatId)
 }
 
-func (pk *TextPacket) Decode() {
+func (pk *TextPacket) Decode() error {
 	pk.TextType = pk.GetByte()
 	pk.Translation = pk.GetBool()
 
 	switch pk.TextType {
-	case data.TextRaw, data.TextTip, data.TextSystem:
+	case data.TextRaw, data.TextTip, data.TextSystem, data.TextJson:
 		pk.Message = pk.GetString()
 		break
 	case data.TextChat, data.TextWhisper, data.TextAnnouncement:
@@ -70,7 +70,7 @@ func (pk *TextPacket) Decode() {
 		break
 	case data.TextTranslation, data.TextPopup, data.TextJukeboxPopup:
 		pk.Message = pk.GetString()
-		c := pk.GetUnsignedVarInt()
+		c := pk.Count(1)
 		for i := uint32(0); i < c; i++ {
 			pk.Params = append(pk.Params, pk.GetString())
 		}
@@ -79,4 +79,5 @@ func (pk *TextPacket) Decode() {
 
 	pk.XUID = pk.GetString()
 	pk.PlatformChatId = pk.GetString()
+	return pk.Error()
 }
\ No newline at end of file
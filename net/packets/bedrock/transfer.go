@@ -20,6 +20,8 @@ func (pk *TransferPacket) Encode() {
 	pk.PutLittleShort(int16(pk.Port))
 }
 
-func (pk *TransferPacket) Decode() {
-
+func (pk *TransferPacket) Decode() error {
+	pk.Address = pk.GetString()
+	pk.Port = uint16(pk.GetLittleShort())
+	return pk.Error()
 }
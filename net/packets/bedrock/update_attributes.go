@@ -21,7 +21,8 @@ func (pk *UpdateAttributesPacket) Encode() {
 	pk.PutAttributeMap(pk.Attributes)
 }
 
-func (pk *UpdateAttributesPacket) Decode() {
+func (pk *UpdateAttributesPacket) Decode() error {
 	pk.RuntimeId = pk.GetEntityRuntimeId()
 	pk.Attributes = pk.GetAttributeMap()
+	return pk.Error()
 }
@@ -30,9 +30,10 @@ func (pk *UpdateBlockPacket) Encode() {
 	pk.PutUnsignedVarInt(pk.DataLayerId)
 }
 
-func (pk *UpdateBlockPacket) Decode() {
+func (pk *UpdateBlockPacket) Decode() error {
 	pk.Position = pk.GetBlockPosition()
 	pk.BlockRuntimeId = pk.GetUnsignedVarInt()
 	pk.Flags = pk.GetUnsignedVarInt()
 	pk.DataLayerId = pk.GetUnsignedVarInt()
+	return pk.Error()
 }
@@ -12,6 +12,7 @@ const (
 	StatusLoginFailedInvalidTenant
 	StatusLoginFailedVanillaEdu
 	StatusLoginFailedEduVanilla
+	StatusLoginFailedServerFull
 )
 
 const (
@@ -54,3 +55,22 @@ const (
 	ListTypeAdd = iota
 	ListTypeRemove
 )
+
+const (
+	CommandArgInt      = 0x01
+	CommandArgFloat    = 0x02
+	CommandArgValue    = 0x03
+	CommandArgTarget   = 0x06
+	CommandArgString   = 0x1b
+	CommandArgPosition = 0x1d
+	CommandArgMessage  = 0x20
+	CommandArgRawText  = 0x22
+	CommandArgJson     = 0x25
+)
+
+const (
+	CommandArgValid    = 0x100000
+	CommandArgEnum     = 0x200000
+	CommandArgPostfix  = 0x1000000
+	CommandArgSoftEnum = 0x4000000
+)
@@ -20,12 +20,14 @@ type MinecraftStream struct {
 	// Usual binary encoding/decoding functions can
 	// be called on a MinecraftStream.
 	*binutils.Stream
+	// err is the first error that occurred while reading from the stream.
+	err error
 }
 
 // NewMinecraftStream reads a new MinecraftStream.
 // This stream is pre-initialized and ready for usage.
 func NewMinecraftStream() *MinecraftStream {
-	return &MinecraftStream{binutils.NewStream()}
+	return &MinecraftStream{Stream: binutils.NewStream()}
 }
 
 // PutEntityRuntimeId writes the runtime ID of an entity.
@@ -157,7 +159,7 @@ func (stream *MinecraftStream) PutAttributeMap(m data.AttributeMap) {
 // not set in the default attribute map, or missing attributes.
 func (stream *MinecraftStream) GetAttributeMap() data.AttributeMap {
 	m := data.NewAttributeMap()
-	c := stream.GetUnsignedVarInt()
+	c := stream.Count(17)
 	for i := uint32(0); i < c; i++ {
 		min := stream.GetLittleFloat()
 		max := stream.GetLittleFloat()
@@ -206,24 +208,31 @@ func (stream *MinecraftStream) GetItem() *items.Stack {
 	aux := stream.GetVarInt()
 	itemData := aux >> 8
 
-	t := items.IdToType[items.GetKey(int16(id), int16(itemData))]
+	t, ok := items.IdToType[items.GetKey(int16(id), int16(itemData))]
 	count := aux & 0xff
 
 	var nbtLength int16
 	var nbtData *gonbt.Compound
 
-	item, _ := items.DefaultManager.Get(t.GetId(), int(count))
+	var item *items.Stack
+	if ok {
+		item, _ = items.DefaultManager.Get(t.GetId(), int(count))
+	}
+	if item == nil {
+		item, _ = items.DefaultManager.Get("minecraft:air", 0)
+	}
 	nbtLength = stream.GetLittleShort()
 	//text.DefaultLogger.Debug(nbtLength)
 	if nbtLength > 0 {
-		reader := gonbt.NewReader(stream.Get(int(nbtLength)), true, binutils.LittleEndian)
-		nbtData = reader.ReadUncompressedIntoCompound()
+		if b := stream.Get(int(nbtLength)); b != nil {
+			nbtData, _ = stream.readNBT(b)
+		}
 	}else if nbtLength == -1 {
-		nbtCount := stream.GetUnsignedVarInt()
-		for i := uint32(0); i < nbtCount; i++ {
-			reader := gonbt.NewReader(stream.Buffer[stream.Offset:], true, binutils.LittleEndian)
-			nbtData = reader.ReadUncompressedIntoCompound()
-			stream.Offset += reader.GetOffset()
+		nbtCount := stream.Count(1)
+		for i := uint32(0); i < nbtCount && stream.Error() == nil; i++ {
+			var length int
+			nbtData, length = stream.readNBT(stream.Buffer[stream.Offset:])
+			stream.Offset += length
 		}
 	}
 
@@ -235,13 +244,13 @@ func (stream *MinecraftStream) GetItem() *items.Stack {
 	// TODO
 	canPlace := stream.GetVarInt()
 	if canPlace > 0 {
-		for i := int32(0); i < canPlace; i++ {
+		for i := int32(0); i < canPlace && stream.Error() == nil; i++ {
 			stream.GetString()
 		}
 	}
 	canBreak := stream.GetVarInt()
 	if canBreak > 0 {
-		for i := int32(0); i < canBreak; i++ {
+		for i := int32(0); i < canBreak && stream.Error() == nil; i++ {
 			stream.GetString()
 		}
 	}
@@ -251,6 +260,22 @@ func (stream *MinecraftStream) GetItem() *items.Stack {
 	return item
 }
 
+// readNBT reads an uncompressed NBT compound from the buffer,
+// and returns the compound with the amount of bytes read.
+// The NBT reader does not check bounds, so reading malformed NBT
+// sets the error of the stream rather than panicking.
+func (stream *MinecraftStream) readNBT(buffer []byte) (compound *gonbt.Compound, length int) {
+	defer func() {
+		if err := recover(); err != nil {
+			stream.Failf("malformed item NBT: %v", err)
+			compound, length = nil, 0
+		}
+	}()
+	reader := gonbt.NewReader(buffer, true, binutils.LittleEndian)
+	compound = reader.ReadUncompressedIntoCompound()
+	return compound, reader.GetOffset()
+}
+
 // PutEntityData writes the data properties of an entity.
 func (stream *MinecraftStream) PutEntityData(entityData map[uint32][]interface{}) {
 	var count= uint32(len(entityData))
@@ -318,7 +343,7 @@ func (stream *MinecraftStream) PutEntityData(entityData map[uint32][]interface{}
 // GetEntityData reads an entity data property map from an entity.
 func (stream *MinecraftStream) GetEntityData() map[uint32][]interface{} {
 	entityData := make(map[uint32][]interface{})
-	count := stream.GetUnsignedVarInt()
+	count := stream.Count(2)
 	if count > 0 {
 		for i := uint32(0); i < count; i++ {
 			var key = stream.GetUnsignedVarInt()
@@ -378,6 +403,26 @@ func (stream *MinecraftStream) PutGameRules(gameRules map[string]types.GameRuleE
 	}
 }
 
+// GetGameRules reads a map of game rules.
+// Game rules are prefixed by their type, and are read as bool, uint32 or float32.
+func (stream *MinecraftStream) GetGameRules() map[string]types.GameRuleEntry {
+	var gameRules = make(map[string]types.GameRuleEntry)
+	var count = stream.Count(2)
+	for i := uint32(0); i < count; i++ {
+		var gameRule = types.GameRuleEntry{Name: stream.GetString()}
+		switch stream.GetUnsignedVarInt() {
+		case 1:
+			gameRule.Value = stream.GetBool()
+		case 2:
+			gameRule.Value = stream.GetUnsignedVarInt()
+		case 3:
+			gameRule.Value = stream.GetLittleFloat()
+		}
+		gameRules[gameRule.Name] = gameRule
+	}
+	return gameRules
+}
+
 // PutPackInfo writes the info of an array of resource pack entries.
 // The UUID, version and pack size gets written.
 func (stream *MinecraftStream) PutPackInfo(packs []types.ResourcePackInfoEntry) {
@@ -394,6 +439,26 @@ func (stream *MinecraftStream) PutPackInfo(packs []types.ResourcePackInfoEntry)
 	}
 }
 
+// GetPackInfo reads the info of an array of resource pack entries.
+func (stream *MinecraftStream) GetPackInfo() []types.ResourcePackInfoEntry {
+	var count = stream.GetLittleShort()
+	if count < 0 {
+		stream.Fail(InvalidLength)
+		return nil
+	}
+	var packs = make([]types.ResourcePackInfoEntry, 0, count)
+
+	for i := int16(0); i < count; i++ {
+		var pack = types.ResourcePackInfoEntry{UUID: stream.GetString(), Version: stream.GetString(), PackSize: stream.GetLittleLong()}
+		stream.GetString()
+		stream.GetString()
+		stream.GetString()
+		stream.GetBool()
+		packs = append(packs, pack)
+	}
+	return packs
+}
+
 // PutPackStack writes an array of resource pack entries.
 // The order of this array specifies the order the client should apply those,
 // with index 0 meaning highest priority.
@@ -406,6 +471,19 @@ func (stream *MinecraftStream) PutPackStack(packs []types.ResourcePackStackEntry
 	}
 }
 
+// GetPackStack reads an array of resource pack entries.
+func (stream *MinecraftStream) GetPackStack() []types.ResourcePackStackEntry {
+	var count = stream.Count(3)
+	var packs = make([]types.ResourcePackStackEntry, 0, count)
+
+	for i := uint32(0); i < count; i++ {
+		var pack = types.ResourcePackStackEntry{UUID: stream.GetString(), Version: stream.GetString()}
+		stream.GetString()
+		packs = append(packs, pack)
+	}
+	return packs
+}
+
 // PutUUID writes a UUID.
 // UUIDs are first re-ordered for little endian byte order,
 // after which they get written.
@@ -424,5 +502,9 @@ func (stream *MinecraftStream) PutUUID(uuid uuid.UUID) {
 // GetUUID reads a UUID.
 // TODO: Re-order for little endian byte order. Order gets messed up.
 func (stream *MinecraftStream) GetUUID() uuid.UUID {
-	return uuid.Must(uuid.FromBytes(stream.Get(16)))
+	var b = stream.Get(16)
+	if b == nil {
+		return uuid.UUID{}
+	}
+	return uuid.Must(uuid.FromBytes(b))
 }
@@ -0,0 +1,207 @@
+package packets
+
+import (
+	"errors"
+	"fmt"
+)
+
+// UnexpectedEnd gets returned when a packet was read beyond the end of its buffer.
+var UnexpectedEnd = errors.New("unexpected end of packet")
+
+// VarIntOverflow gets returned when a variable length integer was longer than its type permits.
+var VarIntOverflow = errors.New("variable length integer overflows")
+
+// InvalidLength gets returned when a length or count read from a packet is negative or unreasonably large.
+var InvalidLength = errors.New("invalid length")
+
+// Error returns the first error that occurred while reading from the stream.
+// Once an error occurred, every following read returns a zero value,
+// so decoders may read all their fields and only check the error once at the end.
+func (stream *MinecraftStream) Error() error {
+	return stream.err
+}
+
+// Fail sets the error of the stream, if no error occurred yet.
+// Decoders may use this to reject values that are read successfully, but are invalid.
+func (stream *MinecraftStream) Fail(err error) {
+	if stream.err == nil {
+		stream.err = err
+	}
+}
+
+// Failf sets the error of the stream to a formatted error, if no error occurred yet.
+func (stream *MinecraftStream) Failf(format string, a ...interface{}) {
+	stream.Fail(fmt.Errorf(format, a...))
+}
+
+// SetBuffer sets the buffer of the stream, and clears its error.
+func (stream *MinecraftStream) SetBuffer(buffer []byte) {
+	stream.Stream.SetBuffer(buffer)
+	stream.err = nil
+}
+
+// ResetStream resets the buffer and offset of the stream, and clears its error.
+func (stream *MinecraftStream) ResetStream() {
+	stream.Stream.ResetStream()
+	stream.err = nil
+}
+
+// Remaining returns the amount of bytes left to read in the stream.
+func (stream *MinecraftStream) Remaining() int {
+	if stream.Offset >= len(stream.Buffer) {
+		return 0
+	}
+	return len(stream.Buffer) - stream.Offset
+}
+
+// has checks if n more bytes can be read from the stream.
+// The error of the stream gets set if not.
+func (stream *MinecraftStream) has(n int) bool {
+	if stream.err != nil {
+		return false
+	}
+	if n < 0 {
+		stream.err = InvalidLength
+		return false
+	}
+	if stream.Remaining() < n {
+		stream.err = UnexpectedEnd
+		return false
+	}
+	return true
+}
+
+// Count reads an unsigned variable length integer count of elements,
+// of which every element takes at least minimumSize bytes.
+// A count that can not possibly fit in the rest of the stream sets the error of the stream,
+// so that decoders never allocate or loop based on a forged count.
+func (stream *MinecraftStream) Count(minimumSize int) uint32 {
+	var count = stream.GetUnsignedVarInt()
+	if minimumSize < 1 {
+		minimumSize = 1
+	}
+	if uint64(count)*uint64(minimumSize) > uint64(stream.Remaining()) {
+		stream.Fail(InvalidLength)
+		return 0
+	}
+	return count
+}
+
+// Get reads n bytes from the stream.
+func (stream *MinecraftStream) Get(n int) []byte {
+	if !stream.has(n) {
+		return nil
+	}
+	return stream.Stream.Get(n)
+}
+
+// GetBool reads a bool from the stream.
+func (stream *MinecraftStream) GetBool() bool {
+	if !stream.has(1) {
+		return false
+	}
+	return stream.Stream.GetBool()
+}
+
+// GetByte reads a byte from the stream.
+func (stream *MinecraftStream) GetByte() byte {
+	if !stream.has(1) {
+		return 0
+	}
+	return stream.Stream.GetByte()
+}
+
+// GetLittleShort reads a little endian int16 from the stream.
+func (stream *MinecraftStream) GetLittleShort() int16 {
+	if !stream.has(2) {
+		return 0
+	}
+	return stream.Stream.GetLittleShort()
+}
+
+// GetInt reads a big endian int32 from the stream.
+func (stream *MinecraftStream) GetInt() int32 {
+	if !stream.has(4) {
+		return 0
+	}
+	return stream.Stream.GetInt()
+}
+
+// GetLittleInt reads a little endian int32 from the stream.
+func (stream *MinecraftStream) GetLittleInt() int32 {
+	if !stream.has(4) {
+		return 0
+	}
+	return stream.Stream.GetLittleInt()
+}
+
+// GetLittleLong reads a little endian int64 from the stream.
+func (stream *MinecraftStream) GetLittleLong() int64 {
+	if !stream.has(8) {
+		return 0
+	}
+	return stream.Stream.GetLittleLong()
+}
+
+// GetLittleFloat reads a little endian float32 from the stream.
+func (stream *MinecraftStream) GetLittleFloat() float32 {
+	if !stream.has(4) {
+		return 0
+	}
+	return stream.Stream.GetLittleFloat()
+}
+
+// GetUnsignedVarInt reads an unsigned variable length int32 from the stream.
+func (stream *MinecraftStream) GetUnsignedVarInt() uint32 {
+	return uint32(stream.getVarUint(5))
+}
+
+// GetVarInt reads a zigzag encoded variable length int32 from the stream.
+func (stream *MinecraftStream) GetVarInt() int32 {
+	var value = uint32(stream.getVarUint(5))
+	return int32(value>>1) ^ -int32(value&1)
+}
+
+// GetUnsignedVarLong reads an unsigned variable length int64 from the stream.
+func (stream *MinecraftStream) GetUnsignedVarLong() uint64 {
+	return stream.getVarUint(10)
+}
+
+// GetVarLong reads a zigzag encoded variable length int64 from the stream.
+func (stream *MinecraftStream) GetVarLong() int64 {
+	var value = stream.getVarUint(10)
+	return int64(value>>1) ^ -int64(value&1)
+}
+
+// getVarUint reads a variable length integer of at most maximumBytes bytes.
+func (stream *MinecraftStream) getVarUint(maximumBytes int) uint64 {
+	var value uint64
+	for i := 0; i < maximumBytes; i++ {
+		if !stream.has(1) {
+			return 0
+		}
+		var b = stream.Buffer[stream.Offset]
+		stream.Offset++
+		value |= uint64(b&0x7f) << uint(7*i)
+		if b&0x80 == 0 {
+			return value
+		}
+	}
+	stream.Fail(VarIntOverflow)
+	return 0
+}
+
+// GetString reads a string prefixed with an unsigned variable length int32 from the stream.
+func (stream *MinecraftStream) GetString() string {
+	return string(stream.GetLengthPrefixedBytes())
+}
+
+// GetLengthPrefixedBytes reads a byte array prefixed with an unsigned variable length int32 from the stream.
+func (stream *MinecraftStream) GetLengthPrefixedBytes() []byte {
+	var length = stream.GetUnsignedVarInt()
+	if uint64(length) > uint64(stream.Remaining()) {
+		stream.Fail(UnexpectedEnd)
+		return nil
+	}
+	return stream.Get(int(length))
+}
@@ -1,7 +1,7 @@
 package packets
 
 import (
-	"github.com/irmine/gomine/text"
+	"fmt"
 )
 
 // IPacket gets implemented by every packet.
@@ -11,15 +11,15 @@ type IPacket interface {
 	GetBuffer() []byte
 	EncodeHeader()
 	Encode()
-	DecodeHeader()
-	Decode()
+	DecodeHeader() error
+	Decode() error
 	ResetStream()
 	GetOffset() int
 	SetOffset(int)
 	Discard()
 	IsDiscarded() bool
 	EncodeId()
-	DecodeId()
+	DecodeId() error
 	GetId() int
 }
 
@@ -74,13 +74,17 @@ func (pk *Packet) EncodeId() {
 }
 
 // DecodeId decodes the packet ID of the packet.
-// The function panics if the packet ID
+// An error is returned if the packet ID
 // and read ID do not match.
-func (pk *Packet) DecodeId() {
+func (pk *Packet) DecodeId() error {
 	id := int(pk.GetUnsignedVarInt())
+	if err := pk.Error(); err != nil {
+		return err
+	}
 	if id != pk.PacketId {
-		text.DefaultLogger.Debug("Packet IDs do not match. Expected:", pk.PacketId, "Got:", id)
+		return fmt.Errorf("packet IDs do not match: expected %v, got %v", pk.PacketId, id)
 	}
+	return nil
 }
 
 // EncodeHeader encodes the header of a packet,
@@ -95,10 +99,12 @@ func (pk *Packet) EncodeHeader() {
 // with bedrock >= 200.
 // First the packet ID gets decoded,
 // after which the sender and receiver ID bytes.
-func (pk *Packet) DecodeHeader() {
-	pk.DecodeId()
+func (pk *Packet) DecodeHeader() error {
+	return pk.DecodeId()
 }
 
 func (pk *Packet) Encode() {}
 
-func (pk *Packet) Decode() {}
+func (pk *Packet) Decode() error {
+	return pk.Error()
+}
@@ -0,0 +1,33 @@
+package types
+
+// CommandEnum is a named list of values a command parameter accepts.
+type CommandEnum struct {
+	Name   string
+	Values []string
+}
+
+// CommandParameter is a parameter of a command overload.
+// Parameters with an enum accept the values of the enum,
+// other parameters accept values of their type, one of the data.CommandArg types.
+type CommandParameter struct {
+	Name     string
+	Type     uint32
+	Optional bool
+	Enum     *CommandEnum
+}
+
+// CommandOverload is a list of parameters a command may be executed with.
+type CommandOverload struct {
+	Parameters []CommandParameter
+}
+
+// CommandData is a command as shown to the client for autocompletion.
+// The aliases enum holds the name and the aliases of the command, and is nil for commands without aliases.
+type CommandData struct {
+	Name        string
+	Description string
+	Flags       byte
+	Permission  byte
+	Aliases     *CommandEnum
+	Overloads   []CommandOverload
+}
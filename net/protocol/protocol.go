@@ -13,11 +13,16 @@ import (
 )
 
 type IPacketManager interface {
+	GetProtocolNumber() int32
 	GetIdList() info.PacketIdList
 	GetHandlers(packet info.PacketName) [][]Handler
 	GetHandlersById(id int) [][]Handler
 	RegisterHandler(packet info.PacketName, handler Handler) bool
 	DeregisterPacketHandlers(packet info.PacketName, priority int)
+	GetInterceptors(packet info.PacketName) [][]Handler
+	GetInterceptorsById(id int) [][]Handler
+	RegisterInterceptor(packet info.PacketName, interceptor Handler) bool
+	DeregisterPacketInterceptors(packet info.PacketName, priority int)
 	GetPackets() map[int]func() packets.IPacket
 	RegisterPacket(packetId int, packetFunc func() packets.IPacket)
 	GetPacket(packetId int) packets.IPacket
@@ -25,6 +30,7 @@ type IPacketManager interface {
 
 	GetAddEntity(AddEntityEntry) packets.IPacket
 	GetAddPlayer(uuid.UUID, AddPlayerEntry) packets.IPacket
+	GetAvailableCommands([]types.CommandData) packets.IPacket
 	GetChunkRadiusUpdated(int32) packets.IPacket
 	GetCraftingData() packets.IPacket
 	GetDisconnect(string, bool) packets.IPacket
@@ -54,14 +60,21 @@ type IPacketManager interface {
 // PacketManagerBase is a struct providing the base for a PacketManagerBase.
 // It provides utility functions for a basic PacketManagerBase implementation.
 type PacketManagerBase struct {
+	protocolNumber int32
 	idList         info.PacketIdList
 	packets        map[int]func() packets.IPacket
 	handlers       map[int][][]Handler
+	interceptors   map[int][][]Handler
 }
 
-// NewBase returns a new PacketManagerBase with the given PacketManagerBase number and packets.
-func NewPacketManagerBase(idList info.PacketIdList, packets map[int]func() packets.IPacket, handlers map[int][][]Handler) *PacketManagerBase {
-	return &PacketManagerBase{idList, packets, handlers}
+// NewBase returns a new PacketManagerBase with the given protocol number and packets.
+func NewPacketManagerBase(protocolNumber int32, idList info.PacketIdList, packets map[int]func() packets.IPacket, handlers map[int][][]Handler) *PacketManagerBase {
+	return &PacketManagerBase{protocolNumber, idList, packets, handlers, make(map[int][][]Handler)}
+}
+
+// GetProtocolNumber returns the protocol number the packet manager implements.
+func (Base *PacketManagerBase) GetProtocolNumber() int32 {
+	return Base.protocolNumber
 }
 
 // GetIdList returns the packet name => Id list of the bedrock.
@@ -95,9 +108,44 @@ func (Base *PacketManagerBase) RegisterHandler(packet info.PacketName, handler H
 // DeregisterPackHandlers deregisters all packet handlers listening for packets with the given ID, on the given priority.
 func (Base *PacketManagerBase) DeregisterPacketHandlers(packet info.PacketName, priority int) {
 	var id = Base.idList[packet]
+	if Base.handlers[id] == nil {
+		return
+	}
 	Base.handlers[id][priority] = []Handler{}
 }
 
+// GetInterceptors returns all outbound interceptors registered for the given packet name.
+func (Base *PacketManagerBase) GetInterceptors(packet info.PacketName) [][]Handler {
+	var id = Base.idList[packet]
+	return Base.interceptors[id]
+}
+
+// GetInterceptorsById returns all outbound interceptors registered on the given ID.
+func (Base *PacketManagerBase) GetInterceptorsById(id int) [][]Handler {
+	return Base.interceptors[id]
+}
+
+// RegisterInterceptor registers a new interceptor to intercept outbound packets with the given ID.
+// This function uses the priority of the interceptor.
+// Returns a bool indicating success.
+func (Base *PacketManagerBase) RegisterInterceptor(packet info.PacketName, interceptor Handler) bool {
+	var id = Base.idList[packet]
+	if Base.interceptors[id] == nil {
+		Base.interceptors[id] = make([][]Handler, 11)
+	}
+	Base.interceptors[id][interceptor.GetPriority()] = append(Base.interceptors[id][interceptor.GetPriority()], interceptor)
+	return true
+}
+
+// DeregisterPacketInterceptors deregisters all interceptors intercepting packets with the given ID, on the given priority.
+func (Base *PacketManagerBase) DeregisterPacketInterceptors(packet info.PacketName, priority int) {
+	var id = Base.idList[packet]
+	if Base.interceptors[id] == nil {
+		return
+	}
+	Base.interceptors[id][priority] = []Handler{}
+}
+
 // GetPackets returns a packet ID => packet function map containing all registered packets.
 func (Base *PacketManagerBase) GetPackets() map[int]func() packets.IPacket {
 	return Base.packets
@@ -0,0 +1,133 @@
+package protocol
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/irmine/gomine/net/info"
+)
+
+// Registry is a struct managing all protocols supported by the server.
+// Every protocol is an IPacketManager, registered by its protocol number.
+// Sessions get bound to the matching packet manager once they log in,
+// allowing clients of different versions to play on the same server.
+type Registry struct {
+	mutex     sync.RWMutex
+	protocols map[int32]IPacketManager
+	latest    IPacketManager
+}
+
+// NewRegistry returns a new protocol registry without any protocols.
+func NewRegistry() *Registry {
+	return &Registry{sync.RWMutex{}, make(map[int32]IPacketManager), nil}
+}
+
+// RegisterProtocol registers a packet manager for the protocol number it implements.
+// Protocols previously registered with the same protocol number get overwritten.
+func (registry *Registry) RegisterProtocol(manager IPacketManager) {
+	registry.mutex.Lock()
+	registry.protocols[manager.GetProtocolNumber()] = manager
+	if registry.latest == nil || manager.GetProtocolNumber() >= registry.latest.GetProtocolNumber() {
+		registry.latest = manager
+	}
+	registry.mutex.Unlock()
+}
+
+// DeregisterProtocol deregisters the protocol with the given protocol number.
+// Returns a bool indicating if a protocol was deregistered.
+func (registry *Registry) DeregisterProtocol(protocolNumber int32) bool {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	if _, ok := registry.protocols[protocolNumber]; !ok {
+		return false
+	}
+	delete(registry.protocols, protocolNumber)
+
+	registry.latest = nil
+	for _, manager := range registry.protocols {
+		if registry.latest == nil || manager.GetProtocolNumber() > registry.latest.GetProtocolNumber() {
+			registry.latest = manager
+		}
+	}
+	return true
+}
+
+// GetProtocol returns the packet manager implementing the given protocol number.
+// A bool is returned indicating success.
+func (registry *Registry) GetProtocol(protocolNumber int32) (IPacketManager, bool) {
+	registry.mutex.RLock()
+	var manager, ok = registry.protocols[protocolNumber]
+	registry.mutex.RUnlock()
+	return manager, ok
+}
+
+// IsProtocolSupported checks if a protocol with the given protocol number is registered.
+func (registry *Registry) IsProtocolSupported(protocolNumber int32) bool {
+	var _, ok = registry.GetProtocol(protocolNumber)
+	return ok
+}
+
+// GetLatestProtocol returns the packet manager with the highest protocol number.
+// Sessions use this packet manager until they are bound to their own protocol.
+func (registry *Registry) GetLatestProtocol() IPacketManager {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+	return registry.latest
+}
+
+// GetProtocols returns a protocol number => packet manager map of all registered protocols.
+func (registry *Registry) GetProtocols() map[int32]IPacketManager {
+	registry.mutex.RLock()
+	var protocols = make(map[int32]IPacketManager, len(registry.protocols))
+	for protocolNumber, manager := range registry.protocols {
+		protocols[protocolNumber] = manager
+	}
+	registry.mutex.RUnlock()
+	return protocols
+}
+
+// GetProtocolNumbers returns all registered protocol numbers in ascending order.
+func (registry *Registry) GetProtocolNumbers() []int32 {
+	registry.mutex.RLock()
+	var numbers = make([]int32, 0, len(registry.protocols))
+	for protocolNumber := range registry.protocols {
+		numbers = append(numbers, protocolNumber)
+	}
+	registry.mutex.RUnlock()
+
+	sort.Slice(numbers, func(i, j int) bool {
+		return numbers[i] < numbers[j]
+	})
+	return numbers
+}
+
+// RegisterHandler registers a packet handler on every registered protocol.
+// Plugins should use this function over registering handlers on a single packet manager,
+// in order to handle packets of clients of every supported version.
+func (registry *Registry) RegisterHandler(packet info.PacketName, handler Handler) {
+	for _, manager := range registry.GetProtocols() {
+		manager.RegisterHandler(packet, handler)
+	}
+}
+
+// DeregisterPacketHandlers deregisters all packet handlers on the given priority of every registered protocol.
+func (registry *Registry) DeregisterPacketHandlers(packet info.PacketName, priority int) {
+	for _, manager := range registry.GetProtocols() {
+		manager.DeregisterPacketHandlers(packet, priority)
+	}
+}
+
+// RegisterInterceptor registers an outbound packet interceptor on every registered protocol.
+func (registry *Registry) RegisterInterceptor(packet info.PacketName, interceptor Handler) {
+	for _, manager := range registry.GetProtocols() {
+		manager.RegisterInterceptor(packet, interceptor)
+	}
+}
+
+// DeregisterPacketInterceptors deregisters all interceptors on the given priority of every registered protocol.
+func (registry *Registry) DeregisterPacketInterceptors(packet info.PacketName, priority int) {
+	for _, manager := range registry.GetProtocols() {
+		manager.DeregisterPacketInterceptors(packet, priority)
+	}
+}
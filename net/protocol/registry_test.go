@@ -0,0 +1,93 @@
+package protocol
+
+import (
+	"testing"
+
+	"github.com/irmine/gomine/net/info"
+	"github.com/irmine/gomine/net/packets"
+)
+
+// unimplementedManager leaves all packet functions of a packet manager unimplemented.
+type unimplementedManager struct {
+	IPacketManager
+}
+
+// testManager is a packet manager that only keeps track of handlers and interceptors.
+type testManager struct {
+	*PacketManagerBase
+	unimplementedManager
+}
+
+// newTestManager returns a new test packet manager implementing the given protocol number.
+func newTestManager(protocolNumber int32) *testManager {
+	return &testManager{PacketManagerBase: NewPacketManagerBase(protocolNumber, info.PacketIds, make(map[int]func() packets.IPacket), make(map[int][][]Handler))}
+}
+
+// testHandler is a handler that only has a priority.
+type testHandler struct {
+	priority int
+}
+
+func (handler *testHandler) GetPriority() int {
+	return handler.priority
+}
+
+func (handler *testHandler) SetPriority(priority int) bool {
+	handler.priority = priority
+	return true
+}
+
+func TestRegistryProtocols(t *testing.T) {
+	var registry = NewRegistry()
+	var newer, older = newTestManager(361), newTestManager(354)
+	registry.RegisterProtocol(newer)
+	registry.RegisterProtocol(older)
+
+	for _, manager := range []*testManager{newer, older} {
+		if found, ok := registry.GetProtocol(manager.GetProtocolNumber()); !ok || found != IPacketManager(manager) {
+			t.Errorf("protocol %v did not resolve to its packet manager", manager.GetProtocolNumber())
+		}
+	}
+	if registry.IsProtocolSupported(340) {
+		t.Error("unregistered protocol 340 is supported")
+	}
+	if numbers := registry.GetProtocolNumbers(); len(numbers) != 2 || numbers[0] != 354 || numbers[1] != 361 {
+		t.Errorf("got protocol numbers %v, expected [354 361]", numbers)
+	}
+	if latest := registry.GetLatestProtocol(); latest != IPacketManager(newer) {
+		t.Errorf("latest protocol is %v, expected 361", latest.GetProtocolNumber())
+	}
+
+	registry.DeregisterProtocol(361)
+	if latest := registry.GetLatestProtocol(); latest != IPacketManager(older) {
+		t.Errorf("latest protocol is %v after deregistering 361, expected 354", latest.GetProtocolNumber())
+	}
+}
+
+func TestRegistryHandlers(t *testing.T) {
+	var registry = NewRegistry()
+	var managers = []*testManager{newTestManager(354), newTestManager(361)}
+	for _, manager := range managers {
+		registry.RegisterProtocol(manager)
+	}
+	var handler, interceptor = &testHandler{priority: 3}, &testHandler{priority: 5}
+	registry.RegisterHandler(info.TextPacket, handler)
+	registry.RegisterInterceptor(info.TextPacket, interceptor)
+
+	for _, manager := range managers {
+		if handlers := manager.GetHandlers(info.TextPacket); len(handlers) == 0 || len(handlers[3]) != 1 || handlers[3][0] != Handler(handler) {
+			t.Errorf("handler was not registered on protocol %v", manager.GetProtocolNumber())
+		}
+		if interceptors := manager.GetInterceptors(info.TextPacket); len(interceptors) == 0 || len(interceptors[5]) != 1 || interceptors[5][0] != Handler(interceptor) {
+			t.Errorf("interceptor was not registered on protocol %v", manager.GetProtocolNumber())
+		}
+	}
+
+	registry.DeregisterPacketHandlers(info.TextPacket, 3)
+	registry.DeregisterPacketInterceptors(info.TextPacket, 5)
+	for _, manager := range managers {
+		if len(manager.GetHandlers(info.TextPacket)[3]) != 0 || len(manager.GetInterceptors(info.TextPacket)[5]) != 0 {
+			t.Errorf("handlers of protocol %v were not deregistered", manager.GetProtocolNumber())
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package net
+
+import (
+	"net"
+	"sync"
+
+	"github.com/irmine/goraklib/protocol"
+	"github.com/irmine/goraklib/server"
+)
+
+// RakNetTransport is the transport adapting to a GoRakLib server.
+// Every GoRakLib session gets wrapped in a RakNetConnection.
+type RakNetTransport struct {
+	manager *server.Manager
+
+	mutex       sync.RWMutex
+	connections map[*server.Session]*RakNetConnection
+
+	connectFunction    func(connection Connection)
+	disconnectFunction func(connection Connection)
+}
+
+// RakNetConnection is a connection of the RakNet transport.
+type RakNetConnection struct {
+	session *server.Session
+}
+
+// NewRakNetTransport returns a new RakNet transport with a new GoRakLib manager.
+func NewRakNetTransport() *RakNetTransport {
+	var transport = &RakNetTransport{manager: server.NewManager(), connections: make(map[*server.Session]*RakNetConnection)}
+	transport.manager.ConnectFunction = func(session *server.Session) {
+		var connection = transport.getConnection(session)
+		if transport.connectFunction != nil {
+			transport.connectFunction(connection)
+		}
+	}
+	transport.manager.DisconnectFunction = func(session *server.Session) {
+		var connection = transport.getConnection(session)
+		transport.mutex.Lock()
+		delete(transport.connections, session)
+		transport.mutex.Unlock()
+
+		if transport.disconnectFunction != nil {
+			transport.disconnectFunction(connection)
+		}
+	}
+	return transport
+}
+
+// GetRakLibManager returns the GoRakLib manager of the transport.
+func (transport *RakNetTransport) GetRakLibManager() *server.Manager {
+	return transport.manager
+}
+
+// Start starts the GoRakLib manager on the given address and port.
+func (transport *RakNetTransport) Start(address string, port uint16) error {
+	return transport.manager.Start(address, int(port))
+}
+
+// Stop stops the GoRakLib manager.
+func (transport *RakNetTransport) Stop() {
+	transport.manager.Stop()
+}
+
+// GetServerId returns the server ID of the GoRakLib manager.
+func (transport *RakNetTransport) GetServerId() int64 {
+	return int64(transport.manager.ServerId)
+}
+
+// SetPongData sets the pong data of the GoRakLib manager.
+func (transport *RakNetTransport) SetPongData(data string) {
+	transport.manager.PongData = data
+}
+
+// SetPacketFunction sets the function called for every batch received.
+func (transport *RakNetTransport) SetPacketFunction(function func(packet []byte, connection Connection)) {
+	transport.manager.PacketFunction = func(packet []byte, session *server.Session) {
+		function(packet, transport.getConnection(session))
+	}
+}
+
+// SetConnectFunction sets the function called once a GoRakLib session connected.
+func (transport *RakNetTransport) SetConnectFunction(function func(connection Connection)) {
+	transport.connectFunction = function
+}
+
+// SetDisconnectFunction sets the function called once a GoRakLib session disconnected.
+func (transport *RakNetTransport) SetDisconnectFunction(function func(connection Connection)) {
+	transport.disconnectFunction = function
+}
+
+// SetRawPacketFunction sets the function called for raw packets, such as query packets.
+func (transport *RakNetTransport) SetRawPacketFunction(function func(packet []byte, addr *net.UDPAddr)) {
+	transport.manager.RawPacketFunction = function
+}
+
+// GetConnection returns the connection of the GoRakLib session with the given address and port.
+// A bool is returned indicating success.
+func (transport *RakNetTransport) GetConnection(address string, port uint16) (*RakNetConnection, bool) {
+	var session, ok = transport.manager.Sessions.GetSession(&net.UDPAddr{IP: net.ParseIP(address), Port: int(port)})
+	if !ok {
+		return nil, false
+	}
+	return transport.getConnection(session), true
+}
+
+// getConnection returns the connection wrapping the given GoRakLib session.
+// A new connection is created if the session did not yet have one.
+func (transport *RakNetTransport) getConnection(session *server.Session) *RakNetConnection {
+	transport.mutex.RLock()
+	var connection, ok = transport.connections[session]
+	transport.mutex.RUnlock()
+	if ok {
+		return connection
+	}
+
+	transport.mutex.Lock()
+	defer transport.mutex.Unlock()
+	if connection, ok = transport.connections[session]; !ok {
+		connection = &RakNetConnection{session}
+		transport.connections[session] = connection
+	}
+	return connection
+}
+
+// GetSession returns the GoRakLib session of the connection.
+func (connection *RakNetConnection) GetSession() *server.Session {
+	return connection.session
+}
+
+// SendBatch sends the batch reliable ordered, as encrypted batches must arrive in order.
+func (connection *RakNetConnection) SendBatch(batch *MinecraftPacketBatch) {
+	connection.session.SendPacket(batch, protocol.ReliabilityReliableOrdered, server.PriorityMedium)
+}
+
+// GetPing returns the current ping of the GoRakLib session.
+func (connection *RakNetConnection) GetPing() int64 {
+	return connection.session.CurrentPing
+}
+
+// Close closes the GoRakLib session. Batches sent before closing are still delivered.
+func (connection *RakNetConnection) Close() error {
+	connection.session.Close()
+	return nil
+}
+
+// GetAddress returns the address of the GoRakLib session.
+func (connection *RakNetConnection) GetAddress() *net.UDPAddr {
+	return connection.session.UDPAddr
+}
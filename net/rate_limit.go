@@ -0,0 +1,94 @@
+package net
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/irmine/gomine/net/info"
+)
+
+// RateLimitAction is the action taken when a session exceeds the rate limit of a packet.
+type RateLimitAction string
+
+const (
+	// RateLimitDrop drops packets exceeding the rate limit, without handling them.
+	RateLimitDrop RateLimitAction = "drop"
+	// RateLimitKick kicks sessions exceeding the rate limit.
+	RateLimitKick RateLimitAction = "kick"
+	// RateLimitBan kicks sessions exceeding the rate limit, and temporarily bans their IP.
+	RateLimitBan RateLimitAction = "ban"
+)
+
+// RateLimit is the budget of packets of a single type a session may send.
+// Sessions may send Burst packets at once, after which their budget refills with Rate packets per second.
+type RateLimit struct {
+	Rate   float64
+	Burst  float64
+	Action RateLimitAction
+}
+
+// validate checks if the rate limit is usable, and returns an error if not.
+func (limit RateLimit) validate() error {
+	if limit.Rate <= 0 || limit.Burst < 1 {
+		return fmt.Errorf("rate must be positive and burst must be at least 1, got rate %v and burst %v", limit.Rate, limit.Burst)
+	}
+	switch limit.Action {
+	case RateLimitDrop, RateLimitKick, RateLimitBan:
+		return nil
+	}
+	return fmt.Errorf("unknown rate limit action %q", limit.Action)
+}
+
+// TokenBucket is a token bucket limiting the rate of packets of a single type of a session.
+type TokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucket returns a new full token bucket,
+// which holds burst tokens and refills at rate tokens per second.
+func NewTokenBucket(rate, burst float64) *TokenBucket {
+	return &TokenBucket{rate, burst, burst, time.Now()}
+}
+
+// Take takes a token from the bucket at the given time.
+// Returns false if the bucket was empty, in which case no token was taken.
+func (bucket *TokenBucket) Take(now time.Time) bool {
+	if elapsed := now.Sub(bucket.last).Seconds(); elapsed > 0 {
+		bucket.tokens += elapsed * bucket.rate
+		if bucket.tokens > bucket.burst {
+			bucket.tokens = bucket.burst
+		}
+	}
+	bucket.last = now
+
+	if bucket.tokens < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
+
+// GetTokens returns the amount of tokens currently left in the bucket.
+func (bucket *TokenBucket) GetTokens() float64 {
+	return bucket.tokens
+}
+
+// buildRateLimits converts rate limits keyed by packet name to rate limits keyed by packet ID.
+// An error is returned for unknown packet names or invalid rate limits.
+func buildRateLimits(limits map[info.PacketName]RateLimit) (map[int]RateLimit, error) {
+	var byId = make(map[int]RateLimit, len(limits))
+	for name, limit := range limits {
+		var id, ok = info.PacketIds[name]
+		if !ok {
+			return nil, fmt.Errorf("rate limit for unknown packet %v", name)
+		}
+		if err := limit.validate(); err != nil {
+			return nil, fmt.Errorf("rate limit for %v: %v", name, err)
+		}
+		byId[id] = limit
+	}
+	return byId, nil
+}
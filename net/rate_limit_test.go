@@ -0,0 +1,59 @@
+package net
+
+import (
+	"testing"
+	"time"
+
+	"github.com/irmine/gomine/net/info"
+)
+
+func TestTokenBucket(t *testing.T) {
+	var bucket = NewTokenBucket(10, 5)
+	var now = bucket.last
+
+	for i := 0; i < 5; i++ {
+		if !bucket.Take(now) {
+			t.Fatalf("token %v of the burst was not available", i)
+		}
+	}
+	if bucket.Take(now) {
+		t.Fatal("took a token beyond the burst")
+	}
+
+	now = now.Add(time.Second / 10)
+	if !bucket.Take(now) {
+		t.Fatal("bucket did not refill after 100ms at 10 tokens per second")
+	}
+	if bucket.Take(now) {
+		t.Fatal("bucket refilled more than 1 token after 100ms at 10 tokens per second")
+	}
+
+	now = now.Add(time.Hour)
+	bucket.Take(now)
+	if tokens := bucket.GetTokens(); tokens != 4 {
+		t.Fatalf("bucket holds %v tokens after refilling, expected burst minus 1", tokens)
+	}
+}
+
+func TestBuildRateLimits(t *testing.T) {
+	var limits, err = buildRateLimits(map[info.PacketName]RateLimit{
+		info.TextPacket: {Rate: 5, Burst: 10, Action: RateLimitKick},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if limit, ok := limits[info.PacketIds[info.TextPacket]]; !ok || limit.Action != RateLimitKick {
+		t.Fatalf("rate limit of text packet not found by ID, got %v", limits)
+	}
+
+	var invalid = []map[info.PacketName]RateLimit{
+		{"UnknownPacket": {Rate: 5, Burst: 10, Action: RateLimitDrop}},
+		{info.TextPacket: {Rate: 5, Burst: 10, Action: "explode"}},
+		{info.TextPacket: {Rate: 0, Burst: 10, Action: RateLimitDrop}},
+	}
+	for _, limits := range invalid {
+		if _, err := buildRateLimits(limits); err == nil {
+			t.Errorf("building invalid rate limits %v succeeded", limits)
+		}
+	}
+}
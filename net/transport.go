@@ -0,0 +1,45 @@
+package net
+
+import (
+	"net"
+)
+
+// Transport is the interface satisfied by every network backend of the network adapter.
+// A transport accepts client connections and passes the raw batches received from them
+// to the packet function, after which the network adapter handles the batches.
+// RakNet is the transport used by default, but batches may be exchanged in any way.
+type Transport interface {
+	// Start starts listening for connections on the given address and port.
+	Start(address string, port uint16) error
+	// Stop stops the transport and closes all connections.
+	Stop()
+	// GetServerId returns the ID of the server broadcast in the pong data.
+	GetServerId() int64
+	// SetPongData sets the data sent to clients pinging the server.
+	SetPongData(data string)
+	// SetPacketFunction sets the function called for every batch received from a connection.
+	SetPacketFunction(function func(packet []byte, connection Connection))
+	// SetConnectFunction sets the function called once a new connection got established.
+	SetConnectFunction(function func(connection Connection))
+	// SetDisconnectFunction sets the function called once a connection disconnected.
+	SetDisconnectFunction(function func(connection Connection))
+	// SetRawPacketFunction sets the function called for raw packets not belonging to a connection,
+	// such as query packets.
+	SetRawPacketFunction(function func(packet []byte, addr *net.UDPAddr))
+}
+
+// Connection is a single client connection of a transport.
+// Every Minecraft session sends its batches through the connection it was created with.
+type Connection interface {
+	// SendBatch sends the batch to the client of the connection.
+	// Sessions encode batches before sending them, but transports should still encode the batch,
+	// as batches may be sent without a session. Encoding a batch more than once has no effect.
+	SendBatch(batch *MinecraftPacketBatch)
+	// GetPing returns the latency of the connection in milliseconds.
+	GetPing() int64
+	// GetAddress returns the address of the client of the connection.
+	GetAddress() *net.UDPAddr
+	// Close closes the connection, after which no more batches of it are passed to the packet function.
+	// The disconnect function of the transport gets called once the connection is closed.
+	Close() error
+}
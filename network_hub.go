@@ -0,0 +1,57 @@
+package gomine
+
+import (
+	"github.com/irmine/gomine/hub"
+	"github.com/irmine/gomine/net"
+)
+
+// newHub returns a new hub for the network of servers in the hub configuration of the server.
+func newHub(server *Server) *hub.Hub {
+	var config = server.Config.Hub
+	var servers []hub.Server
+	for name, s := range config.Servers {
+		servers = append(servers, hub.Server{Name: name, Address: s.Address, Port: s.Port, ControlAddress: s.ControlAddress})
+	}
+	return hub.New(config.ServerName, servers, func() hub.Status {
+		return hub.Status{Name: config.ServerName, OnlinePlayers: server.SessionManager.GetSessionCount(), MaximumPlayers: int(server.Config.MaximumPlayers)}
+	})
+}
+
+// GetHub returns the hub of the network of servers this server is part of.
+// Nil is returned if the hub is not enabled in the configuration.
+func (server *Server) GetHub() *hub.Hub {
+	return server.Hub
+}
+
+// TransferPlayer transfers the player of the session to the server in the network with the given name.
+// An error is returned if the hub is not enabled, or if the server is unknown or offline.
+func (server *Server) TransferPlayer(session *net.MinecraftSession, name string) error {
+	if server.Hub == nil {
+		return hub.UnknownServer
+	}
+	var target, err = server.Hub.GetTarget(name)
+	if err != nil {
+		return err
+	}
+	server.Logger.Info(session.GetDisplayName(), "is being transferred to", name+".")
+	session.Transfer(target.Address, target.Port)
+	return nil
+}
+
+// transferToFallback transfers all players to the fallback server of the network.
+// Players are not transferred if the fallback server is this server or is offline,
+// in which case false is returned.
+func (server *Server) transferToFallback() bool {
+	var fallback = server.Config.Hub.FallbackServer
+	if server.Hub == nil || fallback == "" || fallback == server.Hub.GetName() {
+		return false
+	}
+	if _, err := server.Hub.GetTarget(fallback); err != nil {
+		server.Logger.Notice("Could not transfer players to fallback server", fallback+":", err)
+		return false
+	}
+	for _, session := range server.SessionManager.GetSessions() {
+		server.TransferPlayer(session, fallback)
+	}
+	return true
+}
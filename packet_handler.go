@@ -1,13 +1,10 @@
 package gomine
 
 import (
-	"crypto/ecdsa"
-	"crypto/sha512"
-	"crypto/x509"
-	"encoding/base64"
 	"github.com/golang/geo/r3"
+	"github.com/google/uuid"
+	"github.com/irmine/gomine/auth"
 	"github.com/irmine/gomine/net"
-	"github.com/irmine/gomine/net/info"
 	"github.com/irmine/gomine/net/packets"
 	"github.com/irmine/gomine/net/packets/bedrock"
 	"github.com/irmine/gomine/net/packets/data"
@@ -20,9 +17,7 @@ import (
 	"github.com/irmine/worlds/chunks"
 	data2 "github.com/irmine/worlds/entities/data"
 	utils2 "github.com/irmine/worlds/utils"
-	"math/big"
 	"strings"
-	"time"
 )
 
 func NewClientHandshakeHandler(server *Server) *net.PacketHandler {
@@ -39,7 +34,11 @@ func NewClientHandshakeHandler(server *Server) *net.PacketHandler {
 func NewCommandRequestHandler(server *Server) *net.PacketHandler {
 	return net.NewPacketHandler(func(packet packets.IPacket, session *net.MinecraftSession) bool {
 		if pk, ok := packet.(*bedrock.CommandRequestPacket); ok {
-			var args = strings.Split(pk.CommandText, " ")
+			var preprocess = &PlayerCommandPreprocessEvent{Session: session, CommandLine: pk.CommandText}
+			if !server.Events.Call(preprocess) {
+				return true
+			}
+			var args = strings.Split(preprocess.CommandLine, " ")
 			var commandName = strings.TrimLeft(args[0], "/")
 			var i = 1
 			for !server.CommandManager.IsCommandRegistered(commandName) {
@@ -55,6 +54,14 @@ func NewCommandRequestHandler(server *Server) *net.PacketHandler {
 			}
 			args = args[i:]
 			var command, _ = server.CommandManager.GetCommand(commandName)
+			if server.isQueued(session) {
+				server.sendQueuePosition(session)
+				return true
+			}
+			if server.IsFrozen(session) && command.GetName() != "login" && command.GetName() != "register" {
+				server.promptPasswordLogin(session)
+				return true
+			}
 			command.Execute(session, args)
 
 			return true
@@ -67,41 +74,66 @@ func NewCommandRequestHandler(server *Server) *net.PacketHandler {
 func NewLoginHandler(server *Server) *net.PacketHandler {
 	return net.NewPacketHandler(func(packet packets.IPacket, session *net.MinecraftSession) bool {
 		if loginPacket, ok := packet.(*bedrock.LoginPacket); ok {
-			var _, ok = server.SessionManager.GetSession(loginPacket.Username)
-			if ok {
-				return false
-			}
-
-			if loginPacket.Protocol > info.LatestProtocol {
-				session.Kick("Outdated server.", false, true)
+			if !server.ProtocolRegistry.IsProtocolSupported(loginPacket.Protocol) {
+				if loginPacket.Protocol > server.ProtocolRegistry.GetLatestProtocol().GetProtocolNumber() {
+					session.Kick("Outdated server.", false, true)
+				} else {
+					session.Kick("Outdated client.", false, true)
+				}
 				return false
 			}
-
-			if loginPacket.Protocol < info.LatestProtocol {
-				session.Kick("Outdated client.", false, true)
-				return false
+			if server.IsStopping() {
+				session.Kick("Server is shutting down.", false, false)
+				return true
 			}
 
-			var successful, authenticated, pubKey = VerifyLoginRequest(loginPacket.Chains, server)
-
-			if !successful {
-				text.DefaultLogger.Debug(loginPacket.Username, "has joined with invalid login data.")
+			var identity, err = server.AuthProvider.Authenticate(auth.Login{
+				Chain:      loginPacket.RawChains,
+				ClientData: loginPacket.RawClientData,
+				Claimed:    auth.ExtraData{XUID: loginPacket.ClientXUID, DisplayName: loginPacket.Username, Identity: loginPacket.ClientUUID.String()},
+				Address:    session.GetConnection().GetAddress().IP,
+			})
+			if err != nil {
+				server.Logger.Debug(loginPacket.Username, "could not be authenticated:", err)
+				session.Kick(getLoginFailureMessage(err), false, false)
 				return true
 			}
+			var authenticated, pubKey, xuid = identity.Authenticated, identity.PublicKey, identity.XUID
+			var clientUUID, _ = uuid.Parse(identity.Identity)
 
 			if authenticated {
-				text.DefaultLogger.Debug(loginPacket.Username, "has joined while being logged into XBOX Live.")
+				server.Logger.Debug(loginPacket.Username, "has joined while being authenticated.")
 			} else {
-				if server.Config.XBOXLiveAuth {
-					text.DefaultLogger.Debug(loginPacket.Username, "has tried to join while not being logged into XBOX Live.")
-					session.Kick("XBOX Live account required.", false, false)
-					return true
+				server.Logger.Debug(loginPacket.Username, "has joined while not being authenticated.")
+			}
+
+			if ban, banned := server.Moderation.GetPlayerBan(loginPacket.Username, xuid); banned {
+				server.Logger.Info(loginPacket.Username, "tried to join while being banned.")
+				session.Kick(ban.GetMessage(), false, false)
+				return true
+			}
+			if message, banned := server.checkIPBan(session.GetConnection().GetAddress().IP); banned {
+				server.Logger.Info(loginPacket.Username, "tried to join from a banned IP.")
+				session.Kick(message, false, false)
+				return true
+			}
+			if !server.Moderation.CanJoin(loginPacket.Username) {
+				server.Logger.Info(loginPacket.Username, "tried to join while not being whitelisted.")
+				session.Kick("Server is whitelisted.", false, false)
+				return true
+			}
+			var preLogin = &PlayerPreLoginEvent{Session: session, Name: loginPacket.Username, XUID: xuid, Address: session.GetConnection().GetAddress().IP, Authenticated: authenticated}
+			if !server.Events.Call(preLogin) {
+				if preLogin.KickMessage == "" {
+					preLogin.KickMessage = "Login cancelled."
 				}
-				text.DefaultLogger.Debug(loginPacket.Username, "has joined while not being logged into XBOX Live.")
+				server.Logger.Info(loginPacket.Username, "was prevented from joining:", preLogin.KickMessage)
+				session.Kick(preLogin.KickMessage, false, false)
+				return true
 			}
 
-			session.SetData(server.PermissionManager, types.SessionData{ClientUUID: loginPacket.ClientUUID, ClientXUID: loginPacket.ClientXUID, ClientId: loginPacket.ClientId, ProtocolNumber: loginPacket.Protocol, GameVersion: loginPacket.ClientData.GameVersion, Language: loginPacket.Language, DeviceOS: loginPacket.ClientData.DeviceOS})
-			session.SetPlayer(players.NewPlayer(loginPacket.ClientUUID, loginPacket.ClientXUID, int32(loginPacket.ClientData.DeviceOS), loginPacket.Username))
+			session.SetData(server.PermissionManager, types.SessionData{ClientUUID: clientUUID, ClientXUID: xuid, ClientId: loginPacket.ClientId, ProtocolNumber: loginPacket.Protocol, GameVersion: loginPacket.ClientData.GameVersion, Language: loginPacket.Language, DeviceOS: loginPacket.ClientData.DeviceOS})
+			session.SetPlayer(players.NewPlayer(clientUUID, xuid, int32(loginPacket.ClientData.DeviceOS), loginPacket.Username))
 
 			session.GetEncryptionHandler().Data = &utils.EncryptionData{
 				ClientPublicKey:  pubKey,
@@ -118,29 +150,53 @@ func NewLoginHandler(server *Server) *net.PacketHandler {
 			session.GetPlayer().SetGeometryData(loginPacket.GeometryData)
 			session.SetXBOXLiveAuthenticated(authenticated)
 
-			if server.Config.UseEncryption {
-				var jwt = utils.ConstructEncryptionJwt(server.GetPrivateKey(), server.GetServerToken())
-				session.SendServerHandshake(jwt)
-				session.EnableEncryption()
-			} else {
-				session.SendPlayStatus(data.StatusLoginSuccess)
-				session.SendResourcePackInfo(server.Config.ForceResourcePacks, server.PackManager.GetResourceStack(), server.PackManager.GetBehaviorStack())
+			if !server.handleDuplicateLogin(session, authenticated) {
+				return true
+			}
+			server.requirePasswordLogin(session)
+			if server.admitLogin(session) {
+				server.completeLogin(session)
 			}
-
-			server.SessionManager.AddMinecraftSession(session)
 			return true
 		}
 		return false
 	})
 }
 
-func NewMovePlayerHandler(_ *Server) *net.PacketHandler {
+// getLoginFailureMessage returns the message shown to clients of which the login could not be verified.
+func getLoginFailureMessage(err error) string {
+	switch auth.Cause(err) {
+	case auth.Expired, auth.NotYetValid:
+		return "Login expired, please restart your game."
+	case auth.IdentityMismatch:
+		return "Login identity does not match."
+	case auth.XboxLiveRequired:
+		return "XBOX Live account required."
+	case auth.UntrustedProxy:
+		return "Please join through the proxy."
+	}
+	return "Invalid login data."
+}
+
+func NewMovePlayerHandler(server *Server) *net.PacketHandler {
 	return net.NewPacketHandler(func(packet packets.IPacket, session *net.MinecraftSession) bool {
 		if pk, ok := packet.(*bedrock.MovePlayerPacket); ok {
 			if session.GetPlayer().GetDimension() == nil {
 				return false
 			}
-			session.SyncMove(pk.Position.X, pk.Position.Y, pk.Position.Z, pk.Rotation.Pitch, pk.Rotation.Yaw, pk.Rotation.HeadYaw, pk.OnGround)
+			if server.IsFrozen(session) || server.isQueued(session) {
+				resetFrozenMovement(session)
+				return true
+			}
+			var move = &PlayerMoveEvent{Session: session, From: session.GetPlayer().Position, To: pk.Position, Rotation: pk.Rotation, OnGround: pk.OnGround}
+			if !server.Events.Call(move) {
+				resetFrozenMovement(session)
+				return true
+			}
+			session.SyncMove(move.To.X, move.To.Y, move.To.Z, move.Rotation.Pitch, move.Rotation.Yaw, move.Rotation.HeadYaw, move.OnGround)
+			if move.To != pk.Position {
+				resetFrozenMovement(session)
+			}
 			return true
 		}
 		return false
@@ -154,42 +210,56 @@ func NewRequestChunkRadiusHandler(server *Server) *net.PacketHandler {
 			session.SetViewDistance(viewDistance)
 			session.SendChunkRadiusUpdated(viewDistance)
 
-			var sessions = server.SessionManager.GetSessions()
-			var viewers = make(map[string]protocol.PlayerListEntry)
-			for name, online := range sessions {
-				if online.HasSpawned() {
-					viewers[name] = online.GetPlayer()
-					online.SendPlayerList(data.ListTypeAdd, map[string]protocol.PlayerListEntry{session.GetName(): session.GetPlayer()})
-				}
+			if server.holdQueuedSession(session) {
+				return true
 			}
+			server.spawnSession(session)
+			return true
+		}
 
-			session.SendPlayerList(data.ListTypeAdd, viewers)
+		return false
+	})
+}
 
-			for _, online := range server.SessionManager.GetSessions() {
-				if session.GetUUID() != online.GetUUID() {
-					online.GetPlayer().SpawnPlayerTo(session)
-					online.GetPlayer().AddViewer(session)
+// spawnSession spawns the session to all other spawned sessions and vice versa,
+// and tells the session it spawned.
+func (server *Server) spawnSession(session *net.MinecraftSession) {
+	var sessions = server.SessionManager.GetSessions()
+	var viewers = make(map[string]protocol.PlayerListEntry)
+	for name, online := range sessions {
+		if online.HasSpawned() {
+			viewers[name] = online.GetPlayer()
+			online.SendPlayerList(data.ListTypeAdd, map[string]protocol.PlayerListEntry{session.GetName(): session.GetPlayer()})
+		}
+	}
 
-					session.GetPlayer().SpawnPlayerTo(online)
-					session.GetPlayer().AddViewer(online)
+	session.SendPlayerList(data.ListTypeAdd, viewers)
 
-					online.SendSkin(session)
-					session.SendSkin(online)
-				}
-			}
+	for _, online := range server.SessionManager.GetSessions() {
+		if session.GetUUID() != online.GetUUID() {
+			online.GetPlayer().SpawnPlayerTo(session)
+			online.GetPlayer().AddViewer(session)
 
-			session.SendSetEntityData(session.GetPlayer().GetRuntimeId(), session.GetPlayer().GetEntityData())
-			session.SendUpdateAttributes(session.GetPlayer().GetRuntimeId(), session.GetPlayer().GetAttributeMap())
+			session.GetPlayer().SpawnPlayerTo(online)
+			session.GetPlayer().AddViewer(online)
 
-			server.BroadcastMessage(text.Yellow+session.GetDisplayName(), "has joined the server")
-			session.SendPlayStatus(data.StatusSpawn)
-
-			session.Connected = true
-			return true
+			online.SendSkin(session)
+			session.SendSkin(online)
 		}
+	}
 
-		return false
-	})
+	session.SendSetEntityData(session.GetPlayer().GetRuntimeId(), session.GetPlayer().GetEntityData())
+	session.SendUpdateAttributes(session.GetPlayer().GetRuntimeId(), session.GetPlayer().GetAttributeMap())
+
+	var join = &PlayerJoinEvent{Session: session, JoinMessage: text.Yellow + session.GetDisplayName() + " has joined the server"}
+	server.Events.Call(join)
+	if join.JoinMessage != "" {
+		server.BroadcastMessage(join.JoinMessage)
+	}
+	session.SendPlayStatus(data.StatusSpawn)
+	server.promptPasswordLogin(session)
+
+	session.Connected = true
 }
 
 func NewResourcePackChunkRequestHandler(server *Server) *net.PacketHandler {
@@ -230,6 +300,7 @@ func NewResourcePackClientResponseHandler(server *Server) *net.PacketHandler {
 					server.LevelManager.GetDefaultLevel().GetDefaultDimension().AddViewer(session, r3.Vector{X: 0, Y: 7, Z: 0})
 					session.SendStartGame(session.GetPlayer(), blocks.GetRuntimeIdsTable())
 					session.SendCraftingData()
+					server.SendAvailableCommands(session)
 				})
 			}
 			return true
@@ -244,15 +315,31 @@ func NewTextHandler(server *Server) *net.PacketHandler {
 			if textPacket.TextType != data.TextChat {
 				return false
 			}
+			if server.isQueued(session) {
+				server.sendQueuePosition(session)
+				return true
+			}
+			if server.IsFrozen(session) {
+				server.promptPasswordLogin(session)
+				return true
+			}
+			var recipients = make([]*net.MinecraftSession, 0, server.SessionManager.GetSessionCount())
 			for _, receiver := range server.SessionManager.GetSessions() {
+				recipients = append(recipients, receiver)
+			}
+			var chat = &PlayerChatEvent{Session: session, Message: textPacket.Message, Recipients: recipients}
+			if !server.Events.Call(chat) {
+				return true
+			}
+			for _, receiver := range chat.Recipients {
 				receiver.SendText(types.Text{
-					Message: "<" + session.GetDisplayName() + "> " + textPacket.Message,
+					Message: "<" + session.GetDisplayName() + "> " + chat.Message,
 					PlatformChatId: textPacket.PlatformChatId,
 					SourceXUID: session.GetXUID(),
 					TextType: data.TextChat,
 				})
 			}
-			text.DefaultLogger.LogChat("<" + session.GetDisplayName() + "> " + textPacket.Message)
+			server.Logger.LogChat("<" + session.GetDisplayName() + "> " + chat.Message)
 			return true
 		}
 		return false
@@ -267,10 +354,13 @@ func NewInteractHandler(_ *Server) *net.PacketHandler {
 	})
 }
 
-func NewPlayerActionHandler(_ *Server) *net.PacketHandler {
+func NewPlayerActionHandler(server *Server) *net.PacketHandler {
 	return net.NewPacketHandler(func(packet packets.IPacket, session *net.MinecraftSession) bool {
 		//TODO: fix sending to others
 		if playerAction, ok := packet.(*bedrock.PlayerActionPacket); ok {
+			if server.IsFrozen(session) || server.isQueued(session) {
+				return true
+			}
 			switch playerAction.Action {
 			case bedrock.PlayerStartSneak:
 				session.GetPlayer().SetEntityProperty(data2.EntityDataSneaking, true)
@@ -290,9 +380,12 @@ func NewPlayerActionHandler(_ *Server) *net.PacketHandler {
 	})
 }
 
-func NewAnimateHandler(_ *Server) *net.PacketHandler {
+func NewAnimateHandler(server *Server) *net.PacketHandler {
 	return net.NewPacketHandler(func(packet packets.IPacket, session *net.MinecraftSession) bool {
 		if animate, ok := packet.(*bedrock.AnimatePacket); ok {
+			if server.IsFrozen(session) || server.isQueued(session) {
+				return true
+			}
 			for _, viewer := range session.GetPlayer().GetViewers() {
 				if viewer, ok := viewer.(*net.MinecraftSession); ok {
 					viewer.SendAnimate(animate.Action, animate.RuntimeId, animate.Float)
@@ -303,14 +396,21 @@ func NewAnimateHandler(_ *Server) *net.PacketHandler {
 	})
 }
 
-func NewInventoryTransactionHandler(_ *Server) *net.PacketHandler {
+func NewInventoryTransactionHandler(server *Server) *net.PacketHandler {
 	return net.NewPacketHandler(func(packet packets.IPacket, session *net.MinecraftSession) bool {
 		if invTransaction, ok := packet.(*bedrock.InventoryTransactionPacket); ok {
+			if server.IsFrozen(session) || server.isQueued(session) {
+				return true
+			}
 			var clickPos = invTransaction.BlockPosition
 			switch invTransaction.TransactionType {
 			case bedrock.UseItem:
 				switch invTransaction.ActionType {
 				case bedrock.ItemBreakBlock:
+					if !server.Events.Call(&BlockBreakEvent{Session: session, Position: clickPos, Item: invTransaction.ItemSlot}) {
+						resendBlock(session, clickPos)
+						break
+					}
 					runtimeId, ok := blocks.GetRuntimeId(0, 0)
 					if ok {
 						var block= blocks.New(blocks.NewBlockState("air", int32(runtimeId), 0, 0))
@@ -318,6 +418,15 @@ func NewInventoryTransactionHandler(_ *Server) *net.PacketHandler {
 					}
 					break
 				case bedrock.ItemClickBlock:
+					if !server.Events.Call(&PlayerInteractEvent{Session: session, Position: clickPos, Face: invTransaction.Face, ClickPosition: invTransaction.ClickPosition, Item: invTransaction.ItemSlot}) {
+						break
+					}
+					if invTransaction.ItemSlot == nil || invTransaction.ItemSlot.Count == 0 {
+						break
+					}
+					if !server.Events.Call(&BlockPlaceEvent{Session: session, Position: getAdjacentPosition(clickPos, invTransaction.Face), Item: invTransaction.ItemSlot}) {
+						break
+					}
 					// TODO: do block placing
 					break
 				}
@@ -328,63 +437,12 @@ func NewInventoryTransactionHandler(_ *Server) *net.PacketHandler {
 	})
 }
 
-func VerifyLoginRequest(chains []types.Chain, _ *Server) (successful bool, authenticated bool, clientPublicKey *ecdsa.PublicKey) {
-	var publicKey *ecdsa.PublicKey
-	var publicKeyRaw string
-	for _, chain := range chains {
-		if publicKeyRaw == "" {
-			if chain.Header.X5u == "" {
-				return
-			}
-			publicKeyRaw = chain.Header.X5u
-		}
-
-		sig := []byte(chain.Signature)
-		d := []byte(chain.Header.Raw + "." + chain.Payload.Raw)
-
-		var b64, errB64 = base64.RawStdEncoding.DecodeString(publicKeyRaw)
-		text.DefaultLogger.LogError(errB64)
-
-		key, err := x509.ParsePKIXPublicKey(b64)
-		if err != nil {
-			text.DefaultLogger.LogError(err)
-			return
-		}
-
-		hash := sha512.New384()
-		hash.Write(d)
-
-		publicKey = key.(*ecdsa.PublicKey)
-		r := new(big.Int).SetBytes(sig[:len(sig)/2])
-		s := new(big.Int).SetBytes(sig[len(sig)/2:])
-
-		if !ecdsa.Verify(publicKey, hash.Sum(nil), r, s) {
-			return
-		}
-
-		if publicKeyRaw == data.MojangPublicKey {
-			authenticated = true
-		}
-
-		t := time.Now().Unix()
-		if chain.Payload.ExpirationTime <= t && chain.Payload.ExpirationTime != 0 || chain.Payload.NotBefore > t || chain.Payload.IssuedAt > chain.Payload.ExpirationTime {
-			return
-		}
-
-		publicKeyRaw = chain.Payload.IdentityPublicKey
-	}
-
-	var b64, errB64 = base64.RawStdEncoding.DecodeString(publicKeyRaw)
-	text.DefaultLogger.LogError(errB64)
-
-	key, err := x509.ParsePKIXPublicKey(b64)
-	if err != nil {
-		text.DefaultLogger.LogError(err)
+// resendBlock sends the block at the position back to the session,
+// undoing the change the client already made to the block, for example when breaking it got cancelled.
+func resendBlock(session *net.MinecraftSession, position blocks.Position) {
+	var block, ok = session.GetPlayer().GetDimension().GetBlockAt(utils2.PositionToVector(position))
+	if !ok {
 		return
 	}
-
-	clientPublicKey = key.(*ecdsa.PublicKey)
-
-	successful = true
-	return
+	session.SendUpdateBlock(position, uint32(block.GetRuntimeId()), 0)
 }
@@ -21,9 +21,11 @@ type PacketManager struct {
 	*protocol.PacketManagerBase
 }
 
+// NewPacketManager returns the packet manager implementing info.LatestProtocol.
+// All default packet handlers get registered on the packet manager returned.
 func NewPacketManager(server *Server) *PacketManager {
 	var ids = info.PacketIds
-	var proto = &PacketManager{protocol.NewPacketManagerBase(info.PacketIds, map[int]func() packets.IPacket{
+	var proto = &PacketManager{protocol.NewPacketManagerBase(info.LatestProtocol, info.PacketIds, map[int]func() packets.IPacket{
 		ids[info.LoginPacket]:                      func() packets.IPacket { return bedrock.NewLoginPacket() },
 		ids[info.ClientHandshakePacket]:            func() packets.IPacket { return bedrock.NewClientHandshakePacket() },
 		ids[info.ResourcePackClientResponsePacket]: func() packets.IPacket { return bedrock.NewResourcePackClientResponsePacket() },
@@ -86,6 +88,14 @@ func (protocol *PacketManager) GetAddPlayer(uuid uuid.UUID, player protocol.AddP
 	return pk
 }
 
+func (protocol *PacketManager) GetAvailableCommands(commands []types.CommandData) packets.IPacket {
+	var pk = bedrock.NewAvailableCommandsPacket()
+
+	pk.Commands = commands
+
+	return pk
+}
+
 func (protocol *PacketManager) GetChunkRadiusUpdated(radius int32) packets.IPacket {
 	var pk = bedrock.NewChunkRadiusUpdatedPacket()
 	pk.Radius = radius
@@ -10,6 +10,7 @@ import (
 // It provides helper functions for both types of packs.
 type Manager struct {
 	serverPath string
+	logger     *text.Logger
 
 	resourcePacks map[string]*ResourcePack
 	resourceStack *Stack
@@ -20,7 +21,13 @@ type Manager struct {
 
 // NewManager returns a new pack manager with the given path.
 func NewManager(serverPath string) *Manager {
-	return &Manager{serverPath, make(map[string]*ResourcePack), NewStack(), make(map[string]*BehaviorPack), NewStack()}
+	return &Manager{serverPath, text.DefaultLogger, make(map[string]*ResourcePack), NewStack(), make(map[string]*BehaviorPack), NewStack()}
+}
+
+// SetLogger sets the logger loaded packs are logged to.
+// The default logger is used if not set.
+func (manager *Manager) SetLogger(logger *text.Logger) {
+	manager.logger = logger
 }
 
 // GetResourcePacks returns all resource maps in a UUID => pack map.
@@ -75,7 +82,7 @@ func (manager *Manager) LoadResourcePacks() []error {
 		}
 
 		manager.resourcePacks[resourcePack.manifest.Header.UUID] = resourcePack
-		text.DefaultLogger.Info("Loaded resource pack:", text.Yellow+resourcePack.manifest.Header.Name)
+		manager.logger.Info("Loaded resource pack:", text.Yellow+resourcePack.manifest.Header.Name)
 		manager.GetResourceStack().Push(resourcePack)
 	}
 	return errors
@@ -0,0 +1,200 @@
+package gomine
+
+import (
+	"strconv"
+
+	"github.com/irmine/gomine/net"
+	"github.com/irmine/gomine/net/packets/data"
+	"github.com/irmine/gomine/text"
+	"github.com/irmine/gomine/utils"
+)
+
+const (
+	// ReservedSlotPermission lets players join using the reserved slots of the server,
+	// when all regular slots are taken.
+	ReservedSlotPermission = "gomine.reserved-slot"
+	// MaintenanceBypassPermission lets players join while the server is in maintenance mode.
+	MaintenanceBypassPermission = "gomine.maintenance.bypass"
+)
+
+// DefaultQueueMessageInterval is the default interval in seconds in which queued players are told their position.
+const DefaultQueueMessageInterval = 10
+
+// IsMaintenanceMode checks if the server is in maintenance mode.
+// Only whitelisted players and players permitted to bypass maintenance may join during maintenance.
+func (server *Server) IsMaintenanceMode() bool {
+	return server.maintenance
+}
+
+// SetMaintenanceMode enables or disables maintenance mode.
+// Online and queued players that may not join during maintenance are disconnected when enabling it.
+func (server *Server) SetMaintenanceMode(value bool) {
+	server.maintenance = value
+	if !value {
+		return
+	}
+	for _, session := range server.SessionManager.GetSessions() {
+		if !server.canJoinDuringMaintenance(session) {
+			session.Kick("Server is in maintenance mode.", false, false)
+		}
+	}
+	if server.JoinQueue != nil {
+		for _, session := range server.JoinQueue.GetSessions() {
+			if !server.canJoinDuringMaintenance(session) {
+				server.JoinQueue.Remove(session.GetConnection())
+				server.removeQueuedSession(session)
+				session.Kick("Server is in maintenance mode.", false, false)
+			}
+		}
+	}
+}
+
+// canJoinDuringMaintenance checks if the session may join while the server is in maintenance mode.
+func (server *Server) canJoinDuringMaintenance(session *net.MinecraftSession) bool {
+	return session.HasPermission(MaintenanceBypassPermission) || server.Moderation.IsWhitelisted(session.GetName())
+}
+
+// getSlots returns the amount of slots available to the session.
+// Sessions permitted to use reserved slots get the reserved slots on top of the maximum players.
+func (server *Server) getSlots(session *net.MinecraftSession) int {
+	var slots = int(server.Config.MaximumPlayers)
+	if session.HasPermission(ReservedSlotPermission) {
+		slots += int(server.Config.ReservedSlots)
+	}
+	return slots
+}
+
+// hasFreeSlot checks if a slot is available for the session.
+func (server *Server) hasFreeSlot(session *net.MinecraftSession) bool {
+	return server.SessionManager.GetSessionCount() < server.getSlots(session)
+}
+
+// admitLogin decides if the logged in session may continue joining the server.
+// Sessions get rejected during maintenance if they may not join, and when the server is full.
+// If the join queue is enabled, sessions are queued instead of rejected when the server is full.
+// Queued sessions continue logging in and are held once spawned, so that they can be told their position.
+// Returns true if the login of the session should be completed right away.
+func (server *Server) admitLogin(session *net.MinecraftSession) bool {
+	if server.IsMaintenanceMode() && !server.canJoinDuringMaintenance(session) {
+		server.Logger.Info(session.GetName(), "tried to join during maintenance.")
+		session.Kick("Server is in maintenance mode.", false, false)
+		return false
+	}
+
+	var queued = server.JoinQueue != nil && server.JoinQueue.GetLength() != 0
+	if server.hasFreeSlot(session) && (!queued || session.HasPermission(ReservedSlotPermission)) {
+		return true
+	}
+
+	if server.JoinQueue != nil {
+		if position, ok := server.JoinQueue.Add(session); ok {
+			server.Logger.Info(session.GetName(), "has been queued at position", strconv.Itoa(position)+".")
+			server.continueLogin(session)
+			session.Flush()
+			return false
+		}
+	}
+	server.Logger.Info(session.GetName(), "tried to join while the server is full.")
+	session.SendPlayStatus(data.StatusLoginFailedServerFull)
+	session.Kick("Server is full.", false, false)
+	return false
+}
+
+// completeLogin continues the login sequence of the session,
+// and adds the session to the session manager.
+func (server *Server) completeLogin(session *net.MinecraftSession) {
+	server.continueLogin(session)
+	server.SessionManager.AddMinecraftSession(session)
+}
+
+// continueLogin continues the login sequence of the session with the encryption handshake,
+// or with the resource packs if encryption is disabled.
+func (server *Server) continueLogin(session *net.MinecraftSession) {
+	if server.Config.UseEncryption {
+		var jwt = utils.ConstructEncryptionJwt(server.GetPrivateKey(), server.GetServerToken())
+		session.SendServerHandshake(jwt)
+		session.EnableEncryption()
+	} else {
+		session.SendPlayStatus(data.StatusLoginSuccess)
+		session.SendResourcePackInfo(server.Config.ForceResourcePacks, server.PackManager.GetResourceStack(), server.PackManager.GetBehaviorStack())
+	}
+}
+
+// isQueued checks if the session is in the join queue.
+// Queued sessions may not move, chat or interact until they leave the queue.
+func (server *Server) isQueued(session *net.MinecraftSession) bool {
+	return server.JoinQueue != nil && server.JoinQueue.GetPosition(session.GetConnection()) != 0
+}
+
+// holdQueuedSession spawns the session in the holding state if it is in the join queue,
+// in which it only gets told its position until it leaves the queue.
+// Returns false if the session is not queued and should be spawned normally.
+func (server *Server) holdQueuedSession(session *net.MinecraftSession) bool {
+	if server.JoinQueue == nil || !server.JoinQueue.Hold(session.GetConnection()) {
+		return false
+	}
+	session.SendPlayStatus(data.StatusSpawn)
+	server.sendQueuePosition(session)
+	return true
+}
+
+// removeQueuedSession removes the player of a session that left the join queue without joining,
+// if it already spawned in the holding state.
+func (server *Server) removeQueuedSession(session *net.MinecraftSession) {
+	server.forgetAvailableCommands(session)
+	if session.GetPlayer() != nil && session.GetPlayer().Dimension != nil {
+		session.GetPlayer().Close()
+	}
+}
+
+// sendQueuePosition tells the queued session its position in the join queue.
+func (server *Server) sendQueuePosition(session *net.MinecraftSession) {
+	if position := server.JoinQueue.GetPosition(session.GetConnection()); position != 0 {
+		session.SendMessage(getQueueMessage(position))
+	}
+}
+
+// tickJoinQueue admits queued sessions while slots are available,
+// and tells queued sessions their position every queue message interval.
+// Queued sessions are not in the session manager, so they get flushed here every tick instead.
+func (server *Server) tickJoinQueue() {
+	if server.JoinQueue == nil {
+		return
+	}
+	for {
+		var session, ok = server.JoinQueue.Peek()
+		if !ok || !server.hasFreeSlot(session) {
+			break
+		}
+		server.JoinQueue.Pop()
+		var held = server.JoinQueue.Release(session.GetConnection())
+		if server.SessionManager.HasSession(session.GetName()) {
+			server.removeQueuedSession(session)
+			session.Kick("Logged in from another location.", false, false)
+			continue
+		}
+		server.Logger.Info(session.GetName(), "has left the queue.")
+		server.SessionManager.AddMinecraftSession(session)
+		if held {
+			server.spawnSession(session)
+		}
+		session.Flush()
+	}
+
+	var interval = server.Config.JoinQueue.MessageInterval
+	if interval <= 0 {
+		interval = DefaultQueueMessageInterval
+	}
+	var notify = server.tick%int64(interval*20) == 0
+	for index, session := range server.JoinQueue.GetSessions() {
+		if notify {
+			session.SendMessage(getQueueMessage(index + 1))
+		}
+		session.Flush()
+	}
+}
+
+// getQueueMessage returns the message telling queued players their position in the queue.
+func getQueueMessage(position int) string {
+	return text.Yellow + "The server is full. You are in position " + strconv.Itoa(position) + " of the queue."
+}
@@ -1,5 +1,12 @@
 package gomine
 
+import (
+	"github.com/irmine/gomine/event"
+	"github.com/irmine/gomine/net"
+	"github.com/irmine/gomine/net/info"
+	"github.com/irmine/gomine/scheduler"
+)
+
 type Manifest struct {
 	Name         string
 	Description  string
@@ -21,6 +28,7 @@ type IManifest interface {
 type IPlugin interface {
 	GetServer() *Server
 	OnEnable()
+	OnDisable()
 
 	GetName() string
 	GetVersion() string
@@ -70,6 +78,10 @@ func (manifest Manifest) GetDescription() string {
 	return manifest.Description
 }
 
+// OnDisable gets called once the plugin gets disabled, for example when the server shuts down.
+// Plugins may override it to save their data. It does nothing by default.
+func (plug *Plugin) OnDisable() {}
+
 // GetName returns the name of the plugin.
 func (plug *Plugin) GetName() string {
 	return plug.manifest.GetName()
@@ -109,3 +121,40 @@ func (plug *Plugin) setManifest(manifest IManifest) {
 func (plug *Plugin) GetServer() *Server {
 	return plug.server
 }
+
+// RegisterPacketHandler registers a packet handler owned by this plugin on every protocol of the server.
+// The time spent in the handler is attributed to this plugin in timings.
+func (plug *Plugin) RegisterPacketHandler(packet info.PacketName, handler *net.PacketHandler) {
+	handler.SetOwner(plug.GetName())
+	plug.server.ProtocolRegistry.RegisterHandler(packet, handler)
+}
+
+// ScheduleTask schedules a task owned by this plugin, running the function once on the next tick.
+func (plug *Plugin) ScheduleTask(function func()) *scheduler.Task {
+	return plug.server.Scheduler.Schedule(plug.GetName(), function)
+}
+
+// ScheduleDelayedTask schedules a task owned by this plugin, running the function once after the delay in ticks.
+func (plug *Plugin) ScheduleDelayedTask(function func(), delay int64) *scheduler.Task {
+	return plug.server.Scheduler.ScheduleDelayed(plug.GetName(), function, delay)
+}
+
+// ScheduleRepeatingTask schedules a task owned by this plugin, running the function after the delay in ticks,
+// and every period of ticks after that until the task gets cancelled.
+func (plug *Plugin) ScheduleRepeatingTask(function func(), delay int64, period int64) *scheduler.Task {
+	return plug.server.Scheduler.ScheduleRepeating(plug.GetName(), function, delay, period)
+}
+
+// RunAsyncTask runs the work off the tick goroutine, after which done gets called with its result on the tick goroutine.
+// The task is owned by this plugin, so that done is not called once the plugin gets disabled.
+func (plug *Plugin) RunAsyncTask(work func() interface{}, done func(result interface{})) *scheduler.Task {
+	return plug.server.Scheduler.RunAsync(plug.GetName(), work, done)
+}
+
+// Listen registers a listener owned by this plugin on the events of the server.
+// The listener must be a function taking a single pointer to an event, for example:
+// func(event *gomine.PlayerChatEvent) { ... }
+// All listeners of the plugin are unregistered once the plugin gets disabled.
+func (plug *Plugin) Listen(priority event.Priority, listener interface{}) (*event.Listener, error) {
+	return plug.server.Events.Listen(plug.GetName(), priority, listener)
+}
@@ -10,7 +10,6 @@ import (
 	"strings"
 
 	"github.com/google/uuid"
-	"github.com/irmine/gomine/text"
 )
 
 const (
@@ -73,11 +72,11 @@ func (manager *PluginManager) LoadPlugins() {
 		err := manager.LoadPlugin(filePath)
 		if err != nil {
 			if err.Error() == NoPluginsSupported {
-				text.DefaultLogger.Error("Go does currently not support plugins for your operating system.")
+				manager.server.Logger.Error("Go does currently not support plugins for your operating system.")
 				return
 			}
 		}
-		text.DefaultLogger.LogError(err)
+		manager.server.Logger.LogError(err)
 	}
 }
 
@@ -90,8 +89,8 @@ func (manager *PluginManager) CompilePlugin(filePath string) (*plugin.Plugin, er
 	var output, err = cmd.CombinedOutput()
 
 	if err != nil {
-		text.DefaultLogger.LogError(err)
-		text.DefaultLogger.Error(string(output))
+		manager.server.Logger.LogError(err)
+		manager.server.Logger.Error(string(output))
 	}
 
 	plug, err := plugin.Open(compiledPath)
@@ -117,7 +116,7 @@ func (manager *PluginManager) LoadPlugin(filePath string) error {
 
 	if err != nil {
 		if strings.Contains(err.Error(), OutdatedPlugin) {
-			text.DefaultLogger.Notice("Outdated plugin. Recompiling plugin... This might take a bit.")
+			manager.server.Logger.Notice("Outdated plugin. Recompiling plugin... This might take a bit.")
 			var newPlugin, newErr = manager.RecompilePlugin(filePath)
 			if newErr != nil {
 				return newErr
@@ -162,6 +161,28 @@ func (manager *PluginManager) LoadPlugin(filePath string) error {
 	return nil
 }
 
+// DisablePlugins disables all loaded plugins, after which they are unloaded.
+// A plugin panicking while being disabled does not prevent other plugins from being disabled.
+func (manager *PluginManager) DisablePlugins() {
+	for name, plug := range manager.plugins {
+		manager.disablePlugin(plug)
+		delete(manager.plugins, name)
+	}
+}
+
+// disablePlugin calls OnDisable on the plugin, recovering from any panic.
+// All tasks scheduled by the plugin are cancelled once disabled.
+func (manager *PluginManager) disablePlugin(plug IPlugin) {
+	defer func() {
+		if err := recover(); err != nil {
+			manager.server.Logger.Error("Plugin", plug.GetName(), "panicked while being disabled:", err)
+		}
+		manager.server.Scheduler.CancelTasks(plug.GetName())
+		manager.server.Events.UnregisterAll(plug.GetName())
+	}()
+	plug.OnDisable()
+}
+
 // ValidateManifest validates the plugin manifest and checks for duplicated plugins.
 func (manager *PluginManager) ValidateManifest(manifest IManifest, path string) error {
 	if manifest.GetName() == "" {
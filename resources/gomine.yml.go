@@ -16,6 +16,15 @@ type GoMineConfig struct {
 	MaximumPlayers  uint `yaml:"Maximum Players"`
 	DefaultGameMode byte `yaml:"Default Gamemode"`
 
+	ReservedSlots   uint            `yaml:"Reserved Slots"`
+	JoinQueue       JoinQueueConfig `yaml:"Join Queue"`
+	MaintenanceMode bool            `yaml:"Maintenance Mode"`
+
+	DuplicateLoginPolicy string `yaml:"Duplicate Login Policy"`
+
+	ShutdownMessage string `yaml:"Shutdown Message"`
+	ShutdownTimeout int    `yaml:"Shutdown Timeout"`
+
 	DebugMode bool `yaml:"Debug Mode"`
 
 	DefaultLevel     string `yaml:"Default Level"`
@@ -27,10 +36,76 @@ type GoMineConfig struct {
 	XBOXLiveAuth  bool `yaml:"XBOX Live Auth"`
 	UseEncryption bool `yaml:"Use Encryption"`
 
+	Authentication AuthenticationConfig `yaml:"Authentication"`
+
 	AllowQuery       bool `yaml:"Allow Query"`
 	AllowPluginQuery bool `yaml:"Allow Plugin Query"`
 
 	MaxViewDistance int32 `yaml:"Max View Distance"`
+
+	CapturePackets bool `yaml:"Capture Packets"`
+
+	DecodeFailureThreshold int `yaml:"Decode Failure Threshold"`
+
+	MaximumBatchSize             int `yaml:"Maximum Batch Size"`
+	MaximumDecompressedBatchSize int `yaml:"Maximum Decompressed Batch Size"`
+
+	PacketRateLimits     map[string]RateLimitConfig `yaml:"Packet Rate Limits"`
+	TemporaryBanDuration int                        `yaml:"Temporary Ban Duration"`
+
+	Hub HubConfig `yaml:"Hub"`
+}
+
+// AuthenticationConfig is the configuration of how players are authenticated.
+// Provider is one of xbox-live, offline or trusted-proxy.
+// XBOX Live is required for the xbox-live provider if XBOX Live Auth is enabled.
+// Players of the offline provider log in with a password within Login Timeout seconds after spawning.
+// Proxy Key is the base64 encoded public key the trusted proxy signs logins with.
+type AuthenticationConfig struct {
+	Provider              string `yaml:"Provider"`
+	MinimumPasswordLength int    `yaml:"Minimum Password Length"`
+	LoginTimeout          int    `yaml:"Login Timeout"`
+	MaximumLoginAttempts  int    `yaml:"Maximum Login Attempts"`
+	ProxyKey              string `yaml:"Proxy Key"`
+}
+
+// JoinQueueConfig is the configuration of the queue players join when the server is full.
+// Maximum Size is the maximum amount of queued players, or 0 for no maximum.
+// Queued players are told their position every Message Interval seconds.
+type JoinQueueConfig struct {
+	Enabled         bool `yaml:"Enabled"`
+	MaximumSize     int  `yaml:"Maximum Size"`
+	MessageInterval int  `yaml:"Message Interval"`
+}
+
+// HubConfig is the configuration of the network of servers this server is part of.
+// Server Name is the name of this server in the list of servers.
+// Players are sent to the hub server with /hub, and to the fallback server when this server shuts down.
+type HubConfig struct {
+	Enabled        bool                       `yaml:"Enabled"`
+	ServerName     string                     `yaml:"Server Name"`
+	HubServer      string                     `yaml:"Hub Server"`
+	FallbackServer string                     `yaml:"Fallback Server"`
+	Servers        map[string]HubServerConfig `yaml:"Servers"`
+}
+
+// HubServerConfig is a single server in the network.
+// Address and Port are the address players get transferred to,
+// Control Address is the loopback TCP address the server shares its online player count on.
+type HubServerConfig struct {
+	Address        string `yaml:"Address"`
+	Port           uint16 `yaml:"Port"`
+	ControlAddress string `yaml:"Control Address"`
+}
+
+// RateLimitConfig is the rate limit of a single packet type.
+// Sessions may send Burst packets at once, after which their budget refills with Rate packets per second.
+// Action is the action taken when the budget is exceeded: drop, kick or ban.
+// Banned IPs are banned for the temporary ban duration in seconds.
+type RateLimitConfig struct {
+	Rate   float64 `yaml:"Rate"`
+	Burst  float64 `yaml:"Burst"`
+	Action string  `yaml:"Action"`
 }
 
 // NewGoMineConfig returns a new configuration struct.
@@ -55,6 +130,19 @@ func initializeConfig(serverPath string) {
 			MaximumPlayers:  20,
 			DefaultGameMode: 1,
 
+			ReservedSlots: 0,
+			JoinQueue: JoinQueueConfig{
+				Enabled:         false,
+				MaximumSize:     50,
+				MessageInterval: 10,
+			},
+			MaintenanceMode: false,
+
+			DuplicateLoginPolicy: "kick-old",
+
+			ShutdownMessage: "Server stopped.",
+			ShutdownTimeout: 30,
+
 			DebugMode: true,
 
 			DefaultLevel:     "world",
@@ -66,10 +154,46 @@ func initializeConfig(serverPath string) {
 			XBOXLiveAuth:  true,
 			UseEncryption: false,
 
+			Authentication: AuthenticationConfig{
+				Provider:              "xbox-live",
+				MinimumPasswordLength: 6,
+				LoginTimeout:          60,
+				MaximumLoginAttempts:  3,
+				ProxyKey:              "",
+			},
+
 			AllowQuery:       true,
 			AllowPluginQuery: true,
 
 			MaxViewDistance: 8,
+
+			CapturePackets: false,
+
+			DecodeFailureThreshold: 10,
+
+			MaximumBatchSize:             2097152,
+			MaximumDecompressedBatchSize: 8388608,
+
+			PacketRateLimits: map[string]RateLimitConfig{
+				"MovePlayerPacket":               {Rate: 40, Burst: 80, Action: "drop"},
+				"AnimatePacket":                  {Rate: 20, Burst: 40, Action: "drop"},
+				"InventoryTransactionPacket":     {Rate: 40, Burst: 80, Action: "drop"},
+				"TextPacket":                     {Rate: 5, Burst: 10, Action: "kick"},
+				"CommandRequestPacket":           {Rate: 5, Burst: 10, Action: "kick"},
+				"ResourcePackChunkRequestPacket": {Rate: 20, Burst: 40, Action: "kick"},
+				"LoginPacket":                    {Rate: 0.2, Burst: 2, Action: "ban"},
+			},
+			TemporaryBanDuration: 300,
+
+			Hub: HubConfig{
+				Enabled:        false,
+				ServerName:     "lobby",
+				HubServer:      "lobby",
+				FallbackServer: "lobby",
+				Servers: map[string]HubServerConfig{
+					"lobby": {Address: "127.0.0.1", Port: 19132, ControlAddress: "127.0.0.1:19200"},
+				},
+			},
 		})
 		var file, _ = os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 		file.WriteString(string(data))
@@ -0,0 +1,292 @@
+// Package scheduler implements a scheduler running tasks on the tick of a server.
+// Tasks run on the tick goroutine, so that they never race with the tick of the server.
+// Work that would block the tick may be run on a pool of workers instead,
+// after which its result is delivered back onto the tick goroutine.
+package scheduler
+
+import (
+	"container/heap"
+	"sync"
+
+	"github.com/irmine/gomine/text"
+	"github.com/irmine/gomine/timings"
+)
+
+// DefaultWorkers is the default amount of workers running asynchronous tasks.
+const DefaultWorkers = 4
+
+// asyncJob is a job run on a worker of the scheduler.
+type asyncJob struct {
+	task   *Task
+	work   func() interface{}
+	done   func(result interface{})
+	result interface{}
+}
+
+// Scheduler runs tasks on the tick of a server.
+// Tasks may be scheduled from any goroutine, but always run on the goroutine calling Tick.
+type Scheduler struct {
+	logger  *text.Logger
+	timings *timings.Timings
+
+	mutex  sync.Mutex
+	tick   int64
+	lastId int64
+	tasks  taskQueue
+
+	jobMutex  sync.Mutex
+	jobSignal *sync.Cond
+	jobs      []*asyncJob
+	completed []*asyncJob
+	closed    bool
+	workers   sync.WaitGroup
+}
+
+// New returns a new scheduler, running asynchronous tasks on the given amount of workers.
+func New(workers int) *Scheduler {
+	if workers < 1 {
+		workers = DefaultWorkers
+	}
+	var scheduler = &Scheduler{logger: text.DefaultLogger}
+	scheduler.jobSignal = sync.NewCond(&scheduler.jobMutex)
+	scheduler.workers.Add(workers)
+	for i := 0; i < workers; i++ {
+		go scheduler.work()
+	}
+	return scheduler
+}
+
+// SetLogger sets the logger panics of tasks are logged to.
+// The default logger is used if not set.
+func (scheduler *Scheduler) SetLogger(logger *text.Logger) {
+	scheduler.logger = logger
+}
+
+// SetTimings sets the timings the time spent running tasks is measured with.
+func (scheduler *Scheduler) SetTimings(timings *timings.Timings) {
+	scheduler.timings = timings
+}
+
+// GetCurrentTick returns the amount of times the scheduler was ticked.
+func (scheduler *Scheduler) GetCurrentTick() int64 {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+	return scheduler.tick
+}
+
+// Schedule schedules a task running the function once on the next tick.
+func (scheduler *Scheduler) Schedule(owner string, function func()) *Task {
+	return scheduler.schedule(owner, function, 1, 0)
+}
+
+// ScheduleDelayed schedules a task running the function once after the given amount of ticks.
+// Delays shorter than one tick run the function on the next tick.
+func (scheduler *Scheduler) ScheduleDelayed(owner string, function func(), delay int64) *Task {
+	return scheduler.schedule(owner, function, delay, 0)
+}
+
+// ScheduleRepeating schedules a task running the function after the given delay in ticks,
+// and every period of ticks after that, until the task gets cancelled.
+// Periods shorter than one tick run the function every tick.
+func (scheduler *Scheduler) ScheduleRepeating(owner string, function func(), delay int64, period int64) *Task {
+	if period < 1 {
+		period = 1
+	}
+	return scheduler.schedule(owner, function, delay, period)
+}
+
+// schedule adds a new task to the scheduler.
+func (scheduler *Scheduler) schedule(owner string, function func(), delay int64, period int64) *Task {
+	if delay < 1 {
+		delay = 1
+	}
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+	scheduler.lastId++
+	var task = &Task{id: scheduler.lastId, owner: owner, function: function, nextRun: scheduler.tick + delay, period: period}
+	heap.Push(&scheduler.tasks, task)
+	return task
+}
+
+// RunAsync runs the work on a worker of the scheduler, off the tick goroutine.
+// Once the work is done, done gets called with its result on the tick goroutine, unless the task was cancelled.
+// Work must not access the server, but may pass anything it needs to done.
+func (scheduler *Scheduler) RunAsync(owner string, work func() interface{}, done func(result interface{})) *Task {
+	scheduler.mutex.Lock()
+	scheduler.lastId++
+	var task = &Task{id: scheduler.lastId, owner: owner}
+	scheduler.mutex.Unlock()
+
+	scheduler.jobMutex.Lock()
+	defer scheduler.jobMutex.Unlock()
+	if scheduler.closed {
+		task.Cancel()
+		return task
+	}
+	scheduler.jobs = append(scheduler.jobs, &asyncJob{task: task, work: work, done: done})
+	scheduler.jobSignal.Signal()
+	return task
+}
+
+// CancelTasks cancels all tasks owned by the given owner, including asynchronous tasks.
+// Asynchronous work that is already running is finished, but its result is discarded.
+func (scheduler *Scheduler) CancelTasks(owner string) {
+	scheduler.mutex.Lock()
+	for _, task := range scheduler.tasks {
+		if task.owner == owner {
+			task.Cancel()
+		}
+	}
+	scheduler.mutex.Unlock()
+
+	scheduler.jobMutex.Lock()
+	defer scheduler.jobMutex.Unlock()
+	for _, jobs := range [][]*asyncJob{scheduler.jobs, scheduler.completed} {
+		for _, job := range jobs {
+			if job.task.owner == owner {
+				job.task.Cancel()
+			}
+		}
+	}
+}
+
+// GetTaskCount returns the amount of scheduled tasks that were not cancelled.
+func (scheduler *Scheduler) GetTaskCount() int {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+	var count = 0
+	for _, task := range scheduler.tasks {
+		if !task.IsCancelled() {
+			count++
+		}
+	}
+	return count
+}
+
+// Tick advances the scheduler by one tick.
+// All tasks due on this tick are run, after which the callbacks of completed asynchronous tasks are run.
+// Tasks scheduled while ticking run on the next tick at the earliest.
+func (scheduler *Scheduler) Tick() {
+	scheduler.mutex.Lock()
+	scheduler.tick++
+	var due []*Task
+	for len(scheduler.tasks) > 0 && scheduler.tasks[0].nextRun <= scheduler.tick {
+		var task = heap.Pop(&scheduler.tasks).(*Task)
+		if !task.IsCancelled() {
+			due = append(due, task)
+		}
+	}
+	scheduler.mutex.Unlock()
+
+	for _, task := range due {
+		if task.IsCancelled() {
+			continue
+		}
+		scheduler.run(task, task.function)
+		if task.IsRepeating() && !task.IsCancelled() {
+			scheduler.mutex.Lock()
+			task.nextRun = scheduler.tick + task.period
+			heap.Push(&scheduler.tasks, task)
+			scheduler.mutex.Unlock()
+		}
+	}
+
+	scheduler.jobMutex.Lock()
+	var completed = scheduler.completed
+	scheduler.completed = nil
+	scheduler.jobMutex.Unlock()
+
+	for _, job := range completed {
+		if job.task.IsCancelled() || job.done == nil {
+			continue
+		}
+		var result = job.result
+		scheduler.run(job.task, func() {
+			job.done(result)
+		})
+	}
+}
+
+// run runs the function of the task, measuring it with the timings of the scheduler.
+// Panics of the function are recovered and logged, so that a single task can not stop the tick.
+func (scheduler *Scheduler) run(task *Task, function func()) {
+	var start = scheduler.timings.Start()
+	defer func() {
+		if err := recover(); err != nil {
+			scheduler.logger.Error("Task", task.id, "of", getOwnerName(task.owner), "panicked:", err)
+		}
+		scheduler.timings.Stop(timings.Task, getOwnerName(task.owner), start)
+	}()
+	function()
+}
+
+// work runs asynchronous jobs until the scheduler gets closed.
+func (scheduler *Scheduler) work() {
+	defer scheduler.workers.Done()
+	for {
+		scheduler.jobMutex.Lock()
+		for len(scheduler.jobs) == 0 && !scheduler.closed {
+			scheduler.jobSignal.Wait()
+		}
+		if len(scheduler.jobs) == 0 {
+			scheduler.jobMutex.Unlock()
+			return
+		}
+		var job = scheduler.jobs[0]
+		scheduler.jobs[0] = nil
+		scheduler.jobs = scheduler.jobs[1:]
+		scheduler.jobMutex.Unlock()
+
+		if job.task.IsCancelled() {
+			continue
+		}
+		job.result = scheduler.runAsync(job)
+
+		scheduler.jobMutex.Lock()
+		scheduler.completed = append(scheduler.completed, job)
+		scheduler.jobMutex.Unlock()
+	}
+}
+
+// runAsync runs the work of an asynchronous job, recovering from panics.
+// A panicking job gets cancelled.
+func (scheduler *Scheduler) runAsync(job *asyncJob) (result interface{}) {
+	defer func() {
+		if err := recover(); err != nil {
+			scheduler.logger.Error("Asynchronous task", job.task.id, "of", getOwnerName(job.task.owner), "panicked:", err)
+			job.task.Cancel()
+		}
+	}()
+	return job.work()
+}
+
+// Close cancels all tasks and stops the workers once they finished the work they are running.
+// Asynchronous tasks can no longer be run once the scheduler is closed.
+func (scheduler *Scheduler) Close() {
+	scheduler.mutex.Lock()
+	for _, task := range scheduler.tasks {
+		task.Cancel()
+	}
+	scheduler.tasks = nil
+	scheduler.mutex.Unlock()
+
+	scheduler.jobMutex.Lock()
+	scheduler.closed = true
+	for _, job := range scheduler.jobs {
+		job.task.Cancel()
+	}
+	scheduler.jobs = nil
+	scheduler.completed = nil
+	scheduler.jobSignal.Broadcast()
+	scheduler.jobMutex.Unlock()
+
+	scheduler.workers.Wait()
+}
+
+// getOwnerName returns the name tasks of the owner are logged and measured under.
+func getOwnerName(owner string) string {
+	if owner == "" {
+		return "GoMine"
+	}
+	return owner
+}
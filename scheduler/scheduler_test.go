@@ -0,0 +1,107 @@
+package scheduler
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSchedule(t *testing.T) {
+	var scheduler = New(1)
+	defer scheduler.Close()
+
+	var runs []string
+	scheduler.ScheduleDelayed("", func() { runs = append(runs, "delayed") }, 3)
+	scheduler.Schedule("", func() {
+		runs = append(runs, "next")
+		scheduler.Schedule("", func() { runs = append(runs, "nested") })
+	})
+	var repeating = scheduler.ScheduleRepeating("", func() { runs = append(runs, "repeating") }, 2, 2)
+
+	for i := 0; i < 5; i++ {
+		scheduler.Tick()
+	}
+	repeating.Cancel()
+	scheduler.Tick()
+	scheduler.Tick()
+
+	var expected = []string{"next", "repeating", "nested", "delayed", "repeating"}
+	if !reflect.DeepEqual(runs, expected) {
+		t.Errorf("expected tasks to run as %v, got %v", expected, runs)
+	}
+	if scheduler.GetTaskCount() != 0 {
+		t.Errorf("expected no tasks left, got %v", scheduler.GetTaskCount())
+	}
+}
+
+func TestCancelTasks(t *testing.T) {
+	var scheduler = New(1)
+	defer scheduler.Close()
+
+	var runs = 0
+	scheduler.ScheduleRepeating("Plugin", func() { runs++ }, 1, 1)
+	scheduler.Schedule("Other", func() { runs += 10 })
+	scheduler.CancelTasks("Plugin")
+	scheduler.Tick()
+	scheduler.Tick()
+	if runs != 10 {
+		t.Errorf("expected only the task of the other plugin to run, got %v runs", runs)
+	}
+}
+
+func TestPanickingTask(t *testing.T) {
+	var scheduler = New(1)
+	defer scheduler.Close()
+
+	var ran = false
+	scheduler.Schedule("Plugin", func() { panic("test") })
+	scheduler.Schedule("Plugin", func() { ran = true })
+	scheduler.Tick()
+	if !ran {
+		t.Error("expected a panicking task not to prevent other tasks from running")
+	}
+}
+
+func TestRunAsync(t *testing.T) {
+	var scheduler = New(2)
+	defer scheduler.Close()
+
+	var results = make(chan int, 1)
+	var workGoroutine = make(chan bool, 1)
+	scheduler.RunAsync("Plugin", func() interface{} {
+		workGoroutine <- true
+		return 42
+	}, func(result interface{}) {
+		results <- result.(int)
+	})
+	var cancelled = scheduler.RunAsync("Plugin", func() interface{} {
+		return 0
+	}, func(result interface{}) {
+		t.Error("expected the callback of a cancelled task not to run")
+	})
+	cancelled.Cancel()
+
+	<-workGoroutine
+	var deadline = time.Now().Add(time.Second * 5)
+	for time.Now().Before(deadline) {
+		scheduler.Tick()
+		select {
+		case result := <-results:
+			if result != 42 {
+				t.Errorf("expected result 42, got %v", result)
+			}
+			return
+		default:
+			time.Sleep(time.Millisecond)
+		}
+	}
+	t.Fatal("the result of the asynchronous task was never delivered")
+}
+
+func TestClose(t *testing.T) {
+	var scheduler = New(1)
+	scheduler.Close()
+	if task := scheduler.RunAsync("", func() interface{} { return nil }, nil); !task.IsCancelled() {
+		t.Error("expected asynchronous tasks to be cancelled once closed")
+	}
+}
@@ -0,0 +1,78 @@
+package scheduler
+
+import (
+	"sync/atomic"
+)
+
+// Task is a task scheduled on a scheduler.
+// Tasks are owned by the plugin that scheduled them, and may be cancelled at any time.
+type Task struct {
+	id        int64
+	owner     string
+	function  func()
+	nextRun   int64
+	period    int64
+	cancelled int32
+}
+
+// GetId returns the ID of the task, unique within its scheduler.
+func (task *Task) GetId() int64 {
+	return task.id
+}
+
+// GetOwner returns the name of the plugin that scheduled the task.
+func (task *Task) GetOwner() string {
+	return task.owner
+}
+
+// IsRepeating checks if the task runs repeatedly until cancelled.
+func (task *Task) IsRepeating() bool {
+	return task.period > 0
+}
+
+// Cancel cancels the task, preventing it from running again.
+// The callback of cancelled asynchronous tasks is not run.
+func (task *Task) Cancel() {
+	atomic.StoreInt32(&task.cancelled, 1)
+}
+
+// IsCancelled checks if the task was cancelled.
+func (task *Task) IsCancelled() bool {
+	return atomic.LoadInt32(&task.cancelled) == 1
+}
+
+// taskQueue is a priority queue of tasks, ordered by the tick they run next and the order they were scheduled in.
+// It implements heap.Interface.
+type taskQueue []*Task
+
+// Len returns the amount of tasks in the queue.
+func (queue taskQueue) Len() int {
+	return len(queue)
+}
+
+// Less checks if the task at i runs before the task at j.
+func (queue taskQueue) Less(i, j int) bool {
+	if queue[i].nextRun == queue[j].nextRun {
+		return queue[i].id < queue[j].id
+	}
+	return queue[i].nextRun < queue[j].nextRun
+}
+
+// Swap swaps the tasks at i and j.
+func (queue taskQueue) Swap(i, j int) {
+	queue[i], queue[j] = queue[j], queue[i]
+}
+
+// Push adds a task to the end of the queue.
+func (queue *taskQueue) Push(x interface{}) {
+	*queue = append(*queue, x.(*Task))
+}
+
+// Pop removes the last task of the queue.
+func (queue *taskQueue) Pop() interface{} {
+	var old = *queue
+	var task = old[len(old)-1]
+	old[len(old)-1] = nil
+	*queue = old[:len(old)-1]
+	return task
+}
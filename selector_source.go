@@ -0,0 +1,138 @@
+package gomine
+
+import (
+	"strconv"
+
+	"github.com/golang/geo/r3"
+	"github.com/irmine/gomine/commands/selectors"
+	"github.com/irmine/gomine/net"
+	"github.com/irmine/gomine/net/protocol"
+	"github.com/irmine/worlds"
+	"github.com/irmine/worlds/entities"
+)
+
+// playerGameMode is the game mode of all players, as sent in the start game packet.
+const playerGameMode = 1
+
+// entityTypeNames are the identifiers of entity types by their network ID, without the minecraft: prefix,
+// as used in the type argument of selectors.
+var entityTypeNames = map[uint32]string{
+	10: "chicken", 11: "cow", 12: "pig", 13: "sheep", 14: "wolf", 15: "villager", 16: "mooshroom",
+	17: "squid", 18: "rabbit", 19: "bat", 20: "iron_golem", 21: "snow_golem", 22: "ocelot", 23: "horse",
+	24: "donkey", 25: "mule", 26: "skeleton_horse", 27: "zombie_horse", 28: "polar_bear", 29: "llama",
+	30: "parrot", 31: "dolphin", 32: "zombie", 33: "creeper", 34: "skeleton", 35: "spider",
+	36: "zombie_pigman", 37: "slime", 38: "enderman", 39: "silverfish", 40: "cave_spider", 41: "ghast",
+	42: "magma_cube", 43: "blaze", 44: "zombie_villager", 45: "witch", 46: "stray", 47: "husk",
+	48: "wither_skeleton", 49: "guardian", 50: "elder_guardian", 51: "npc", 52: "wither", 53: "ender_dragon",
+	54: "shulker", 55: "endermite", 56: "agent", 57: "vindicator", 58: "phantom", 61: "armor_stand",
+	62: "tripod_camera", 63: "player", 64: "item", 65: "tnt", 66: "falling_block", 67: "moving_block",
+	68: "xp_bottle", 69: "xp_orb", 70: "eye_of_ender_signal", 71: "ender_crystal", 72: "fireworks_rocket",
+	73: "thrown_trident", 74: "turtle", 76: "shulker_bullet", 77: "fishing_hook", 78: "chalkboard",
+	79: "dragon_fireball", 80: "arrow", 81: "snowball", 82: "egg", 83: "painting", 84: "minecart",
+	85: "fireball", 86: "splash_potion", 87: "ender_pearl", 88: "leash_knot", 89: "wither_skull",
+	90: "boat", 91: "wither_skull_dangerous", 93: "lightning_bolt", 94: "small_fireball",
+	95: "area_effect_cloud", 96: "hopper_minecart", 97: "tnt_minecart", 98: "chest_minecart",
+	100: "command_block_minecart", 101: "lingering_potion", 102: "llama_spit", 103: "evocation_fang",
+	104: "evocation_illager", 105: "vex", 106: "ice_bomb", 107: "balloon", 108: "pufferfish",
+	109: "salmon", 110: "drowned", 111: "tropicalfish", 112: "cod",
+}
+
+// selectorSource provides the players and entities of a server to target selectors.
+type selectorSource struct {
+	server *Server
+}
+
+// GetSelectorSource returns the source target selectors in commands are resolved against.
+// It is used to create target arguments, for example:
+// arguments.NewPlayerTarget("player", false, server.GetSelectorSource())
+// The value of player targets is the session of the player.
+func (server *Server) GetSelectorSource() selectors.Source {
+	return selectorSource{server}
+}
+
+// GetOrigin returns the origin of the sender.
+// Players are their own origin, while all other senders resolve selectors from the spawn of the default dimension.
+func (source selectorSource) GetOrigin(sender interface{}) selectors.Origin {
+	if session, ok := sender.(*net.MinecraftSession); ok && session.HasSpawned() {
+		var self = newSessionTarget(session)
+		var rotation = session.GetPlayer().GetRotation()
+		return selectors.Origin{Position: self.Position, Dimension: self.Dimension, Yaw: rotation.Yaw, Pitch: rotation.Pitch, Self: self}
+	}
+	var origin = selectors.Origin{Position: selectors.Vector{Y: 7}}
+	if level := source.server.LevelManager.GetDefaultLevel(); level != nil {
+		origin.Dimension = level.GetDefaultDimension()
+	}
+	return origin
+}
+
+// SupportsArgument checks if selectors with the argument can be resolved against the server.
+// Players and entities have no tags, so the tag argument is not supported.
+func (source selectorSource) SupportsArgument(key string) bool {
+	return key != "tag"
+}
+
+// GetPlayers returns the targets of all players that spawned.
+func (source selectorSource) GetPlayers() []*selectors.Target {
+	var targets []*selectors.Target
+	for _, session := range source.server.SessionManager.GetSessions() {
+		if session.HasSpawned() {
+			targets = append(targets, newSessionTarget(session))
+		}
+	}
+	return targets
+}
+
+// GetEntities returns the targets of all entities in the loaded chunks of the dimension, excluding players.
+func (source selectorSource) GetEntities(dimension interface{}) []*selectors.Target {
+	var d, ok = dimension.(*worlds.Dimension)
+	if !ok || d == nil {
+		return nil
+	}
+	var targets []*selectors.Target
+	for _, chunk := range d.GetChunks() {
+		for _, entity := range chunk.GetEntities() {
+			if entity.GetEntityType() == uint32(entities.Player) {
+				continue
+			}
+			targets = append(targets, newEntityTarget(entity, d))
+		}
+	}
+	return targets
+}
+
+// newSessionTarget returns the target of the player of the session.
+func newSessionTarget(session *net.MinecraftSession) *selectors.Target {
+	return &selectors.Target{
+		Name:      session.GetName(),
+		Type:      "player",
+		Player:    true,
+		GameMode:  playerGameMode,
+		Position:  toSelectorVector(session.GetPlayer().GetPosition()),
+		Dimension: session.GetPlayer().GetDimension(),
+		Value:     session,
+	}
+}
+
+// newEntityTarget returns the target of an entity in the dimension.
+func newEntityTarget(entity protocol.AddEntityEntry, dimension *worlds.Dimension) *selectors.Target {
+	return &selectors.Target{
+		Type:      getEntityTypeName(entity.GetEntityType()),
+		GameMode:  -1,
+		Position:  toSelectorVector(entity.GetPosition()),
+		Dimension: dimension,
+		Value:     entity,
+	}
+}
+
+// getEntityTypeName returns the identifier of the entity type, or its network ID if the type is unknown.
+func getEntityTypeName(entityType uint32) string {
+	if name, ok := entityTypeNames[entityType]; ok {
+		return name
+	}
+	return strconv.Itoa(int(entityType))
+}
+
+// toSelectorVector converts a vector to a selector vector.
+func toSelectorVector(vector r3.Vector) selectors.Vector {
+	return selectors.Vector{X: vector.X, Y: vector.Y, Z: vector.Z}
+}
@@ -0,0 +1,25 @@
+package gomine
+
+import (
+	"testing"
+
+	"github.com/irmine/gomine/commands/arguments"
+)
+
+func TestEntityTypeNames(t *testing.T) {
+	for entityType, expected := range map[uint32]string{32: "zombie", 63: "player", 9999: "9999"} {
+		if name := getEntityTypeName(entityType); name != expected {
+			t.Errorf("entity type %v has name %v, expected %v", entityType, name, expected)
+		}
+	}
+}
+
+func TestSelectorTagsRejected(t *testing.T) {
+	var target = arguments.NewTarget("target", false, selectorSource{})
+	if target.IsValidValue("@e[tag=red]") {
+		t.Error("expected selectors with tags to be rejected")
+	}
+	if !target.IsValidValue("@e[type=zombie]") {
+		t.Error("expected selectors with types to be accepted")
+	}
+}
@@ -1,30 +1,38 @@
 package gomine
 
 import (
+	"context"
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
-	"github.com/irmine/worlds/generation/defaults"
-	"github.com/irmine/worlds/providers"
-
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"github.com/irmine/gomine/auth"
 	"github.com/irmine/gomine/commands"
+	"github.com/irmine/gomine/event"
+	"github.com/irmine/gomine/hub"
+	"github.com/irmine/gomine/moderation"
 	"github.com/irmine/gomine/net"
+	"github.com/irmine/gomine/net/capture"
 	"github.com/irmine/gomine/net/info"
 	"github.com/irmine/gomine/net/packets/data"
 	"github.com/irmine/gomine/net/protocol"
 	"github.com/irmine/gomine/packs"
 	"github.com/irmine/gomine/permissions"
 	"github.com/irmine/gomine/resources"
+	"github.com/irmine/gomine/scheduler"
 	"github.com/irmine/gomine/text"
-	"github.com/irmine/goraklib/server"
+	"github.com/irmine/gomine/tick"
+	"github.com/irmine/gomine/timings"
 	"github.com/irmine/query"
 	"github.com/irmine/worlds"
 	net2 "net"
 	"os"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 const (
@@ -33,74 +41,169 @@ const (
 )
 
 type Server struct {
-	isRunning         bool
+	isRunning         int32
+	stopping          int32
+	runMutex          sync.Mutex
+	cancelRun         context.CancelFunc
 	tick              int64
+	maintenance       bool
 	privateKey        *ecdsa.PrivateKey
 	token             []byte
+	logFile           *os.File
+	worldProvider     WorldProvider
 	ServerPath        string
 	Config            *resources.GoMineConfig
+	Logger            *text.Logger
 	CommandReader     *text.CommandReader
 	CommandManager    *commands.Manager
 	PackManager       *packs.Manager
 	PermissionManager *permissions.Manager
 	LevelManager      *worlds.Manager
 	SessionManager    *net.SessionManager
+	ProtocolRegistry  *protocol.Registry
 	NetworkAdapter    *net.NetworkAdapter
 	PluginManager     *PluginManager
 	QueryManager      query.Manager
+	TickLoop          *tick.Loop
+	Timings           *timings.Timings
+	Scheduler         *scheduler.Scheduler
+	Events            *event.Bus
+	Hub               *hub.Hub
+	Moderation        *moderation.Store
+	JoinQueue         *net.JoinQueue
+	AuthProvider      auth.Provider
+	passwordLogins    passwordLogins
+	availableCommands availableCommands
 }
 
 // AlreadyStarted gets returned during server startup,
 // if the server has already been started.
 var AlreadyStarted = errors.New("server is already started")
 
-// NewServer returns a new server with the given server path.
+// NewServer returns a new server with the given server path and configuration.
+// The server accepts connections over RakNet, and reads console commands from Stdin.
 func NewServer(serverPath string, config *resources.GoMineConfig) *Server {
+	return New(serverPath, WithConfig(config), WithConsole(os.Stdin))
+}
+
+// NewServerWithTransport returns a new server with the given server path and configuration,
+// which accepts connections over the given transport and reads console commands from Stdin.
+func NewServerWithTransport(serverPath string, config *resources.GoMineConfig, transport net.Transport) *Server {
+	return New(serverPath, WithConfig(config), WithConsole(os.Stdin), WithTransport(transport))
+}
+
+// SetUpDirectories sets up all directories needed for GoMine in the given server path.
+func SetUpDirectories(serverPath string) {
+	os.Mkdir(serverPath+"extensions", 0700)
+	os.Mkdir(serverPath+"extensions/plugins", 0700)
+	os.Mkdir(serverPath+"extensions/behavior_packs", 0700)
+	os.Mkdir(serverPath+"extensions/resource_packs", 0700)
+}
+
+// New returns a new server with the given server path, configured with the given options.
+// Servers share no state with each other, so that multiple servers may run in a single process.
+// Options that are not passed fall back to the configuration in the server path,
+// a new logger, no console, the RakNet transport and the default world provider.
+func New(serverPath string, opts ...Option) *Server {
+	var options = serverOptions{}
+	for _, option := range opts {
+		option(&options)
+	}
+
 	var s = &Server{}
 
 	s.ServerPath = serverPath
-	s.Config = config
-	text.DefaultLogger.DebugMode = config.DebugMode
-	file, _ := os.OpenFile(serverPath+"gomine.log", os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0700)
-	text.DefaultLogger.AddOutput(func(message []byte) {
-		_, err := file.WriteString(text.ColoredString(message).StripAll())
-		if err != nil {
-			text.DefaultLogger.LogError(err)
-		}
-	})
+	s.Config = options.config
+	if s.Config == nil {
+		s.Config = resources.NewGoMineConfig(serverPath)
+	}
+	var config = s.Config
+
+	s.Logger = options.logger
+	if s.Logger == nil {
+		s.Logger = newLogger(s)
+	}
+	s.worldProvider = options.worldProvider
+	if s.worldProvider == nil {
+		s.worldProvider = DefaultWorldProvider
+	}
+	var transport = options.transport
+	if transport == nil {
+		transport = net.NewRakNetTransport()
+	}
 
 	s.LevelManager = worlds.NewManager(serverPath)
-	s.CommandReader = text.NewCommandReader(os.Stdin)
-	s.CommandReader.AddReadFunc(s.attemptReadCommand)
+	if options.console != nil {
+		s.CommandReader = text.NewCommandReader(options.console)
+		s.CommandReader.AddReadFunc(s.attemptReadCommand)
+	}
 
 	s.CommandManager = commands.NewManager()
 
 	s.SessionManager = net.NewSessionManager()
-	s.NetworkAdapter = net.NewNetworkAdapter(NewPacketManager(s), s.SessionManager)
-	s.NetworkAdapter.GetRakLibManager().PongData = s.GeneratePongData()
-	s.NetworkAdapter.GetRakLibManager().RawPacketFunction = s.HandleRaw
-	s.NetworkAdapter.GetRakLibManager().DisconnectFunction = s.HandleDisconnect
+	s.ProtocolRegistry = protocol.NewRegistry()
+	s.ProtocolRegistry.RegisterProtocol(NewPacketManager(s))
+	s.NetworkAdapter = net.NewNetworkAdapter(transport, s.ProtocolRegistry, s.SessionManager)
+	s.NetworkAdapter.SetLogger(s.Logger)
+	s.NetworkAdapter.GetTransport().SetPongData(s.GeneratePongData())
+	s.NetworkAdapter.GetTransport().SetRawPacketFunction(s.HandleRaw)
+	s.NetworkAdapter.GetTransport().SetDisconnectFunction(s.HandleDisconnect)
+	s.NetworkAdapter.SetConnectionFilter(s.checkIPBan)
+	s.NetworkAdapter.SetDecodeFailureThreshold(config.DecodeFailureThreshold)
+	s.NetworkAdapter.SetBatchLimits(config.MaximumBatchSize, config.MaximumDecompressedBatchSize)
+	s.NetworkAdapter.SetTemporaryBanDuration(time.Duration(config.TemporaryBanDuration) * time.Second)
+	if err := s.NetworkAdapter.SetRateLimits(getPacketRateLimits(config)); err != nil {
+		s.Logger.Error("Invalid packet rate limits:", err)
+	}
+
+	if config.CapturePackets {
+		if recorder, err := capture.NewRecorder(serverPath + "captures/"); err != nil {
+			s.Logger.LogError(err)
+		} else {
+			s.NetworkAdapter.SetRecorder(recorder)
+		}
+	}
 
 	s.PackManager = packs.NewManager(serverPath)
+	s.PackManager.SetLogger(s.Logger)
 	s.PermissionManager = permissions.NewManager()
+	s.passwordLogins.logins = make(map[net.Connection]*passwordLogin)
+	s.availableCommands.sent = make(map[*net.MinecraftSession]string)
 	s.PluginManager = NewPluginManager(s)
 	s.QueryManager = query.NewManager()
+	s.TickLoop = tick.NewLoop(s.Tick)
+	s.Timings = timings.New(timings.DefaultWindow)
+	s.NetworkAdapter.SetTimings(s.Timings)
+	s.Scheduler = scheduler.New(scheduler.DefaultWorkers)
+	s.Scheduler.SetLogger(s.Logger)
+	s.Scheduler.SetTimings(s.Timings)
+	s.Events = event.NewBus()
+	s.Events.SetLogger(s.Logger)
+	s.TickLoop.SetOverloadFunction(s.warnOverload)
+
+	if config.Hub.Enabled {
+		s.Hub = newHub(s)
+	}
+	if config.JoinQueue.Enabled {
+		s.JoinQueue = net.NewJoinQueue(config.JoinQueue.MaximumSize)
+	}
+	s.maintenance = config.MaintenanceMode
 
 	if config.UseEncryption {
 		var curve = elliptic.P384()
 
 		var err error
 		s.privateKey, err = ecdsa.GenerateKey(curve, rand.Reader)
-		text.DefaultLogger.LogError(err)
+		s.Logger.LogError(err)
 
 		if !curve.IsOnCurve(s.privateKey.X, s.privateKey.Y) {
-			text.DefaultLogger.Error("Invalid private key generated")
+			s.Logger.Error("Invalid private key generated")
 		}
 
 		var token = make([]byte, 128)
 		_, err = rand.Read(token)
 		if err != nil {
-			text.DefaultLogger.Error(err)
+			s.Logger.Error(err)
 		}
 		s.token = token
 	}
@@ -108,32 +211,68 @@ func NewServer(serverPath string, config *resources.GoMineConfig) *Server {
 	return s
 }
 
+// getPacketRateLimits converts the packet rate limits of the configuration to network rate limits.
+func getPacketRateLimits(config *resources.GoMineConfig) map[info.PacketName]net.RateLimit {
+	var limits = make(map[info.PacketName]net.RateLimit)
+	for name, limit := range config.PacketRateLimits {
+		limits[info.PacketName(name)] = net.RateLimit{Rate: limit.Rate, Burst: limit.Burst, Action: net.RateLimitAction(limit.Action)}
+	}
+	return limits
+}
+
 // RegisterDefaultCommands registers all default commands of the server.
 func (server *Server) RegisterDefaultCommands() {
 	server.CommandManager.RegisterCommand(NewStop(server))
 	server.CommandManager.RegisterCommand(NewList(server))
 	server.CommandManager.RegisterCommand(NewPing())
+	server.CommandManager.RegisterCommand(NewTps(server))
+	server.CommandManager.RegisterCommand(NewTimings(server))
 	server.CommandManager.RegisterCommand(NewTest(server))
+	server.CommandManager.RegisterCommand(NewBan(server))
+	server.CommandManager.RegisterCommand(NewBanIp(server))
+	server.CommandManager.RegisterCommand(NewPardon(server))
+	server.CommandManager.RegisterCommand(NewWhitelist(server))
+	server.CommandManager.RegisterCommand(NewMaintenance(server))
+
+	if _, ok := server.getPasswordProvider(); ok {
+		server.CommandManager.RegisterCommand(NewLogin(server))
+		server.CommandManager.RegisterCommand(NewRegister(server))
+	}
+
+	if server.Hub != nil {
+		server.CommandManager.RegisterCommand(NewServerCommand(server))
+		server.CommandManager.RegisterCommand(NewHub(server))
+	}
 }
 
 // IsRunning checks if the server is running.
 func (server *Server) IsRunning() bool {
-	return server.isRunning
+	return atomic.LoadInt32(&server.isRunning) == 1
 }
 
 // Start starts the server and loads levels, plugins, resource packs etc.
 // Start returns an error if one occurred during starting.
 func (server *Server) Start() error {
-	if server.isRunning {
+	if server.IsRunning() {
 		return AlreadyStarted
 	}
-	text.DefaultLogger.Info("GoMine "+GoMineVersion+" is now starting...", "("+server.ServerPath+")")
+	server.Logger.Info("GoMine "+GoMineVersion+" is now starting...", "("+server.ServerPath+")")
+
+	server.LevelManager.SetDefaultLevel(server.worldProvider(server))
 
-	server.LevelManager.SetDefaultLevel(worlds.NewLevel("world", server.ServerPath))
-	var dimension = worlds.NewDimension("overworld", server.LevelManager.GetDefaultLevel(), worlds.OverworldId)
-	dimension.SetChunkProvider(providers.NewAnvil(server.ServerPath + "worlds/world/overworld/region/"))
-	server.LevelManager.GetDefaultLevel().SetDefaultDimension(dimension)
-	dimension.SetGenerator(defaults.NewFlatGenerator())
+	var store, err = moderation.NewStore(server.ServerPath)
+	if err != nil {
+		return fmt.Errorf("loading bans and whitelist: %v", err)
+	}
+	server.Moderation = store
+
+	if server.AuthProvider == nil {
+		var provider, err = newAuthProvider(server)
+		if err != nil {
+			return fmt.Errorf("setting up authentication: %v", err)
+		}
+		server.AuthProvider = provider
+	}
 
 	server.RegisterDefaultCommands()
 
@@ -142,21 +281,17 @@ func (server *Server) Start() error {
 
 	server.PluginManager.LoadPlugins()
 
-	server.isRunning = true
-	return server.NetworkAdapter.GetRakLibManager().Start(server.Config.ServerIp, int(server.Config.ServerPort))
-}
-
-// Shutdown shuts down the server, saving and disabling everything.
-func (server *Server) Shutdown() {
-	if !server.isRunning {
-		return
+	if server.Hub != nil {
+		if err := server.Hub.Start(hub.DefaultPollInterval); err != nil {
+			return err
+		}
 	}
-	text.DefaultLogger.Info("Server is shutting down.")
 
-	text.DefaultLogger.Notice("Server stopped.")
-	text.DefaultLogger.Wait()
-
-	server.isRunning = false
+	if err := server.NetworkAdapter.GetTransport().Start(server.Config.ServerIp, server.Config.ServerPort); err != nil {
+		return err
+	}
+	atomic.StoreInt32(&server.isRunning, 1)
+	return nil
 }
 
 // GetMinecraftVersion returns the latest Minecraft game version.
@@ -179,7 +314,7 @@ func (server *Server) HasPermission(string) bool {
 
 // SendMessage sends a message to the server to satisfy the ICommandSender interface.
 func (server *Server) SendMessage(message ...interface{}) {
-	text.DefaultLogger.Notice(message)
+	server.Logger.Notice(message)
 }
 
 // GetEngineName returns 'GoMine'.
@@ -229,6 +364,8 @@ func (server *Server) GetAllowedViewDistance(distance int32) int32 {
 }
 
 // GetCurrentTick returns the current tick the server is on.
+// Every tick of the server ticks every level exactly once,
+// and ticks skipped by the tick loop are not counted.
 func (server *Server) GetCurrentTick() int64 {
 	return server.tick
 }
@@ -238,7 +375,7 @@ func (server *Server) BroadcastMessageTo(receivers []*net.MinecraftSession, mess
 	for _, session := range receivers {
 		session.SendMessage(message)
 	}
-	text.DefaultLogger.LogChat(message)
+	server.Logger.LogChat(message)
 }
 
 // Broadcast broadcasts a message to all players and the console in the server.
@@ -246,7 +383,7 @@ func (server *Server) BroadcastMessage(message ...interface{}) {
 	for _, session := range server.SessionManager.GetSessions() {
 		session.SendMessage(message)
 	}
-	text.DefaultLogger.LogChat(message)
+	server.Logger.LogChat(message)
 }
 
 // GetPrivateKey returns the ECDSA private key of the server.
@@ -276,6 +413,11 @@ func (server *Server) GenerateQueryResult() query.Result {
 		ps = append(ps, name)
 	}
 
+	var whitelist = "off"
+	if server.Moderation != nil && server.Moderation.IsWhitelistEnabled() {
+		whitelist = "on"
+	}
+
 	var result = query.Result{
 		MOTD:           server.GetMotd(),
 		ListPlugins:    server.Config.AllowPluginQuery,
@@ -287,7 +429,7 @@ func (server *Server) GenerateQueryResult() query.Result {
 		WorldName:      server.LevelManager.GetDefaultLevel().GetName(),
 		OnlinePlayers:  int(server.SessionManager.GetSessionCount()),
 		MaximumPlayers: int(server.Config.MaximumPlayers),
-		Whitelist:      "off",
+		Whitelist:      whitelist,
 		Port:           server.Config.ServerPort,
 		Address:        server.Config.ServerIp,
 	}
@@ -296,7 +438,14 @@ func (server *Server) GenerateQueryResult() query.Result {
 }
 
 // HandleRaw handles a raw packet, for instance a query packet.
+// Raw packets of IP banned addresses are dropped.
 func (server *Server) HandleRaw(packet []byte, addr *net2.UDPAddr) {
+	if len(packet) < 2 {
+		return
+	}
+	if _, banned := server.checkIPBan(addr.IP); banned {
+		return
+	}
 	if string(packet[0:2]) == string(query.Header) {
 		if !server.Config.AllowQuery {
 			return
@@ -308,18 +457,48 @@ func (server *Server) HandleRaw(packet []byte, addr *net2.UDPAddr) {
 		server.QueryManager.HandleQuery(q)
 		return
 	}
-	text.DefaultLogger.Debug("Unhandled raw packet:", hex.EncodeToString(packet))
+	server.Logger.Debug("Unhandled raw packet:", hex.EncodeToString(packet))
 }
 
-// HandleDisconnect handles a disconnection from a session.
-func (server *Server) HandleDisconnect(s *server.Session) {
-	text.DefaultLogger.Debug(s, "disconnected!")
-	session, ok := server.SessionManager.GetSessionByRakNetSession(s)
-	server.SessionManager.RemoveMinecraftSession(session)
+// checkIPBan checks if the IP is banned from the server.
+// Returns the message shown to the banned client and true if the IP is banned.
+func (server *Server) checkIPBan(ip net2.IP) (string, bool) {
+	if server.Moderation == nil {
+		return "", false
+	}
+	var ban, ok = server.Moderation.GetBan(moderation.BanIP, ip.String())
+	return ban.GetMessage(), ok
+}
 
+// HandleDisconnect handles a disconnection from a transport connection.
+func (server *Server) HandleDisconnect(connection net.Connection) {
+	server.Logger.Debug(connection.GetAddress(), "disconnected!")
+	if recorder := server.NetworkAdapter.GetRecorder(); recorder != nil {
+		recorder.Close(connection.GetAddress().String())
+	}
+	server.unfreeze(connection)
+	server.NetworkAdapter.RemovePendingSession(connection)
+	if server.JoinQueue != nil {
+		if queued, ok := server.JoinQueue.GetSession(connection); ok && server.JoinQueue.Remove(connection) {
+			server.Logger.Debug(connection.GetAddress(), "left the join queue.")
+			server.removeQueuedSession(queued)
+		}
+	}
+	session, ok := server.SessionManager.GetSessionByConnection(connection)
 	if !ok {
 		return
 	}
+	server.removeSession(session)
+}
+
+// removeSession removes the session from the session manager,
+// and removes its player from the player list of all other sessions.
+// Sessions that were already removed are left alone, so that their player does not quit twice.
+func (server *Server) removeSession(session *net.MinecraftSession) {
+	if !server.SessionManager.RemoveMinecraftSession(session) {
+		return
+	}
+	server.forgetAvailableCommands(session)
 
 	if session.GetPlayer().Dimension != nil {
 		for _, online := range server.SessionManager.GetSessions() {
@@ -329,39 +508,72 @@ func (server *Server) HandleDisconnect(s *server.Session) {
 		session.GetPlayer().Close()
 		session.Connected = false
 
-		server.BroadcastMessage(text.Yellow+session.GetDisplayName(), "has left the server")
+		var quit = &PlayerQuitEvent{Session: session, QuitMessage: text.Yellow + session.GetDisplayName() + " has left the server"}
+		server.Events.Call(quit)
+		if quit.QuitMessage != "" {
+			server.BroadcastMessage(quit.QuitMessage)
+		}
 	}
 }
 
 // GeneratePongData generates the GoRakLib pong data for the UnconnectedPong RakNet packet.
 func (server *Server) GeneratePongData() string {
-	return fmt.Sprint("MCPE;", server.GetMotd(), ";", info.LatestProtocol, ";", server.GetMinecraftNetworkVersion(), ";", server.SessionManager.GetSessionCount(), ";", server.Config.MaximumPlayers, ";", server.NetworkAdapter.GetRakLibManager().ServerId, ";", server.GetEngineName(), ";Creative;")
+	return fmt.Sprint("MCPE;", server.GetMotd(), ";", info.LatestProtocol, ";", server.GetMinecraftNetworkVersion(), ";", server.SessionManager.GetSessionCount(), ";", server.Config.MaximumPlayers, ";", server.NetworkAdapter.GetTransport().GetServerId(), ";", server.GetEngineName(), ";Creative;")
 }
 
 // Tick ticks the entire server. (Levels, scheduler, GoRakLib server etc.)
+// Ticks are run by the tick loop of the server once running.
 // Internal. Not to be used by plugins.
 func (server *Server) Tick() {
-	if !server.isRunning {
+	if !server.IsRunning() || server.IsStopping() {
 		return
 	}
+	var tickStart = server.Timings.Start()
 	if server.tick%20 == 0 {
 		server.QueryManager.SetQueryResult(server.GenerateQueryResult())
-		server.NetworkAdapter.GetRakLibManager().PongData = server.GeneratePongData()
+		server.NetworkAdapter.GetTransport().SetPongData(server.GeneratePongData())
+
+		if recorder := server.NetworkAdapter.GetRecorder(); recorder != nil {
+			recorder.Flush()
+		}
+		server.tickPasswordLogins()
+		server.tickAvailableCommands()
 	}
 
+	server.tickJoinQueue()
+	server.Scheduler.Tick()
+
 	for _, session := range server.SessionManager.GetSessions() {
+		var start = server.Timings.Start()
 		session.Tick()
+		server.Timings.Stop(timings.SessionTick, "Chunk Loading", start)
 	}
 
 	for _, level := range server.LevelManager.GetLevels() {
+		var start = server.Timings.Start()
 		level.Tick()
+		server.Timings.Stop(timings.LevelTick, level.GetName(), start)
+	}
+
+	for _, session := range server.SessionManager.GetSessions() {
+		session.Flush()
 	}
 
 	server.tick++
+	server.Timings.Stop(timings.ServerTick, "Full Tick", tickStart)
+}
+
+// warnOverload warns that the server can not keep up with the tick rate.
+func (server *Server) warnOverload(behind time.Duration, skipped int64) {
+	server.Logger.Warning("Can't keep up! Is the server overloaded? Running", behind.Round(time.Millisecond), "behind, skipped", skipped, "ticks.")
 }
 
 func (server *Server) attemptReadCommand(commandText string) {
-	args := strings.Split(commandText, " ")
+	var serverCommand = &ServerCommandEvent{Sender: server, CommandLine: commandText}
+	if !server.Events.Call(serverCommand) {
+		return
+	}
+	args := strings.Split(serverCommand.CommandLine, " ")
 	commandName := args[0]
 	i := 1
 	for !server.CommandManager.IsCommandRegistered(commandName) {
@@ -374,7 +586,7 @@ func (server *Server) attemptReadCommand(commandText string) {
 	manager := server.CommandManager
 
 	if !manager.IsCommandRegistered(commandName) {
-		text.DefaultLogger.Error("Command could not be found.")
+		server.Logger.Error("Command could not be found.")
 		return
 	}
 	args = args[i:]
@@ -0,0 +1,128 @@
+package gomine
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/irmine/gomine/net"
+	"github.com/irmine/gomine/resources"
+	"github.com/irmine/gomine/text"
+	"github.com/irmine/worlds"
+	"github.com/irmine/worlds/generation/defaults"
+	"github.com/irmine/worlds/providers"
+)
+
+// Option is an option to configure a server with when creating it with New.
+type Option func(options *serverOptions)
+
+// serverOptions holds everything configured by the options passed to New.
+type serverOptions struct {
+	config        *resources.GoMineConfig
+	logger        *text.Logger
+	console       io.Reader
+	transport     net.Transport
+	worldProvider WorldProvider
+}
+
+// WorldProvider returns the default level of a server.
+// It gets called once the server starts.
+type WorldProvider func(server *Server) *worlds.Level
+
+// WithConfig sets the configuration of the server.
+// The configuration is loaded from gomine.yml in the server path if not set.
+func WithConfig(config *resources.GoMineConfig) Option {
+	return func(options *serverOptions) {
+		options.config = config
+	}
+}
+
+// WithLogger sets the logger of the server.
+// The logger is used as is: the debug mode of the configuration is not applied,
+// and nothing gets written to gomine.log. A new logger writing to Stdout and gomine.log
+// in the server path gets created if not set.
+func WithLogger(logger *text.Logger) Option {
+	return func(options *serverOptions) {
+		options.logger = logger
+	}
+}
+
+// WithConsole sets the reader console commands are read from, for example os.Stdin.
+// No console commands are read if not set.
+func WithConsole(reader io.Reader) Option {
+	return func(options *serverOptions) {
+		options.console = reader
+	}
+}
+
+// WithTransport sets the transport the server accepts connections over.
+// Connections are accepted over RakNet if not set.
+func WithTransport(transport net.Transport) Option {
+	return func(options *serverOptions) {
+		options.transport = transport
+	}
+}
+
+// WithWorldProvider sets the function providing the default level of the server.
+// DefaultWorldProvider is used if not set.
+func WithWorldProvider(provider WorldProvider) Option {
+	return func(options *serverOptions) {
+		options.worldProvider = provider
+	}
+}
+
+// DefaultWorldProvider provides a level with a flat overworld,
+// which is stored in the worlds directory of the server path.
+func DefaultWorldProvider(server *Server) *worlds.Level {
+	var level = worlds.NewLevel("world", server.ServerPath)
+	var dimension = worlds.NewDimension("overworld", level, worlds.OverworldId)
+	dimension.SetChunkProvider(providers.NewAnvil(server.ServerPath + "worlds/world/overworld/region/"))
+	level.SetDefaultDimension(dimension)
+	dimension.SetGenerator(defaults.NewFlatGenerator())
+	return level
+}
+
+// newLogger returns a new logger for the server, writing to Stdout and gomine.log in the server path.
+func newLogger(server *Server) *text.Logger {
+	var logger = text.NewLogger(GoMineName, server.Config.DebugMode)
+	logger.AddOutput(func(message []byte) {
+		os.Stdout.Write(message)
+	})
+
+	server.logFile, _ = os.OpenFile(server.ServerPath+"gomine.log", os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0700)
+	logger.AddOutput(func(message []byte) {
+		var file = server.logFile
+		if file == nil {
+			return
+		}
+		if _, err := file.WriteString(text.ColoredString(message).StripAll()); err != nil {
+			os.Stderr.WriteString(err.Error() + "\n")
+		}
+	})
+	return logger
+}
+
+// Run starts the server if it was not yet started, and ticks it until the context is cancelled
+// or the server gets shut down, for example by the stop command.
+// The server is shut down once the context is cancelled, after which Run returns.
+// An error is returned if the server could not be started.
+func (server *Server) Run(ctx context.Context) error {
+	if !server.IsRunning() {
+		if err := server.Start(); err != nil {
+			return err
+		}
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	server.runMutex.Lock()
+	server.cancelRun = cancel
+	server.runMutex.Unlock()
+
+	server.TickLoop.Run(ctx, server.IsRunning)
+	server.Shutdown()
+
+	server.runMutex.Lock()
+	server.cancelRun = nil
+	server.runMutex.Unlock()
+	return nil
+}
@@ -0,0 +1,154 @@
+package gomine_test
+
+import (
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/irmine/gomine"
+	"github.com/irmine/gomine/client"
+	"github.com/irmine/gomine/event"
+	"github.com/irmine/gomine/internal/gominetest"
+	"github.com/irmine/gomine/net"
+	"github.com/irmine/gomine/resources"
+)
+
+func TestLoopbackLogin(t *testing.T) {
+	var server, transport = gominetest.StartServer(t, nil)
+	var connection, err = transport.Connect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := client.New("Steve", connection)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	if err := c.Join(time.Second * 10); err != nil {
+		t.Fatalf("joining: %v", err)
+	}
+	session, ok := server.SessionManager.GetSessionByUUID(c.GetUUID())
+	if !ok {
+		t.Fatal("session of the client was not added to the session manager")
+	}
+	if session.GetName() != "Steve" || session.GetConnection() != net.Connection(connection) {
+		t.Errorf("session %v does not belong to the client", session.GetName())
+	}
+	if session.GetPlayer().GetRuntimeId() != c.GetRuntimeId() {
+		t.Errorf("client got runtime ID %v, expected %v", c.GetRuntimeId(), session.GetPlayer().GetRuntimeId())
+	}
+}
+
+func TestMalformedBatchesDisconnect(t *testing.T) {
+	var _, transport = gominetest.StartServer(t, func(config *resources.GoMineConfig) {
+		config.DecodeFailureThreshold = 3
+	})
+	var connection, err = transport.Connect()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var closed = make(chan struct{})
+	go func() {
+		for {
+			if _, err := connection.ReadBatch(); err == io.EOF {
+				close(closed)
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < 4; i++ {
+		if err := connection.WriteBatch([]byte{net.McpeFlag, 0x01, 0x02, 0x03}); err != nil {
+			t.Fatalf("writing malformed batch %v: %v", i, err)
+		}
+	}
+	select {
+	case <-closed:
+	case <-time.After(time.Second * 5):
+		t.Fatal("connection was not closed after exceeding the decode failure threshold")
+	}
+}
+
+func TestStopEndsRun(t *testing.T) {
+	var server, _ = gominetest.StartServer(t, nil)
+	var stopped = make(chan struct{})
+	go func() {
+		server.Stop()
+		close(stopped)
+	}()
+	<-stopped
+
+	var deadline = time.Now().Add(time.Second * 5)
+	for server.IsRunning() {
+		if time.Now().After(deadline) {
+			t.Fatal("server is still running after being stopped")
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+	if !server.IsStopping() {
+		t.Fatal("server was stopped without shutting down")
+	}
+}
+
+func TestQueuedLogin(t *testing.T) {
+	var server, transport = gominetest.StartServer(t, func(config *resources.GoMineConfig) {
+		config.MaximumPlayers = 1
+		config.JoinQueue.Enabled = true
+		config.JoinQueue.MessageInterval = 60
+	})
+	var join = func(name string) *client.Client {
+		var connection, err = transport.Connect()
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, err := client.New(name, connection)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() {
+			c.Close()
+		})
+		if err := c.Join(time.Second * 10); err != nil {
+			t.Fatalf("joining %v: %v", name, err)
+		}
+		return c
+	}
+
+	join("Steve")
+	var alex = join("Alex")
+	if _, ok := server.SessionManager.GetSessionByUUID(alex.GetUUID()); ok {
+		t.Fatal("queued client was added to the session manager")
+	}
+	if length := server.JoinQueue.GetLength(); length != 1 {
+		t.Fatalf("expected 1 queued session, %v sessions queued", length)
+	}
+}
+
+func TestDuplicateLoginQuitsOnce(t *testing.T) {
+	var server, transport = gominetest.StartServer(t, nil)
+	var quits int32
+	if _, err := server.Events.Listen("test", event.Monitor, func(quit *gomine.PlayerQuitEvent) {
+		atomic.AddInt32(&quits, 1)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		var connection, err = transport.Connect()
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, err := client.New("Steve", connection)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer c.Close()
+		if err := c.Join(time.Second * 10); err != nil {
+			t.Fatalf("joining: %v", err)
+		}
+	}
+	if quits := atomic.LoadInt32(&quits); quits != 1 {
+		t.Fatalf("old session quit %v times, expected once", quits)
+	}
+}
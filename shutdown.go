@@ -0,0 +1,138 @@
+package gomine
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultShutdownMessage is the default message players are disconnected with when the server shuts down.
+	DefaultShutdownMessage = "Server stopped."
+	// DefaultShutdownTimeout is the default time in seconds the server may take to shut down.
+	DefaultShutdownTimeout = 30
+)
+
+// IsStopping checks if the server is shutting down.
+// No new logins are accepted once the server is shutting down.
+func (server *Server) IsStopping() bool {
+	return atomic.LoadInt32(&server.stopping) == 1
+}
+
+// Stop stops the server. If the server is run by Run, its context gets cancelled,
+// so that the server shuts down on the goroutine ticking it. The server is shut down immediately otherwise.
+// Stop does not wait for the server to be shut down if it is run by Run.
+func (server *Server) Stop() {
+	server.runMutex.Lock()
+	var cancel = server.cancelRun
+	server.runMutex.Unlock()
+	if cancel != nil {
+		cancel()
+		return
+	}
+	server.Shutdown()
+}
+
+// Shutdown shuts down the server, saving and disabling everything.
+// Logins are no longer accepted, after which players are disconnected, plugins are disabled,
+// levels are saved and the transport gets stopped. The remaining steps are skipped
+// if shutting down takes longer than the shutdown timeout in the configuration.
+// The logger is flushed and the log file is closed in all cases.
+// Servers run by Run shut down once its context is cancelled, and should be stopped with Stop instead.
+func (server *Server) Shutdown() {
+	if !server.IsRunning() || !atomic.CompareAndSwapInt32(&server.stopping, 0, 1) {
+		return
+	}
+	server.Logger.Info("Server is shutting down.")
+
+	var done = make(chan bool)
+	go func() {
+		server.shutdown()
+		close(done)
+	}()
+
+	var timeout = server.getShutdownTimeout()
+	select {
+	case <-done:
+		server.Logger.Notice("Server stopped.")
+	case <-time.After(timeout):
+		server.Logger.Error("Server did not shut down within", timeout, "- stopping anyway.")
+	}
+
+	server.Logger.Wait()
+	server.closeLogFile()
+	atomic.StoreInt32(&server.isRunning, 0)
+}
+
+// shutdown runs every step of shutting down the server in order.
+func (server *Server) shutdown() {
+	server.disconnectPlayers()
+
+	server.Logger.Info("Disabling plugins...")
+	server.PluginManager.DisablePlugins()
+	server.Scheduler.Close()
+
+	server.Logger.Info("Saving levels...")
+	server.saveLevels()
+
+	if server.Hub != nil {
+		server.Hub.Close()
+	}
+	if recorder := server.NetworkAdapter.GetRecorder(); recorder != nil {
+		recorder.CloseAll()
+	}
+	server.NetworkAdapter.GetTransport().Stop()
+}
+
+// disconnectPlayers disconnects all queued and online players.
+// Online players are transferred to the fallback server of the network if possible,
+// and are kicked with the shutdown message otherwise.
+func (server *Server) disconnectPlayers() {
+	var message = server.getShutdownMessage()
+	if server.JoinQueue != nil {
+		for session, ok := server.JoinQueue.Pop(); ok; session, ok = server.JoinQueue.Pop() {
+			session.Kick(message, false, false)
+		}
+	}
+	if server.transferToFallback() {
+		return
+	}
+	for _, session := range server.SessionManager.GetSessions() {
+		session.Kick(message, false, false)
+	}
+}
+
+// saveLevels saves and unloads all levels of the level manager.
+func (server *Server) saveLevels() {
+	for _, level := range server.LevelManager.GetLevels() {
+		level.Close(false)
+	}
+}
+
+// closeLogFile closes the log file of the server.
+// Messages logged after closing the log file are only written to the other outputs of the logger.
+func (server *Server) closeLogFile() {
+	var file = server.logFile
+	if file == nil {
+		return
+	}
+	server.logFile = nil
+	file.Sync()
+	file.Close()
+}
+
+// getShutdownMessage returns the message players are disconnected with when the server shuts down.
+func (server *Server) getShutdownMessage() string {
+	if server.Config.ShutdownMessage == "" {
+		return DefaultShutdownMessage
+	}
+	return server.Config.ShutdownMessage
+}
+
+// getShutdownTimeout returns the time the server may take to shut down.
+func (server *Server) getShutdownTimeout() time.Duration {
+	var seconds = server.Config.ShutdownTimeout
+	if seconds <= 0 {
+		seconds = DefaultShutdownTimeout
+	}
+	return time.Duration(seconds) * time.Second
+}
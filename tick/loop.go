@@ -0,0 +1,225 @@
+// Package tick implements the tick loop of a server,
+// which runs ticks in a fixed interval and measures the tick rate.
+package tick
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// TicksPerSecond is the amount of ticks run every second by a loop that keeps up.
+	TicksPerSecond = 20
+	// Interval is the interval in which ticks are run.
+	Interval = time.Second / TicksPerSecond
+	// MaximumCatchUp is the maximum amount of ticks a loop runs back to back to catch up after falling behind.
+	// Loops falling further behind skip ticks instead.
+	MaximumCatchUp = 10
+	// OverloadWarningInterval is the minimum time between two calls of the overload function of a loop.
+	OverloadWarningInterval = time.Second * 15
+	// MaximumWindow is the longest window statistics of a loop are kept for.
+	MaximumWindow = time.Minute * 15
+)
+
+// Sample is the measurement of a single tick.
+type Sample struct {
+	Start    time.Time
+	Duration time.Duration
+}
+
+// Stats are the statistics of the ticks run in a window.
+// MSPT is the time in milliseconds a tick took to run.
+type Stats struct {
+	Ticks       int
+	TPS         float64
+	AverageMSPT float64
+	MinimumMSPT float64
+	MaximumMSPT float64
+}
+
+// Loop runs a tick function in a fixed interval, and keeps statistics of the ticks it ran.
+// Loops falling behind run up to MaximumCatchUp ticks back to back to catch up,
+// and skip ticks if they fall any further behind. Skipped ticks are never run,
+// so that every tick counted by the loop corresponds to exactly one call of the tick function.
+type Loop struct {
+	tickFunc     func()
+	interval     time.Duration
+	overloadFunc func(behind time.Duration, skipped int64)
+
+	mutex        sync.RWMutex
+	started      time.Time
+	ticks        int64
+	samples      []Sample
+	first        int
+	count        int
+	skipped      int64
+	lastOverload time.Time
+}
+
+// NewLoop returns a new loop running the tick function every Interval.
+func NewLoop(tickFunc func()) *Loop {
+	return NewLoopWithInterval(tickFunc, Interval)
+}
+
+// NewLoopWithInterval returns a new loop running the tick function in the given interval.
+func NewLoopWithInterval(tickFunc func(), interval time.Duration) *Loop {
+	var size = int(MaximumWindow/interval) + 1
+	return &Loop{tickFunc: tickFunc, interval: interval, samples: make([]Sample, size)}
+}
+
+// SetOverloadFunction sets the function called when the loop skips ticks,
+// with the time the loop was behind and the amount of ticks skipped since the last call.
+// The function is called at most once every OverloadWarningInterval.
+func (loop *Loop) SetOverloadFunction(function func(behind time.Duration, skipped int64)) {
+	loop.overloadFunc = function
+}
+
+// GetInterval returns the interval in which the loop runs ticks.
+func (loop *Loop) GetInterval() time.Duration {
+	return loop.interval
+}
+
+// GetCurrentTick returns the amount of ticks the loop ran.
+func (loop *Loop) GetCurrentTick() int64 {
+	loop.mutex.RLock()
+	defer loop.mutex.RUnlock()
+	return loop.ticks
+}
+
+// GetSkippedTicks returns the amount of ticks the loop skipped because it fell too far behind.
+func (loop *Loop) GetSkippedTicks() int64 {
+	loop.mutex.RLock()
+	defer loop.mutex.RUnlock()
+	return loop.skipped
+}
+
+// Run runs ticks until the context is cancelled, or until running returns false.
+// Running is checked before every tick.
+func (loop *Loop) Run(ctx context.Context, running func() bool) {
+	var next = time.Now()
+	loop.mutex.Lock()
+	loop.started = next
+	loop.mutex.Unlock()
+
+	for running() {
+		if wait := next.Sub(time.Now()); wait > 0 {
+			var timer = time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+		} else if ctx.Err() != nil {
+			return
+		}
+
+		var start = time.Now()
+		loop.tickFunc()
+		loop.record(Sample{start, time.Now().Sub(start)})
+
+		next = next.Add(loop.interval)
+		if behind := time.Now().Sub(next); behind > loop.interval*MaximumCatchUp {
+			var skipped = int64(behind / loop.interval)
+			next = next.Add(time.Duration(skipped) * loop.interval)
+			loop.skip(behind, skipped)
+		}
+	}
+}
+
+// record records the sample of a tick that was run.
+func (loop *Loop) record(sample Sample) {
+	loop.mutex.Lock()
+	defer loop.mutex.Unlock()
+	loop.ticks++
+	if loop.count < len(loop.samples) {
+		loop.samples[(loop.first+loop.count)%len(loop.samples)] = sample
+		loop.count++
+		return
+	}
+	loop.samples[loop.first] = sample
+	loop.first = (loop.first + 1) % len(loop.samples)
+}
+
+// skip records skipped ticks, and calls the overload function if it was not called recently.
+func (loop *Loop) skip(behind time.Duration, skipped int64) {
+	loop.mutex.Lock()
+	loop.skipped += skipped
+	var now = time.Now()
+	if loop.overloadFunc == nil || now.Sub(loop.lastOverload) < OverloadWarningInterval {
+		loop.mutex.Unlock()
+		return
+	}
+	var total = loop.skipped
+	loop.skipped = 0
+	loop.lastOverload = now
+	loop.mutex.Unlock()
+
+	loop.overloadFunc(behind, total)
+}
+
+// GetStats returns the statistics of the ticks run within the given window until now.
+// Windows longer than MaximumWindow are shortened to MaximumWindow.
+func (loop *Loop) GetStats(window time.Duration) Stats {
+	return loop.getStats(time.Now(), window)
+}
+
+// GetTPS returns the average amount of ticks per second run within the given window.
+func (loop *Loop) GetTPS(window time.Duration) float64 {
+	return loop.GetStats(window).TPS
+}
+
+// GetMSPT returns the average time in milliseconds ticks took to run within the given window.
+func (loop *Loop) GetMSPT(window time.Duration) float64 {
+	return loop.GetStats(window).AverageMSPT
+}
+
+// getStats returns the statistics of the ticks run within the window until the given time.
+func (loop *Loop) getStats(now time.Time, window time.Duration) Stats {
+	if window > MaximumWindow {
+		window = MaximumWindow
+	}
+	loop.mutex.RLock()
+	defer loop.mutex.RUnlock()
+
+	var stats = Stats{}
+	var from = now.Add(-window)
+	var total time.Duration
+	for i := loop.count - 1; i >= 0; i-- {
+		var sample = loop.samples[(loop.first+i)%len(loop.samples)]
+		if sample.Start.Before(from) {
+			break
+		}
+		var mspt = milliseconds(sample.Duration)
+		if stats.Ticks == 0 || mspt < stats.MinimumMSPT {
+			stats.MinimumMSPT = mspt
+		}
+		if mspt > stats.MaximumMSPT {
+			stats.MaximumMSPT = mspt
+		}
+		total += sample.Duration
+		stats.Ticks++
+	}
+	if stats.Ticks == 0 {
+		return stats
+	}
+	stats.AverageMSPT = milliseconds(total) / float64(stats.Ticks)
+
+	if loop.started.After(from) {
+		from = loop.started
+	}
+	var maximum = float64(time.Second) / float64(loop.interval)
+	if elapsed := now.Sub(from).Seconds(); elapsed > 0 {
+		stats.TPS = float64(stats.Ticks) / elapsed
+	}
+	if stats.TPS > maximum || stats.TPS == 0 {
+		stats.TPS = maximum
+	}
+	return stats
+}
+
+// milliseconds returns the duration in fractional milliseconds.
+func milliseconds(duration time.Duration) float64 {
+	return float64(duration) / float64(time.Millisecond)
+}
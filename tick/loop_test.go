@@ -0,0 +1,98 @@
+package tick
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestStats(t *testing.T) {
+	var loop = NewLoop(func() {})
+	var start = time.Unix(1000, 0)
+	loop.started = start
+
+	// Ten seconds of ticks at 10 TPS taking 10ms each, followed by five seconds at 20 TPS taking 2ms each.
+	var now = start
+	for i := 0; i < 100; i++ {
+		loop.record(Sample{now, time.Millisecond * 10})
+		now = now.Add(time.Second / 10)
+	}
+	for i := 0; i < 100; i++ {
+		loop.record(Sample{now, time.Millisecond * 2})
+		now = now.Add(Interval)
+	}
+
+	var recent = loop.getStats(now, time.Second*5)
+	if recent.Ticks != 100 || !approximately(recent.TPS, 20) || !approximately(recent.AverageMSPT, 2) {
+		t.Errorf("unexpected stats of the last 5 seconds: %+v", recent)
+	}
+	var all = loop.getStats(now, time.Minute)
+	if all.Ticks != 200 || !approximately(all.TPS, 200.0/15) || !approximately(all.AverageMSPT, 6) {
+		t.Errorf("unexpected stats of the last minute: %+v", all)
+	}
+	if all.MinimumMSPT != 2 || all.MaximumMSPT != 10 {
+		t.Errorf("expected MSPT between 2 and 10, got %v and %v", all.MinimumMSPT, all.MaximumMSPT)
+	}
+	if loop.GetCurrentTick() != 200 {
+		t.Errorf("expected tick 200, got %v", loop.GetCurrentTick())
+	}
+}
+
+func TestSamplesWrapAround(t *testing.T) {
+	var loop = NewLoopWithInterval(func() {}, time.Minute)
+	var now = time.Unix(1000, 0)
+	loop.started = now
+	for i := 0; i < len(loop.samples)*2; i++ {
+		loop.record(Sample{now, time.Duration(i) * time.Millisecond})
+		now = now.Add(time.Minute)
+	}
+	var stats = loop.getStats(now, MaximumWindow)
+	if stats.Ticks != 15 {
+		t.Errorf("expected 15 ticks in the window, got %v", stats.Ticks)
+	}
+	if stats.MaximumMSPT != float64(len(loop.samples)*2-1) {
+		t.Errorf("expected the latest sample to be kept, got maximum %v", stats.MaximumMSPT)
+	}
+}
+
+func TestRunSkipsTicks(t *testing.T) {
+	var ticks = 0
+	var loop = NewLoopWithInterval(func() {
+		ticks++
+		if ticks == 1 {
+			time.Sleep(time.Millisecond * 50)
+		}
+	}, time.Millisecond)
+
+	var behind time.Duration
+	var skipped int64
+	loop.SetOverloadFunction(func(b time.Duration, s int64) {
+		behind, skipped = b, s
+	})
+	loop.Run(context.Background(), func() bool {
+		return ticks < 5
+	})
+
+	if skipped == 0 || behind <= time.Millisecond*MaximumCatchUp {
+		t.Errorf("expected ticks to be skipped after falling behind, got %v skipped %v behind", skipped, behind)
+	}
+	if loop.GetCurrentTick() != 5 {
+		t.Errorf("expected 5 ticks to be run, got %v", loop.GetCurrentTick())
+	}
+}
+
+func TestRunStopsOnCancel(t *testing.T) {
+	var ctx, cancel = context.WithCancel(context.Background())
+	var loop = NewLoopWithInterval(cancel, time.Millisecond)
+	loop.Run(ctx, func() bool {
+		return true
+	})
+	if loop.GetCurrentTick() != 1 {
+		t.Errorf("expected the loop to stop after 1 tick, got %v", loop.GetCurrentTick())
+	}
+}
+
+func approximately(a, b float64) bool {
+	return math.Abs(a-b) < 0.01
+}
@@ -0,0 +1,103 @@
+package timings
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Report is a report of the records measured between its start and end time.
+// Records are sorted by the total time spent on them, descending.
+type Report struct {
+	Start   time.Time
+	End     time.Time
+	Records []Record
+}
+
+// GetTotal returns the total time spent on all records of the given category.
+func (report Report) GetTotal(category Category) time.Duration {
+	var total time.Duration
+	for _, record := range report.Records {
+		if record.Category == category {
+			total += record.Total
+		}
+	}
+	return total
+}
+
+// GetTop returns the records of the report with the highest total time, excluding full server ticks.
+// At most n records are returned.
+func (report Report) GetTop(n int) []Record {
+	var records []Record
+	for _, record := range report.Records {
+		if len(records) == n {
+			break
+		}
+		if record.Category != ServerTick {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// WriteTo writes the report in a human readable format to the writer.
+func (report Report) WriteTo(writer io.Writer) (int64, error) {
+	var w = &countingWriter{writer: writer}
+	var elapsed = report.End.Sub(report.Start)
+	fmt.Fprintf(w, "Timings from %v to %v (%v)\n", report.Start.Format(time.RFC3339), report.End.Format(time.RFC3339), elapsed.Round(time.Second))
+	for _, category := range []Category{ServerTick, PacketHandler, SessionTick, LevelTick, Task} {
+		fmt.Fprintf(w, "\n%v: %v total\n", category, report.GetTotal(category).Round(time.Millisecond))
+		for _, record := range report.Records {
+			if record.Category != category {
+				continue
+			}
+			fmt.Fprintf(w, "  %v: count %v, total %v, avg %v, min %v, max %v", record.Name, record.Count, record.Total, record.GetAverage(), record.Minimum, record.Maximum)
+			if elapsed > 0 {
+				fmt.Fprintf(w, ", %.2f%% of time", float64(record.Total)/float64(elapsed)*100)
+			}
+			fmt.Fprintln(w)
+		}
+	}
+	return w.written, w.err
+}
+
+// Save writes the report to a new file in the given directory, named after the end time of the report.
+// The path of the file written is returned.
+func (report Report) Save(directory string) (string, error) {
+	if err := os.MkdirAll(directory, 0700); err != nil {
+		return "", err
+	}
+	var path = filepath.Join(directory, "timings-"+report.End.Format("20060102-150405")+".txt")
+	file, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	var writer = bufio.NewWriter(file)
+	if _, err := report.WriteTo(writer); err != nil {
+		return "", err
+	}
+	return path, writer.Flush()
+}
+
+// countingWriter is a writer counting the bytes written, and keeping the first error that occurred.
+type countingWriter struct {
+	writer  io.Writer
+	written int64
+	err     error
+}
+
+// Write writes the bytes to the underlying writer, unless an error occurred before.
+func (w *countingWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	var n, err = w.writer.Write(p)
+	w.written += int64(n)
+	w.err = err
+	return n, err
+}
@@ -0,0 +1,213 @@
+// Package timings implements a profiler attributing the time spent on the main tick
+// to packet handlers, session ticks, level ticks and scheduled tasks.
+package timings
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Category is the category of the work a record was timed for.
+type Category string
+
+const (
+	// ServerTick is the category of full ticks of the server.
+	ServerTick Category = "Server Tick"
+	// PacketHandler is the category of packet handlers, named by the packet and the plugin that registered the handler.
+	PacketHandler Category = "Packet Handler"
+	// SessionTick is the category of ticks of sessions.
+	SessionTick Category = "Session Tick"
+	// LevelTick is the category of ticks of levels, named by the level.
+	LevelTick Category = "Level Tick"
+	// Task is the category of scheduled tasks, named by the plugin that scheduled the task.
+	Task Category = "Scheduler Task"
+)
+
+// DefaultWindow is the default window records are aggregated over.
+const DefaultWindow = time.Minute * 5
+
+// Record is the aggregated time spent on a single kind of work.
+type Record struct {
+	Category Category
+	Name     string
+	Count    int64
+	Total    time.Duration
+	Minimum  time.Duration
+	Maximum  time.Duration
+}
+
+// GetAverage returns the average time spent on the work.
+func (record Record) GetAverage() time.Duration {
+	if record.Count == 0 {
+		return 0
+	}
+	return record.Total / time.Duration(record.Count)
+}
+
+// add adds a single measurement to the record.
+func (record *Record) add(duration time.Duration) {
+	if record.Count == 0 || duration < record.Minimum {
+		record.Minimum = duration
+	}
+	if duration > record.Maximum {
+		record.Maximum = duration
+	}
+	record.Total += duration
+	record.Count++
+}
+
+// merge merges another record of the same work into the record.
+func (record *Record) merge(other Record) {
+	if other.Count == 0 {
+		return
+	}
+	if record.Count == 0 || other.Minimum < record.Minimum {
+		record.Minimum = other.Minimum
+	}
+	if other.Maximum > record.Maximum {
+		record.Maximum = other.Maximum
+	}
+	record.Total += other.Total
+	record.Count += other.Count
+}
+
+// key is the key records are stored under.
+type key struct {
+	category Category
+	name     string
+}
+
+// window holds the records of a single window of time.
+type window struct {
+	start   time.Time
+	records map[key]*Record
+}
+
+// newWindow returns a new empty window starting at the given time.
+func newWindow(start time.Time) *window {
+	return &window{start, make(map[key]*Record)}
+}
+
+// Timings measures the time spent on work while enabled.
+// Records are aggregated per window: reports cover the current window and the window before it.
+// Start, Stop and IsEnabled are safe to call on a nil *Timings, in which case nothing is measured.
+type Timings struct {
+	mutex    sync.Mutex
+	enabled  bool
+	length   time.Duration
+	current  *window
+	previous *window
+}
+
+// New returns new disabled timings, aggregating records over windows of the given length.
+func New(length time.Duration) *Timings {
+	return &Timings{length: length}
+}
+
+// IsEnabled checks if the timings are measuring.
+func (timings *Timings) IsEnabled() bool {
+	if timings == nil {
+		return false
+	}
+	timings.mutex.Lock()
+	defer timings.mutex.Unlock()
+	return timings.enabled
+}
+
+// Enable starts measuring, discarding all previous records.
+func (timings *Timings) Enable() {
+	timings.mutex.Lock()
+	defer timings.mutex.Unlock()
+	timings.enabled = true
+	timings.current = newWindow(time.Now())
+	timings.previous = nil
+}
+
+// Disable stops measuring. The records measured so far are kept for reporting.
+func (timings *Timings) Disable() {
+	timings.mutex.Lock()
+	defer timings.mutex.Unlock()
+	timings.enabled = false
+}
+
+// Start returns the time work started at, which should be passed to Stop once the work is done.
+// The zero time is returned if the timings are disabled.
+func (timings *Timings) Start() time.Time {
+	if !timings.IsEnabled() {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// Stop records the time spent on the work since the start time returned by Start.
+// Nothing is recorded if the start time is the zero time.
+func (timings *Timings) Stop(category Category, name string, start time.Time) {
+	if timings == nil || start.IsZero() {
+		return
+	}
+	var now = time.Now()
+	timings.mutex.Lock()
+	defer timings.mutex.Unlock()
+	if !timings.enabled {
+		return
+	}
+	timings.rotate(now)
+	var k = key{category, name}
+	var record, ok = timings.current.records[k]
+	if !ok {
+		record = &Record{Category: category, Name: name}
+		timings.current.records[k] = record
+	}
+	record.add(now.Sub(start))
+}
+
+// rotate starts a new window if the current window has ended at the given time.
+func (timings *Timings) rotate(now time.Time) {
+	if now.Sub(timings.current.start) < timings.length {
+		return
+	}
+	timings.previous = timings.current
+	timings.current = newWindow(now)
+}
+
+// GetReport returns the report of all records of the current and previous window.
+func (timings *Timings) GetReport() Report {
+	var now = time.Now()
+	timings.mutex.Lock()
+	defer timings.mutex.Unlock()
+
+	var report = Report{End: now}
+	if timings.current == nil {
+		report.Start = now
+		return report
+	}
+	if timings.enabled {
+		timings.rotate(now)
+	}
+
+	var merged = make(map[key]*Record)
+	for _, w := range []*window{timings.previous, timings.current} {
+		if w == nil {
+			continue
+		}
+		if report.Start.IsZero() {
+			report.Start = w.start
+		}
+		for k, record := range w.records {
+			if existing, ok := merged[k]; ok {
+				existing.merge(*record)
+				continue
+			}
+			var copied = *record
+			merged[k] = &copied
+		}
+	}
+	for _, record := range merged {
+		report.Records = append(report.Records, *record)
+	}
+	sort.Slice(report.Records, func(i, j int) bool {
+		return report.Records[i].Total > report.Records[j].Total
+	})
+	return report
+}
@@ -0,0 +1,82 @@
+package timings
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDisabled(t *testing.T) {
+	var timings = New(DefaultWindow)
+	var start = timings.Start()
+	if !start.IsZero() {
+		t.Fatal("expected a zero start time while disabled")
+	}
+	timings.Stop(Task, "test", time.Now())
+	if len(timings.GetReport().Records) != 0 {
+		t.Error("expected no records while disabled")
+	}
+
+	var none *Timings
+	none.Stop(Task, "test", none.Start())
+}
+
+func TestRecords(t *testing.T) {
+	var timings = New(DefaultWindow)
+	timings.Enable()
+
+	var now = time.Now()
+	for _, duration := range []time.Duration{time.Millisecond, time.Millisecond * 3, time.Millisecond * 5} {
+		timings.Stop(LevelTick, "world", now.Add(-duration))
+	}
+	timings.Stop(PacketHandler, "LoginPacket (0x01) by GoMine", now.Add(-time.Millisecond*20))
+
+	var report = timings.GetReport()
+	if len(report.Records) != 2 {
+		t.Fatalf("expected 2 records, got %v", len(report.Records))
+	}
+	var handler, level = report.Records[0], report.Records[1]
+	if handler.Category != PacketHandler || level.Category != LevelTick {
+		t.Fatalf("expected records sorted by total time, got %v and %v", handler.Category, level.Category)
+	}
+	if level.Count != 3 || level.Minimum < time.Millisecond || level.Maximum < time.Millisecond*5 || level.Maximum > time.Millisecond*6 {
+		t.Errorf("unexpected level tick record: %+v", level)
+	}
+	if average := level.GetAverage(); average < time.Millisecond*3 || average > time.Millisecond*4 {
+		t.Errorf("unexpected average %v", average)
+	}
+
+	var buffer = &bytes.Buffer{}
+	if _, err := report.WriteTo(buffer); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buffer.String(), "LoginPacket (0x01) by GoMine") {
+		t.Errorf("report is missing the packet handler record:\n%v", buffer.String())
+	}
+
+	timings.Enable()
+	if len(timings.GetReport().Records) != 0 {
+		t.Error("expected enabling to discard previous records")
+	}
+}
+
+func TestWindows(t *testing.T) {
+	var timings = New(time.Minute)
+	timings.Enable()
+	timings.Stop(Task, "old", time.Now())
+	timings.current.start = time.Now().Add(-time.Minute * 2)
+	timings.Stop(Task, "new", time.Now())
+	timings.current.start = time.Now().Add(-time.Minute * 2)
+	timings.Stop(Task, "newest", time.Now())
+
+	var report = timings.GetReport()
+	if len(report.Records) != 2 {
+		t.Fatalf("expected the oldest window to be dropped, got %+v", report.Records)
+	}
+	for _, record := range report.Records {
+		if record.Name == "old" {
+			t.Error("expected the oldest window to be dropped")
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"crypto/cipher"
+)
+
+// registerBlocks is the amount of blocks the shift register of a CFB8 stream can hold.
+// The register only gets moved back to the start once it is full,
+// which avoids copying the IV for every single byte.
+const registerBlocks = 64
+
+// CFB8 is a stateful AES-CFB8 stream used for encrypting and decrypting batches.
+// Unlike the CFB mode of the standard library, which shifts a full block at a time,
+// CFB8 shifts the IV by a single byte for every byte processed.
+// A CFB8 stream keeps its state between calls, and should be reused for every batch.
+type CFB8 struct {
+	block     cipher.Block
+	register  []byte
+	offset    int
+	output    []byte
+	decrypter bool
+}
+
+// NewCFB8Encrypter returns a new CFB8 stream encrypting with the given block and IV.
+// The IV must have the same length as the block size of the block.
+func NewCFB8Encrypter(block cipher.Block, iv []byte) *CFB8 {
+	return newCFB8(block, iv, false)
+}
+
+// NewCFB8Decrypter returns a new CFB8 stream decrypting with the given block and IV.
+// The IV must have the same length as the block size of the block.
+func NewCFB8Decrypter(block cipher.Block, iv []byte) *CFB8 {
+	return newCFB8(block, iv, true)
+}
+
+// newCFB8 returns a new CFB8 stream with the given block and IV.
+func newCFB8(block cipher.Block, iv []byte, decrypter bool) *CFB8 {
+	var blockSize = block.BlockSize()
+	if len(iv) != blockSize {
+		panic("utils: IV length must equal block size")
+	}
+	var stream = &CFB8{block: block, register: make([]byte, blockSize*registerBlocks), output: make([]byte, blockSize), decrypter: decrypter}
+	copy(stream.register, iv)
+	return stream
+}
+
+// XORKeyStream XORs every byte of src with the key stream, and writes the result to dst.
+// Dst and src may overlap entirely, but dst must be at least as long as src.
+func (stream *CFB8) XORKeyStream(dst, src []byte) {
+	if len(dst) < len(src) {
+		panic("utils: output smaller than input")
+	}
+	var blockSize = stream.block.BlockSize()
+	for i, b := range src {
+		stream.block.Encrypt(stream.output, stream.register[stream.offset:stream.offset+blockSize])
+		var c = b ^ stream.output[0]
+		dst[i] = c
+
+		if stream.decrypter {
+			stream.shift(b, blockSize)
+		} else {
+			stream.shift(c, blockSize)
+		}
+	}
+}
+
+// shift shifts the IV in the register by one byte, appending the given cipher text byte.
+func (stream *CFB8) shift(b byte, blockSize int) {
+	if stream.offset+blockSize == len(stream.register) {
+		copy(stream.register, stream.register[stream.offset:])
+		stream.offset = 0
+	}
+	stream.register[stream.offset+blockSize] = b
+	stream.offset++
+}
@@ -0,0 +1,151 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"testing"
+)
+
+// NIST SP 800-38A, F.3.7 CFB8-AES128.Encrypt
+var cfb8Vector = struct {
+	key, iv, plainText, cipherText string
+}{
+	"2b7e151628aed2a6abf7158809cf4f3c",
+	"000102030405060708090a0b0c0d0e0f",
+	"6bc1bee22e409f96e93d7e117393172aae2d",
+	"3b79424c9c0dd436bace9e0ed4586a4f32b9",
+}
+
+func decodeHex(t testing.TB, s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestCFB8Vector(t *testing.T) {
+	block, err := aes.NewCipher(decodeHex(t, cfb8Vector.key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var plainText = decodeHex(t, cfb8Vector.plainText)
+	var cipherText = decodeHex(t, cfb8Vector.cipherText)
+
+	var encrypted = make([]byte, len(plainText))
+	NewCFB8Encrypter(block, decodeHex(t, cfb8Vector.iv)).XORKeyStream(encrypted, plainText)
+	if !bytes.Equal(encrypted, cipherText) {
+		t.Errorf("encryption mismatch: got %x, expected %x", encrypted, cipherText)
+	}
+
+	var decrypted = make([]byte, len(cipherText))
+	NewCFB8Decrypter(block, decodeHex(t, cfb8Vector.iv)).XORKeyStream(decrypted, cipherText)
+	if !bytes.Equal(decrypted, plainText) {
+		t.Errorf("decryption mismatch: got %x, expected %x", decrypted, plainText)
+	}
+}
+
+func TestCFB8Stateful(t *testing.T) {
+	var key = make([]byte, 32)
+	rand.Read(key)
+	block, _ := aes.NewCipher(key)
+	var iv = key[:aes.BlockSize]
+
+	// Multiple batches through the same stream must equal a single large batch,
+	// including batches crossing the end of the shift register.
+	var data = make([]byte, aes.BlockSize*registerBlocks*3+7)
+	rand.Read(data)
+
+	var whole = make([]byte, len(data))
+	NewCFB8Encrypter(block, iv).XORKeyStream(whole, data)
+
+	var parts = append([]byte{}, data...)
+	var encrypter = NewCFB8Encrypter(block, iv)
+	for offset, size := 0, 1; offset < len(parts); offset, size = offset+size, size*2+1 {
+		var end = offset + size
+		if end > len(parts) {
+			end = len(parts)
+		}
+		encrypter.XORKeyStream(parts[offset:end], parts[offset:end])
+	}
+	if !bytes.Equal(whole, parts) {
+		t.Fatal("stateful encryption over several calls does not equal a single call")
+	}
+
+	var decrypter = NewCFB8Decrypter(block, iv)
+	decrypter.XORKeyStream(parts[:100], parts[:100])
+	decrypter.XORKeyStream(parts[100:], parts[100:])
+	if !bytes.Equal(data, parts) {
+		t.Fatal("decrypted data does not equal original data")
+	}
+}
+
+func TestChecksumVerification(t *testing.T) {
+	var data = &EncryptionData{}
+	rand.Read(data.EncryptSecretKeyBytes[:])
+	data.DecryptSecretKeyBytes = data.EncryptSecretKeyBytes
+
+	var sender, receiver = &EncryptionHandler{&EncryptionData{}}, &EncryptionHandler{&EncryptionData{}}
+	*sender.Data, *receiver.Data = *data, *data
+
+	for i := 0; i < 3; i++ {
+		var payload = []byte("batch payload")
+		var received = append(append([]byte{}, payload...), sender.ComputeSendChecksum(payload)...)
+
+		verified, err := receiver.VerifyReceiveChecksum(received)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(verified, payload) {
+			t.Fatalf("verified payload %q does not equal %q", verified, payload)
+		}
+	}
+
+	var tampered = append([]byte("batch payload"), sender.ComputeSendChecksum([]byte("another payload"))...)
+	if _, err := receiver.VerifyReceiveChecksum(tampered); err != InvalidChecksum {
+		t.Fatalf("expected InvalidChecksum, got %v", err)
+	}
+	if _, err := receiver.VerifyReceiveChecksum([]byte{0x01}); err != InvalidChecksum {
+		t.Fatalf("expected InvalidChecksum for short data, got %v", err)
+	}
+}
+
+func benchmarkData(b *testing.B) (cipher.Block, []byte, []byte) {
+	var key = make([]byte, 32)
+	rand.Read(key)
+	block, _ := aes.NewCipher(key)
+
+	var data = make([]byte, 64*1024)
+	rand.Read(data)
+	b.SetBytes(int64(len(data)))
+	return block, key[:aes.BlockSize], data
+}
+
+func BenchmarkCFB8(b *testing.B) {
+	var block, iv, data = benchmarkData(b)
+	var stream = NewCFB8Encrypter(block, iv)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		stream.XORKeyStream(data, data)
+	}
+}
+
+// BenchmarkCFB8PerByteCipher benchmarks the previous implementation,
+// which created a new CFB encrypter and IV slice for every byte.
+func BenchmarkCFB8PerByteCipher(b *testing.B) {
+	var block, iv, data = benchmarkData(b)
+	iv = append([]byte{}, iv...)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for j := range data {
+			var cfb = cipher.NewCFBEncrypter(block, iv)
+			cfb.XORKeyStream(data[j:j+1], data[j:j+1])
+			iv = append(iv[1:], data[j])
+		}
+	}
+}
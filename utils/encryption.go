@@ -5,9 +5,17 @@ import (
 	"crypto/cipher"
 	"crypto/ecdsa"
 	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
 	"github.com/irmine/binutils"
 )
 
+// InvalidChecksum gets returned if the checksum of a received batch did not match the computed checksum.
+var InvalidChecksum = errors.New("invalid batch checksum")
+
+// ChecksumLength is the length of the checksum appended to every encrypted batch.
+const ChecksumLength = 8
+
 type EncryptionData struct {
 	ClientPublicKey       *ecdsa.PublicKey
 	ServerPrivateKey      *ecdsa.PrivateKey
@@ -16,12 +24,13 @@ type EncryptionData struct {
 	DecryptSecretKeyBytes [32]byte
 	EncryptSecretKeyBytes [32]byte
 
-	DecryptIV     []byte
-	EncryptIV     []byte
 	DecryptCipher cipher.Block
 	EncryptCipher cipher.Block
+	DecryptStream cipher.Stream
+	EncryptStream cipher.Stream
 
-	SendCounter int64
+	SendCounter    int64
+	ReceiveCounter int64
 }
 
 func (data *EncryptionData) ComputeSharedSecret() {
@@ -37,8 +46,8 @@ func (data *EncryptionData) ComputeSecretKeyBytes() {
 	data.DecryptCipher, _ = aes.NewCipher(data.DecryptSecretKeyBytes[:])
 	data.EncryptCipher, _ = aes.NewCipher(data.EncryptSecretKeyBytes[:])
 
-	data.DecryptIV = data.DecryptSecretKeyBytes[:aes.BlockSize]
-	data.EncryptIV = data.DecryptSecretKeyBytes[:aes.BlockSize]
+	data.DecryptStream = NewCFB8Decrypter(data.DecryptCipher, data.DecryptSecretKeyBytes[:aes.BlockSize])
+	data.EncryptStream = NewCFB8Encrypter(data.EncryptCipher, data.EncryptSecretKeyBytes[:aes.BlockSize])
 }
 
 type EncryptionHandler struct {
@@ -62,5 +71,36 @@ func (handler *EncryptionHandler) ComputeSendChecksum(d []byte) []byte {
 	hash.Write(secret)
 
 	var sum = hash.Sum(nil)
-	return sum[:8]
+	return sum[:ChecksumLength]
+}
+
+// ComputeReceiveChecksum computes the checksum of decrypted data received from the client.
+// Every call increments the receive counter, so it must be called once for every received batch.
+func (handler *EncryptionHandler) ComputeReceiveChecksum(d []byte) []byte {
+	var buffer []byte
+	var secret = handler.Data.DecryptSecretKeyBytes[:]
+
+	binutils.WriteLittleLong(&buffer, handler.Data.ReceiveCounter)
+	handler.Data.ReceiveCounter++
+
+	var hash = sha256.New()
+	hash.Write(buffer)
+	hash.Write(d)
+	hash.Write(secret)
+
+	var sum = hash.Sum(nil)
+	return sum[:ChecksumLength]
+}
+
+// VerifyReceiveChecksum verifies the checksum at the end of the decrypted data.
+// The data without the checksum is returned, or InvalidChecksum if the checksum did not match.
+func (handler *EncryptionHandler) VerifyReceiveChecksum(d []byte) ([]byte, error) {
+	if len(d) < ChecksumLength {
+		return nil, InvalidChecksum
+	}
+	var payload, checksum = d[:len(d)-ChecksumLength], d[len(d)-ChecksumLength:]
+	if subtle.ConstantTimeCompare(checksum, handler.ComputeReceiveChecksum(payload)) != 1 {
+		return nil, InvalidChecksum
+	}
+	return payload, nil
 }
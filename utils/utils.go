@@ -20,14 +20,25 @@ type EncryptionPayload struct {
 	Token string `json:"salt"`
 }
 
-func DecodeJwtPayload(v string, t interface{}) {
-	v = strings.Split(v, ".")[1]
-	str, err := base64.RawURLEncoding.DecodeString(v)
+// DecodeJwtPayload decodes the JSON payload of the JWT into t.
+// An error is returned if the JWT or its payload is malformed.
+func DecodeJwtPayload(v string, t interface{}) error {
+	var parts = strings.Split(v, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("jwt has %v parts, expected 3", len(parts))
+	}
+	str, err := base64.RawURLEncoding.DecodeString(parts[1])
 	if err != nil {
-		fmt.Println(err)
-		return
+		return err
+	}
+	// Fields of a different type than expected are left empty,
+	// as clients are not consistent in the types of some fields.
+	if err := json.Unmarshal(str, t); err != nil {
+		if _, ok := err.(*json.UnmarshalTypeError); !ok {
+			return err
+		}
 	}
-	json.Unmarshal(str, t)
+	return nil
 }
 
 func DecodeJwt(v string) []string {
@@ -46,16 +57,24 @@ func DecodeJwt(v string) []string {
 	return jwt
 }
 
+// ConstructEncryptionJwt constructs the JWT sent in the server handshake,
+// containing the public key of the server and the token used as salt.
 func ConstructEncryptionJwt(key *ecdsa.PrivateKey, token []byte) string {
+	var payload = EncryptionPayload{}
+	payload.Token = base64.RawStdEncoding.EncodeToString(token)
+
+	return ConstructJwt(key, payload)
+}
+
+// ConstructJwt constructs an ES384 JWT with the given payload, signed by the given private key.
+// The public key of the private key is put in the x5u field of the header.
+func ConstructJwt(key *ecdsa.PrivateKey, payload interface{}) string {
 	var header = EncryptionHeader{}
 	header.Algorithm = "ES384"
 	var b, _ = x509.MarshalPKIXPublicKey(&key.PublicKey)
 
 	header.X5u = base64.RawStdEncoding.EncodeToString(b)
 
-	var payload = EncryptionPayload{}
-	payload.Token = base64.RawStdEncoding.EncodeToString(token)
-
 	var headerData, _ = json.Marshal(header)
 	var headerStr = base64.RawURLEncoding.EncodeToString(headerData)
 	var payloadData, _ = json.Marshal(payload)
@@ -69,7 +88,13 @@ func ConstructEncryptionJwt(key *ecdsa.PrivateKey, token []byte) string {
 		fmt.Println(err)
 	}
 
-	var signature = base64.RawURLEncoding.EncodeToString(append(r.Bytes(), s.Bytes()...))
+	// R and S are padded to the size of the curve, so that the signature can be split in half.
+	var size = (key.Curve.Params().BitSize + 7) / 8
+	var sig = make([]byte, size*2)
+	r.FillBytes(sig[:size])
+	s.FillBytes(sig[size:])
+
+	var signature = base64.RawURLEncoding.EncodeToString(sig)
 
 	return headerStr + "." + payloadStr + "." + signature
 }